@@ -0,0 +1,93 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// AnyPolicy selects how values decoded into fields of type any (interface{}) are
+// normalized. By default the raw input value is assigned as-is.
+// Policies combine with |; normalization always builds new maps and slices,
+// so the input is never modified.
+type AnyPolicy uint8
+
+const (
+	// AnyCopy deep-copies map[string]any and []any values so the decoded struct
+	// shares no containers with the input.
+	AnyCopy AnyPolicy = 1 << iota
+
+	// AnyNumbers converts json.Number values to int64 when integral, float64 otherwise.
+	AnyNumbers
+
+	// AnyMapSlices converts []any values whose elements are all map[string]any
+	// into []map[string]any.
+	AnyMapSlices
+)
+
+// WithAnyPolicy sets the normalization applied to values decoded into fields of type any.
+func WithAnyPolicy(policy AnyPolicy) Option {
+	return func(u *Unmarshaler) {
+		u.anyPolicy = policy
+	}
+}
+
+// isAnyType reports whether typ is the empty interface.
+func isAnyType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Interface && typ.NumMethod() == 0
+}
+
+// normalizeAny applies policy to value, recursing into maps and slices.
+func normalizeAny(value any, policy AnyPolicy) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+
+		result := make(map[string]any, len(v))
+		for key, elem := range v {
+			result[key] = normalizeAny(elem, policy)
+		}
+
+		return result
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		result := make([]any, len(v))
+		allMaps := len(v) > 0
+		for i, elem := range v {
+			result[i] = normalizeAny(elem, policy)
+			if _, ok := result[i].(map[string]any); !ok {
+				allMaps = false
+			}
+		}
+
+		if allMaps && policy&AnyMapSlices != 0 {
+			maps := make([]map[string]any, len(result))
+			for i, elem := range result {
+				//nolint:forcetypeassert // Checked above
+				maps[i] = elem.(map[string]any)
+			}
+
+			return maps
+		}
+
+		return result
+	case json.Number:
+		if policy&AnyNumbers == 0 {
+			return v
+		}
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,93 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAny(t *testing.T) {
+	input := map[string]any{
+		"count": json.Number("42"),
+		"ratio": json.Number("0.5"),
+		"items": []any{map[string]any{"id": json.Number("1")}, map[string]any{"id": json.Number("2")}},
+		"mixed": []any{map[string]any{}, "x"},
+		"empty": []any{},
+	}
+
+	t.Run("copy only", func(t *testing.T) {
+		got := normalizeAny(input, AnyCopy)
+		assert.Equal(t, input, got)
+
+		//nolint:forcetypeassert // Test code
+		got.(map[string]any)["count"] = 1
+		assert.Equal(t, json.Number("42"), input["count"], "input must not share containers")
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		//nolint:forcetypeassert // Test code
+		got := normalizeAny(input, AnyNumbers).(map[string]any)
+		assert.Equal(t, int64(42), got["count"])
+		assert.InDelta(t, 0.5, got["ratio"], 1e-9)
+		assert.Equal(t, []any{map[string]any{"id": int64(1)}, map[string]any{"id": int64(2)}}, got["items"])
+		assert.Equal(t, json.Number("42"), input["count"], "input must not be modified")
+	})
+
+	t.Run("map slices", func(t *testing.T) {
+		//nolint:forcetypeassert // Test code
+		got := normalizeAny(input, AnyMapSlices).(map[string]any)
+		assert.Equal(t, []map[string]any{{"id": json.Number("1")}, {"id": json.Number("2")}}, got["items"])
+		assert.Equal(t, []any{map[string]any{}, "x"}, got["mixed"])
+		assert.Equal(t, []any{}, got["empty"])
+	})
+
+	t.Run("invalid number kept", func(t *testing.T) {
+		assert.Equal(t, json.Number("abc"), normalizeAny(json.Number("abc"), AnyNumbers))
+	})
+}
+
+func TestUnmarshaler_Unmarshal_AnyPolicy(t *testing.T) {
+	type Event struct {
+		Payload any   `schema:"payload"`
+		Count   any   `schema:"count"`
+		Extra   *any  `schema:"extra"`
+		Tags    []any `schema:"tags"`
+	}
+
+	payload := map[string]any{"items": []any{map[string]any{"n": json.Number("1")}}}
+	data := map[string]any{
+		"payload": payload,
+		"count":   json.Number("7"),
+		"extra":   json.Number("1.5"),
+		"tags":    []any{json.Number("3")},
+	}
+
+	t.Run("default assigns as-is", func(t *testing.T) {
+		var result Event
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(data, &result))
+		assert.Equal(t, json.Number("7"), result.Count)
+		//nolint:forcetypeassert // Test code
+		result.Payload.(map[string]any)["mutated"] = true
+		assert.Contains(t, payload, "mutated", "default shares the input map")
+		delete(payload, "mutated")
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		u := NewDefaultUnmarshaler(WithAnyPolicy(AnyCopy | AnyNumbers | AnyMapSlices))
+
+		var result Event
+		require.NoError(t, u.Unmarshal(data, &result))
+
+		assert.Equal(t, int64(7), result.Count)
+		require.NotNil(t, result.Extra)
+		assert.InDelta(t, 1.5, *result.Extra, 1e-9)
+		assert.Equal(t, map[string]any{"items": []map[string]any{{"n": int64(1)}}}, result.Payload)
+		assert.Equal(t, []any{json.Number("3")}, result.Tags, "only fields of type any are normalized")
+
+		//nolint:forcetypeassert // Test code
+		result.Payload.(map[string]any)["mutated"] = true
+		assert.NotContains(t, payload, "mutated")
+	})
+}
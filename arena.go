@@ -0,0 +1,30 @@
+//go:build goexperiment.arenas
+
+package mapstructure
+
+import "arena"
+
+// WithArena makes the call allocate its intermediate values from a memory arena freed when
+// the call returns, instead of leaving them to the garbage collector, for latency-sensitive
+// services where decode-time GC pauses matter. Decoded values are always heap allocated.
+//
+// This mode is experimental: it is only available when building with GOEXPERIMENT=arenas,
+// and it currently covers the row slices zipped by UnmarshalColumns.
+func WithArena() CallOption {
+	return func(o *callOptions) {
+		o.scratch = &arenaScratch{a: arena.NewArena()}
+	}
+}
+
+// arenaScratch allocates intermediate values from an arena.
+type arenaScratch struct {
+	a *arena.Arena
+}
+
+func (s *arenaScratch) anySlice(n int) []any {
+	return arena.MakeSlice[any](s.a, n, n)
+}
+
+func (s *arenaScratch) release() {
+	s.a.Free()
+}
@@ -0,0 +1,31 @@
+//go:build goexperiment.arenas
+
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_UnmarshalColumns_WithArena(t *testing.T) {
+	type Row struct {
+		Name string `schema:"name"`
+		Size int    `schema:"size"`
+	}
+
+	cols := map[string][]any{"name": {"a", "b"}, "size": {1, "2"}}
+
+	var rows []Row
+	require.NoError(t, UnmarshalColumns(cols, &rows, WithArena()))
+	assert.Equal(t, []Row{{Name: "a", Size: 1}, {Name: "b", Size: 2}}, rows)
+
+	// Rows assigned as they are stay on the heap
+	var raw []any
+	require.NoError(t, UnmarshalColumns(cols, &raw, WithArena()))
+	assert.Equal(t, map[string]any{"name": "b", "size": "2"}, raw[1])
+
+	var failed []Row
+	assert.Error(t, UnmarshalColumns(map[string][]any{"size": {"x"}}, &failed, WithArena()))
+}
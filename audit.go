@@ -0,0 +1,35 @@
+package mapstructure
+
+import "reflect"
+
+// UnmarshalAudit decodes data into the struct pointed to by result like Unmarshal and
+// returns the fields it changed. This is a convenience function that uses a shared
+// default unmarshaler.
+func UnmarshalAudit(data map[string]any, result any, opts ...CallOption) ([]FieldChange, error) {
+	return defaultUnmarshaler.UnmarshalAudit(data, result, opts...)
+}
+
+// UnmarshalAudit decodes data into the struct pointed to by result like Unmarshal and
+// returns the fields it changed, with their old and new values and the map key they
+// were decoded from, for audit logs of configuration and admin changes.
+// Changes are computed as by Diff, including fields set from defaults.
+// No changes are returned on error; result may then be partially updated, as with Unmarshal.
+func (u *Unmarshaler) UnmarshalAudit(data map[string]any, result any, opts ...CallOption) ([]FieldChange, error) {
+	rv, err := validateResultPointer(result)
+	if err != nil {
+		return nil, err
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, NewValidationError("result must be a pointer to a struct")
+	}
+
+	before := deepCopy(rv, make(map[copyKey]reflect.Value))
+	if err := u.Unmarshal(data, result, opts...); err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	u.diffStruct(before, rv, "", &changes, make(map[copyKey]struct{}))
+
+	return changes, nil
+}
@@ -0,0 +1,54 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_UnmarshalAudit(t *testing.T) {
+	type Limits struct {
+		Requests int      `schema:"requests"`
+		Burst    int      `schema:"burst" default:"10"`
+		Allow    []string `schema:"allow"`
+	}
+	type Settings struct {
+		Name   string  `schema:"name"`
+		Limits Limits  `schema:"limits"`
+		Owner  *string `schema:"owner"`
+	}
+
+	owner := "ops"
+	settings := Settings{Name: "api", Limits: Limits{Requests: 100, Burst: 10, Allow: []string{"a"}}, Owner: &owner}
+
+	changes, err := UnmarshalAudit(map[string]any{
+		"name":   "api",
+		"limits": map[string]any{"requests": "200", "allow": []any{"a", "b"}},
+	}, &settings)
+
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Path: "limits.requests", Key: "requests", Old: 100, New: 200},
+		{Path: "limits.allow", Key: "allow", Old: []string{"a"}, New: []string{"a", "b"}},
+	}, changes)
+	assert.Equal(t, 200, settings.Limits.Requests, "result is updated")
+	assert.Same(t, &owner, settings.Owner, "absent keys are left alone")
+
+	t.Run("errors", func(t *testing.T) {
+		changes, err := UnmarshalAudit(map[string]any{"limits": map[string]any{"burst": "x"}}, &settings)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Nil(t, changes)
+
+		var count int
+		_, err = UnmarshalAudit(map[string]any{}, &count)
+
+		var valErr *ValidationError
+		require.ErrorAs(t, err, &valErr)
+
+		_, err = UnmarshalAudit(map[string]any{}, settings)
+		require.ErrorAs(t, err, &valErr)
+	})
+}
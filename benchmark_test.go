@@ -0,0 +1,119 @@
+package mapstructure
+
+import (
+	"testing"
+)
+
+func BenchmarkUnmarshal_Slices(b *testing.B) {
+	type Target struct {
+		Assignable []int     `schema:"assignable"`
+		Copied     []int     `schema:"copied"`
+		Interfaces []any     `schema:"interfaces"`
+		Converted  []float64 `schema:"converted"`
+		Split      []string  `schema:"split,split"`
+	}
+
+	benchmarks := []struct {
+		name string
+		data map[string]any
+	}{
+		{name: "assignable", data: map[string]any{"assignable": []int{1, 2, 3, 4, 5, 6, 7, 8}}},
+		{name: "copied", data: map[string]any{"copied": [8]int{1, 2, 3, 4, 5, 6, 7, 8}}},
+		{name: "interfaces", data: map[string]any{"interfaces": []string{"a", "b", "c", "d"}}},
+		{name: "split", data: map[string]any{"split": "a,b,c,d,e,f,g,h"}},
+		{name: "converted", data: map[string]any{"converted": []any{1, 2, 3, 4, 5, 6, 7, 8}}},
+	}
+
+	u := NewDefaultUnmarshaler()
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var result Target
+				if err := u.Unmarshal(bm.data, &result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshal_Struct(b *testing.B) {
+	type Address struct {
+		Street string `schema:"street"`
+		City   string `schema:"city"`
+	}
+	type User struct {
+		Name    string   `schema:"name"`
+		Age     int      `schema:"age"`
+		Active  bool     `schema:"active"`
+		Score   float64  `schema:"score"`
+		Tags    []string `schema:"tags"`
+		Address Address  `schema:"address"`
+		Role    string   `schema:"role" default:"user"`
+	}
+
+	data := map[string]any{
+		"name":    "Alice",
+		"age":     30,
+		"active":  true,
+		"score":   9.5,
+		"tags":    []string{"admin", "dev"},
+		"address": map[string]any{"street": "Main St", "city": "Springfield"},
+	}
+
+	u := NewDefaultUnmarshaler()
+	b.ReportAllocs()
+	for b.Loop() {
+		var result User
+		if err := u.Unmarshal(data, &result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecoder_Decode(b *testing.B) {
+	type Request struct {
+		Name   string `schema:"name"`
+		Limit  int    `schema:"limit"`
+		Offset int    `schema:"offset"`
+	}
+
+	data := map[string]any{"name": "list", "limit": 10, "offset": 20}
+	d := NewDecoder[Request](NewDefaultUnmarshaler())
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := d.Decode(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal_Defaults(b *testing.B) {
+	type Server struct {
+		Host  string  `schema:"host" default:"localhost"`
+		Port  int     `schema:"port" default:"8080"`
+		Limit uint64  `schema:"limit" default:"1000"`
+		Ratio float64 `schema:"ratio" default:"0.75"`
+		Debug bool    `schema:"debug" default:"true"`
+	}
+
+	for _, bc := range []struct {
+		name string
+		u    *Unmarshaler
+	}{
+		{"converted per decode", NewDefaultUnmarshaler()},
+		{"prepared", NewDefaultUnmarshaler(WithPreparedDefaults())},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var result Server
+				if err := bc.u.Unmarshal(map[string]any{}, &result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package mapstructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// ErrUnsupportedMediaType is returned by DecodeBody for content types it cannot decode.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// UnmarshalFunc parses an encoded document into v, with the signature of yaml.Unmarshal
+// in gopkg.in/yaml.v3 and compatible packages.
+type UnmarshalFunc func(data []byte, v any) error
+
+// WithYAMLUnmarshal sets the function DecodeYAML and DecodeBody parse YAML with, such as
+// yaml.Unmarshal from gopkg.in/yaml.v3, so the package does not depend on a YAML library.
+// Without it YAML bodies are not supported.
+func WithYAMLUnmarshal(unmarshal UnmarshalFunc) Option {
+	return func(u *Unmarshaler) {
+		u.yamlUnmarshal = unmarshal
+	}
+}
+
+// DecodeBody decodes a JSON body into result, failing with ErrUnsupportedMediaType for
+// other content types. This is a convenience function that uses a shared default
+// unmarshaler, which has no YAML support: YAML is opt-in, see WithYAMLUnmarshal and DecodeYAML.
+func DecodeBody(contentType string, body []byte, result any, opts ...CallOption) error {
+	return defaultUnmarshaler.DecodeBody(contentType, body, result, opts...)
+}
+
+// DecodeJSON decodes a JSON object into result.
+// This is a convenience function that uses a shared default unmarshaler.
+func DecodeJSON(body []byte, result any, opts ...CallOption) error {
+	return defaultUnmarshaler.DecodeJSON(body, result, opts...)
+}
+
+// DecodeYAML decodes a YAML document holding a mapping into result, parsing it with
+// unmarshal, such as yaml.Unmarshal from gopkg.in/yaml.v3.
+// This is a convenience function that uses a shared default unmarshaler.
+func DecodeYAML(unmarshal UnmarshalFunc, body []byte, result any, opts ...CallOption) error {
+	u := *defaultUnmarshaler
+	u.yamlUnmarshal = unmarshal
+
+	return u.DecodeYAML(body, result, opts...)
+}
+
+// DecodeBody decodes a request or message body into result with DecodeJSON or DecodeYAML,
+// picking the format from contentType, a Content-Type header value such as
+// "application/json; charset=utf-8". JSON is application/json, text/json and media types
+// with a +json suffix; YAML is application/yaml, application/x-yaml, text/yaml, text/x-yaml
+// and media types with a +yaml suffix, when u has a YAML unmarshal function. Other content
+// types fail with ErrUnsupportedMediaType.
+// opts configure this call only, e.g. WithGroups("admin").
+func (u *Unmarshaler) DecodeBody(contentType string, body []byte, result any, opts ...CallOption) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrUnsupportedMediaType, contentType, err)
+	}
+
+	switch {
+	case mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json"):
+		return u.DecodeJSON(body, result, opts...)
+	case u.yamlUnmarshal != nil && (mediaType == "application/yaml" || mediaType == "application/x-yaml" ||
+		mediaType == "text/yaml" || mediaType == "text/x-yaml" || strings.HasSuffix(mediaType, "+yaml")):
+		return u.DecodeYAML(body, result, opts...)
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedMediaType, mediaType)
+	}
+}
+
+// DecodeJSON decodes body, a single JSON object or null, into result: it is parsed with
+// numbers kept as json.Number, so large integers and exact decimals reach their fields
+// without passing through float64, and the resulting map is decoded with Unmarshal.
+// null decodes like a nil map. Bodies that are not valid JSON, hold another JSON value
+// or have data after the object fail before result is changed.
+// opts configure this call only, e.g. WithGroups("admin").
+func (u *Unmarshaler) DecodeJSON(body []byte, result any, opts ...CallOption) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid JSON body: data after the top-level value")
+	}
+
+	dataMap, ok := data.(map[string]any)
+	if !ok && data != nil {
+		return fmt.Errorf("invalid JSON body: expected an object, got %s", jsonKind(data))
+	}
+
+	return u.Unmarshal(dataMap, result, opts...)
+}
+
+// DecodeYAML decodes body, a YAML document holding a mapping, into result: the document
+// is parsed with the function set by WithYAMLUnmarshal, mappings with non-string keys are
+// turned into maps keyed by the keys' text, and the resulting map is decoded with Unmarshal.
+// An empty document or null decodes like a nil map. Bodies that fail to parse, hold another
+// kind of value or have distinct keys with the same text, such as 1 and "1", fail before
+// result is changed. Only the first document of a multi-document stream is decoded, as
+// yaml.Unmarshal reads no further.
+// opts configure this call only, e.g. WithGroups("admin").
+func (u *Unmarshaler) DecodeYAML(body []byte, result any, opts ...CallOption) error {
+	if u.yamlUnmarshal == nil {
+		return NewValidationError("YAML decoding requires WithYAMLUnmarshal")
+	}
+
+	var data any
+	if err := u.yamlUnmarshal(body, &data); err != nil {
+		return fmt.Errorf("invalid YAML body: %w", err)
+	}
+
+	normalized, err := normalizeYAML(data, "")
+	if err != nil {
+		return fmt.Errorf("invalid YAML body: %w", err)
+	}
+
+	dataMap, ok := normalized.(map[string]any)
+	if !ok && normalized != nil {
+		return fmt.Errorf("invalid YAML body: expected a mapping, got %T", data)
+	}
+
+	return u.Unmarshal(dataMap, result, opts...)
+}
+
+// normalizeYAML returns value with the mappings it holds keyed by strings, as decoding
+// expects, failing when distinct keys of a mapping have the same text.
+func normalizeYAML(value any, fieldPath string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			normalized, err := normalizeYAML(elem, buildFieldPath(fieldPath, key))
+			if err != nil {
+				return nil, err
+			}
+			v[key] = normalized
+		}
+
+		return v, nil
+	case map[any]any:
+		normalized := make(map[string]any, len(v))
+		keys := make(map[string]any, len(v)) // Original keys by text
+		for key, elem := range v {
+			text := fmt.Sprint(key)
+			if other, ok := keys[text]; ok {
+				return nil, NewConstraintError(fieldPath, "duplicate",
+					fmt.Sprintf("keys %#v and %#v are both %q", other, key, text))
+			}
+			keys[text] = key
+
+			var err error
+			if normalized[text], err = normalizeYAML(elem, buildFieldPath(fieldPath, text)); err != nil {
+				return nil, err
+			}
+		}
+
+		return normalized, nil
+	case []any:
+		for i, elem := range v {
+			normalized, err := normalizeYAML(elem, fmt.Sprintf("%s[%d]", fieldPath, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = normalized
+		}
+
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// jsonKind names the kind of a decoded JSON value for error messages.
+func jsonKind(value any) string {
+	switch value.(type) {
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,241 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bodyOrder struct {
+	ID     int64          `schema:"id"`
+	Total  float64        `schema:"total"`
+	Status string         `schema:"status" default:"new"`
+	Tags   []string       `schema:"tags"`
+	Ship   *normalizeLine `schema:"ship"`
+	Extra  map[string]any `schema:"extra"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    bodyOrder
+		wantErr string
+	}{
+		{
+			name: "object",
+			body: `{"id": 9007199254740993, "total": 12.5, "tags": ["a", "b"], "ship": {"sku": "x", "qty": 2}}`,
+			want: bodyOrder{ID: 9007199254740993, Total: 12.5, Status: "new", Tags: []string{"a", "b"}, Ship: &normalizeLine{SKU: "X", Qty: 2}},
+		},
+		{
+			name: "numbers kept exact in untyped fields",
+			body: `{"extra": {"n": 12345678901234567890}}`,
+			want: bodyOrder{Status: "new", Extra: map[string]any{"n": json.Number("12345678901234567890")}},
+		},
+		{
+			name: "null",
+			body: `null`,
+			want: bodyOrder{Status: "new"},
+		},
+		{
+			name:    "syntax error",
+			body:    `{"id": `,
+			wantErr: "invalid JSON body",
+		},
+		{
+			name:    "not an object",
+			body:    `[1, 2]`,
+			wantErr: "expected an object, got an array",
+		},
+		{
+			name:    "trailing data",
+			body:    `{"id": 1} {"id": 2}`,
+			wantErr: "data after the top-level value",
+		},
+		{
+			name:    "decoding errors",
+			body:    `{"id": "many"}`,
+			wantErr: "id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bodyOrder
+			err := DecodeJSON([]byte(tt.body), &got)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// parsedYAML returns a YAML unmarshal function parsing every document as value.
+func parsedYAML(value any) UnmarshalFunc {
+	return func(_ []byte, v any) error {
+		*v.(*any) = value //nolint:forcetypeassert // DecodeYAML passes *any
+
+		return nil
+	}
+}
+
+func TestUnmarshaler_DecodeYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		parsed  any // Document returned by the YAML unmarshal function
+		want    bodyOrder
+		wantErr string
+	}{
+		{
+			name: "mapping",
+			parsed: map[string]any{
+				"id": 7, "total": 1.5, "tags": []any{"a", "b"}, "ship": map[string]any{"sku": "x"},
+			},
+			want: bodyOrder{ID: 7, Total: 1.5, Status: "new", Tags: []string{"a", "b"}, Ship: &normalizeLine{SKU: "X", Qty: 1}},
+		},
+		{
+			name: "non-string keys",
+			parsed: map[string]any{"extra": map[any]any{
+				1: "one", true: "yes", "list": []any{map[any]any{2: "two"}},
+			}},
+			want: bodyOrder{Status: "new", Extra: map[string]any{
+				"1": "one", "true": "yes", "list": []any{map[string]any{"2": "two"}},
+			}},
+		},
+		{
+			name:   "empty document",
+			parsed: nil,
+			want:   bodyOrder{Status: "new"},
+		},
+		{
+			name:    "keys with the same text",
+			parsed:  map[string]any{"extra": map[any]any{"list": []any{map[any]any{1: "a", "1": "b"}}}},
+			wantErr: `extra.list[0]: keys `,
+		},
+		{
+			name:    "not a mapping",
+			parsed:  []any{1, 2},
+			wantErr: "expected a mapping, got []interface {}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bodyOrder
+			err := NewDefaultUnmarshaler(WithYAMLUnmarshal(parsedYAML(tt.parsed))).DecodeYAML([]byte("body"), &got)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("keys with the same text reported", func(t *testing.T) {
+		var got bodyOrder
+		err := NewDefaultUnmarshaler(WithYAMLUnmarshal(parsedYAML(map[any]any{1: "a", "1": "b"}))).DecodeYAML(nil, &got)
+
+		var constraintErr *ConstraintError
+		require.ErrorAs(t, err, &constraintErr)
+		assert.Equal(t, "duplicate", constraintErr.Constraint)
+		assert.Contains(t, constraintErr.Message, `are both "1"`)
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		u := NewDefaultUnmarshaler(WithYAMLUnmarshal(func([]byte, any) error { return errors.New("line 1: bad indent") }))
+		var got bodyOrder
+		assert.ErrorContains(t, u.DecodeYAML(nil, &got), "invalid YAML body: line 1: bad indent")
+	})
+
+	t.Run("without a YAML unmarshal function", func(t *testing.T) {
+		var got bodyOrder
+		err := NewDefaultUnmarshaler().DecodeYAML([]byte("id: 1"), &got)
+		assert.ErrorAs(t, err, new(*ValidationError))
+	})
+}
+
+func TestDecodeYAML(t *testing.T) {
+	var got bodyOrder
+	require.NoError(t, DecodeYAML(parsedYAML(map[string]any{"id": 7}), []byte("body"), &got))
+	assert.Equal(t, bodyOrder{ID: 7, Status: "new"}, got)
+
+	// The shared unmarshaler keeps no YAML support
+	err := DecodeBody("application/yaml", []byte("id: 3"), &got)
+	require.ErrorIs(t, err, ErrUnsupportedMediaType)
+
+	err = DecodeYAML(nil, []byte("id: 1"), &got)
+	assert.ErrorAs(t, err, new(*ValidationError))
+}
+
+func TestDecodeBody(t *testing.T) {
+	yamlUnmarshaler := NewDefaultUnmarshaler(WithYAMLUnmarshal(json.Unmarshal)) // JSON documents are YAML
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     error
+	}{
+		{name: "json", contentType: "application/json", body: `{"id": 3}`},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", body: `{"id": 3}`},
+		{name: "json suffix", contentType: "application/merge-patch+json", body: `{"id": 3}`},
+		{name: "yaml", contentType: "application/yaml", body: `{"id": 3}`},
+		{name: "legacy yaml", contentType: "text/x-yaml", body: `{"id": 3}`},
+		{name: "yaml suffix", contentType: "application/vnd.api+yaml", body: `{"id": 3}`},
+		{name: "unsupported", contentType: "text/plain", body: "id=3", wantErr: ErrUnsupportedMediaType},
+		{name: "malformed", contentType: "application/", body: `{"id": 3}`, wantErr: ErrUnsupportedMediaType},
+		{name: "empty", contentType: "", body: `{"id": 3}`, wantErr: ErrUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bodyOrder
+			err := yamlUnmarshaler.DecodeBody(tt.contentType, []byte(tt.body), &got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), got.ID)
+		})
+	}
+
+	t.Run("yaml unsupported by default", func(t *testing.T) {
+		var got bodyOrder
+		err := DecodeBody("application/yaml", []byte("id: 3"), &got)
+		assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+
+		require.NoError(t, DecodeBody("application/json", []byte(`{"id": 3}`), &got))
+		assert.Equal(t, int64(3), got.ID)
+	})
+}
+
+func TestUnmarshaler_DecodeBody(t *testing.T) {
+	type Account struct {
+		Name  string `schema:"name"`
+		Admin bool   `schema:"admin,groups=admin"`
+	}
+
+	u := NewDefaultUnmarshaler(WithErrorUnset(), WithYAMLUnmarshal(json.Unmarshal))
+
+	var got Account
+	require.NoError(t, u.DecodeBody("application/json", []byte(`{"name": "a", "admin": true}`), &got, WithGroups("admin")))
+	assert.Equal(t, Account{Name: "a", Admin: true}, got)
+
+	// Options of the unmarshaler apply
+	err := u.DecodeBody("application/yaml", []byte(`{"name": "a"}`), &got, WithGroups("admin"))
+	assert.ErrorContains(t, err, `missing key "admin"`)
+}
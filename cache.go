@@ -2,6 +2,8 @@ package mapstructure
 
 import (
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/talav/tagparser"
@@ -20,6 +22,30 @@ type StructMetadataCache struct {
 	cache          sync.Map
 	tagName        string
 	defaultTagName string
+	taggedOnly     bool      // Skip fields without the tag, see WithTaggedFieldsOnly
+	dashKey        bool      // Map fields tagged "-," to the key "-", see WithDashKey
+	docs           FieldDocs // Field doc comments, see WithFieldDocs
+}
+
+// CacheOption configures a StructMetadataCache.
+type CacheOption func(*StructMetadataCache)
+
+// WithTaggedFieldsOnly makes the cache skip fields that do not carry the tag, so structs
+// can mix wire fields with internal ones. Embedded structs participate regardless,
+// with the same rule applied to their fields. It has no effect when tags are ignored.
+func WithTaggedFieldsOnly() CacheOption {
+	return func(c *StructMetadataCache) {
+		c.taggedOnly = true
+	}
+}
+
+// WithDashKey makes a tag whose key is "-" followed by a comma, such as `json:"-,"`, map
+// the field to the key "-" instead of skipping it, as encoding/json does. A tag of
+// just "-" still skips the field.
+func WithDashKey() CacheOption {
+	return func(c *StructMetadataCache) {
+		c.dashKey = true
+	}
 }
 
 // NewStructMetadataCache creates a new struct metadata cache.
@@ -27,7 +53,7 @@ type StructMetadataCache struct {
 // defaultTagName specifies which tag to read for default values (e.g., "default").
 // Use "-" for tagName to ignore all tags and map fields by their Go struct field names.
 // Empty strings default to "schema" and "default" respectively.
-func NewStructMetadataCache(tagName, defaultTagName string) *StructMetadataCache {
+func NewStructMetadataCache(tagName, defaultTagName string, opts ...CacheOption) *StructMetadataCache {
 	if tagName == "" {
 		tagName = DefaultTagName
 	}
@@ -35,10 +61,15 @@ func NewStructMetadataCache(tagName, defaultTagName string) *StructMetadataCache
 		defaultTagName = DefaultValueTagName
 	}
 
-	return &StructMetadataCache{
+	c := &StructMetadataCache{
 		tagName:        tagName,
 		defaultTagName: defaultTagName,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // NewDefaultStructMetadataCache creates a struct metadata cache with default tag names.
@@ -56,6 +87,28 @@ func NewDefaultStructMetadataCache() *StructMetadataCache {
 //   - Introspecting struct metadata for tooling
 //   - Testing cache behavior
 func (c *StructMetadataCache) GetMetadata(typ reflect.Type) *StructMetadata {
+	return c.getMetadata(typ, nil)
+}
+
+// ResolveField returns the field that decoding a struct of type typ reads from mapKey,
+// which may be promoted from an embedded struct, and whether there is one. typ may also
+// be a pointer to the struct type. Middleware such as query parameter whitelists or sort
+// field validation can use it to accept exactly the keys decoding does.
+// The returned metadata is shared by all users of the cache and must not be modified.
+func (c *StructMetadataCache) ResolveField(typ reflect.Type, mapKey string) (*FieldMetadata, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	return c.GetMetadata(typ).FieldByKey(mapKey)
+}
+
+// getMetadata is GetMetadata for a type embedded in the struct types being built,
+// which building holds.
+func (c *StructMetadataCache) getMetadata(typ reflect.Type, building map[reflect.Type]struct{}) *StructMetadata {
 	// Check cache first
 	if cached, ok := c.cache.Load(typ); ok {
 		if metadata, ok := cached.(*StructMetadata); ok {
@@ -64,7 +117,7 @@ func (c *StructMetadataCache) GetMetadata(typ reflect.Type) *StructMetadata {
 	}
 
 	// Build metadata
-	metadata := c.buildMetadata(typ)
+	metadata := c.buildMetadata(typ, building)
 
 	// Store in cache (or get existing if another goroutine stored it first)
 	actual, _ := c.cache.LoadOrStore(typ, metadata)
@@ -73,66 +126,357 @@ func (c *StructMetadataCache) GetMetadata(typ reflect.Type) *StructMetadata {
 	return metadata
 }
 
-// buildMetadata builds struct metadata by parsing struct tags.
-func (c *StructMetadataCache) buildMetadata(typ reflect.Type) *StructMetadata {
+// buildMetadata builds struct metadata by parsing struct tags. building holds the struct
+// types embedding typ that are being built: an embedded pointer to one of them is not
+// promoted, which ends embedding cycles such as type Node struct{ *Node }.
+func (c *StructMetadataCache) buildMetadata(typ reflect.Type, building map[reflect.Type]struct{}) *StructMetadata {
+	if building == nil {
+		building = make(map[reflect.Type]struct{})
+	}
+	building[typ] = struct{}{}
+	defer delete(building, typ)
+
 	fields := make([]FieldMetadata, 0, typ.NumField())
+	knownKeys := make(map[string]struct{}, typ.NumField())
+	var funcFields []FieldMetadata
+	var markerTag string
+	var tagErr error
+	docs := c.docs.fieldDocs(typ)
 
 	for i := range typ.NumField() {
 		f := typ.Field(i)
-		if !f.IsExported() {
+
+		// Blank marker field declares struct-level options
+		if f.Name == "_" && c.tagName != "-" {
+			markerTag = f.Tag.Get(c.tagName)
+
+			continue
+		}
+
+		// Unexported embedded structs still promote their exported fields
+		if !f.IsExported() && (!f.Anonymous || f.Type.Kind() != reflect.Struct) {
 			continue
 		}
 
 		// If tagName is "-", use field name directly without reading tags
 		var mapKey string
+		var options map[string]string
 		var skip bool
+		var named bool
+		var since, until int
+		anonymous := f.Anonymous
 		if c.tagName == "-" {
 			mapKey = f.Name
 		} else {
-			mapKey, skip = parseFieldTag(f.Tag.Get(c.tagName), f.Name)
+			tagValue, tagged := f.Tag.Lookup(c.tagName)
+			if c.taggedOnly && !tagged && !f.Anonymous {
+				continue
+			}
+
+			var err error
+			mapKey, options, skip, err = c.parseFieldTag(tagValue, f.Name)
+			if err != nil && tagErr == nil {
+				tagErr = NewTagError(typ, f.Name, tagValue, err)
+			}
 			if skip {
 				continue
 			}
+
+			// Embedded structs named by their tag are plain fields, as in encoding/json
+			named = hasTagName(tagValue)
+			if named {
+				if !f.IsExported() {
+					continue
+				}
+				anonymous = false
+			}
+
+			// Squashed struct fields promote their fields like embedded ones
+			if _, ok := options[OptionSquash]; ok {
+				if _, isStruct := embeddedStruct(f.Type); isStruct {
+					anonymous = true
+				} else if tagErr == nil {
+					tagErr = NewTagError(typ, f.Name, tagValue, errSquashType)
+				}
+			}
+			if _, ok := options[OptionRemain]; ok && f.Type != remainType && tagErr == nil {
+				tagErr = NewTagError(typ, f.Name, tagValue, errRemainType)
+			}
+			if source, ok := options[OptionExpr]; ok && tagErr == nil {
+				if _, err := parseExpr(source); err != nil {
+					tagErr = NewTagError(typ, f.Name, tagValue, err)
+				}
+			}
+			if since, until, err = parseVersions(options); err != nil && tagErr == nil {
+				tagErr = NewTagError(typ, f.Name, tagValue, err)
+			}
+			for _, constraint := range []string{OptionMinLen, OptionMaxLen} {
+				if limit, ok := options[constraint]; ok && tagErr == nil {
+					if _, err := parseLength(constraint, limit); err != nil {
+						tagErr = NewTagError(typ, f.Name, tagValue, err)
+					}
+				}
+			}
+			if mode, ok := options[OptionUnique]; ok && tagErr == nil {
+				if err := checkUniqueMode(mode); err != nil {
+					tagErr = NewTagError(typ, f.Name, tagValue, err)
+				}
+			}
+			if format, ok := options[OptionFormat]; ok && tagErr == nil {
+				if err := checkFormat(format, f.Type); err != nil {
+					tagErr = NewTagError(typ, f.Name, tagValue, err)
+				}
+			}
 		}
 
 		// Store raw default pointer - conversion happens at unmarshal time
 		var defaultPtr *string
+		var defaultTmpl *defaultTemplate
+		var defaultValues map[string]any
+		embedType, promotes := embeddedStruct(f.Type)
+		promotes = promotes && anonymous
 		if v, ok := f.Tag.Lookup(c.defaultTagName); ok {
 			defaultPtr = &v
+			if promotes {
+				var err error
+				if defaultValues, err = parseEmbeddedDefault(v); err != nil && tagErr == nil {
+					tagErr = NewTagError(typ, f.Name, v, err)
+				}
+			} else {
+				defaultTmpl = parseDefaultTemplate(f.Name, v)
+			}
 		}
 
-		fields = append(fields, FieldMetadata{
+		var groups []string
+		if v, ok := options["groups"]; ok {
+			groups = parseGroups(v)
+		}
+
+		if _, remain := options[OptionRemain]; f.IsExported() && !remain {
+			knownKeys[mapKey] = struct{}{}
+		}
+		var embedded *StructMetadata
+		if _, cycle := building[embedType]; promotes && !cycle {
+			embedded = c.getMetadata(embedType, building)
+			for key := range embedded.knownKeys {
+				knownKeys[key] = struct{}{}
+			}
+			if tagErr == nil {
+				tagErr = embedded.tagErr
+			}
+		}
+
+		field := FieldMetadata{
 			StructFieldName: f.Name,
 			MapKey:          mapKey,
-			Index:           i,
+			Index:           f.Index,
 			Type:            f.Type,
-			Embedded:        f.Anonymous,
+			Embedded:        anonymous,
 			Default:         defaultPtr,
-		})
+			Options:         options,
+			Groups:          groups,
+			Since:           since,
+			Until:           until,
+			Doc:             docs[f.Name],
+			defaultTemplate: defaultTmpl,
+			defaultValues:   defaultValues,
+			promoted:        embedded,
+			named:           named,
+		}
+
+		// Funcs and channels cannot come from input data, see WithFuncFieldPolicy
+		if isFuncOrChan(f.Type) {
+			funcFields = append(funcFields, field)
+
+			continue
+		}
+
+		fields = append(fields, field)
 	}
 
-	return &StructMetadata{Fields: fields}
+	funcFields = c.promoteFuncFields(fields, funcFields)
+	flat := c.flattenFields(fields)
+	byKey, eager := indexFields(flat)
+	oneOf := buildOneOfGroups(flat)
+
+	return &StructMetadata{
+		Fields:      fields,
+		Options:     resolveStructOptions(typ, markerTag),
+		knownKeys:   knownKeys,
+		oneOf:       oneOf,
+		flat:        flat,
+		funcFields:  funcFields,
+		tagErr:      tagErr,
+		byKey:       byKey,
+		eager:       eager,
+		flatScalars: tagErr == nil && isFlatScalars(fields, oneOf),
+		remain:      remainField(flat),
+	}
 }
 
-// parseFieldTag extracts the map key from a tag value.
-// Returns (mapKey, skip). If skip is true, the field should be ignored.
-func parseFieldTag(tagValue, fieldName string) (string, bool) {
+// flattenFields returns fields followed, after each embedded struct or pointer to one,
+// by the fields promoted from it, with index paths relative to the outer struct. Promoted fields
+// whose map key is also used by a shallower field are dropped, as are fields sharing
+// a key at the same depth, following the rules Go uses for promoted field names.
+func (c *StructMetadataCache) flattenFields(fields []FieldMetadata) []FieldMetadata {
+	flat := make([]FieldMetadata, 0, len(fields))
+
+	for i := range fields {
+		flat = append(flat, fields[i])
+		if fields[i].promoted == nil {
+			continue
+		}
+
+		embed := len(flat) // owner of fields declared by the embedded struct
+		base := len(flat)
+		for _, promoted := range fields[i].promoted.flat {
+			promoted.Index = append(slices.Clip(fields[i].Index), promoted.Index...)
+			if v, ok := fields[i].defaultValues[promoted.MapKey]; ok && !promoted.Embedded {
+				promoted.embeddedDefault = &v
+			}
+			if promoted.owner == 0 {
+				promoted.owner = embed
+			} else {
+				promoted.owner += base
+			}
+			flat = append(flat, promoted)
+		}
+	}
+
+	return dropConflictingFields(flat)
+}
+
+// dropConflictingFields removes fields of flat that lose to another field with the same
+// map key: the shallowest field wins, and fields tied at the shallowest depth all lose.
+// Embedded fields are never removed, as they are decoded by their Go field name.
+func dropConflictingFields(flat []FieldMetadata) []FieldMetadata {
+	depths := make(map[string][]int, len(flat)) // Depth of every field using the key
+	for i := range flat {
+		if !flat[i].Embedded {
+			depths[flat[i].MapKey] = append(depths[flat[i].MapKey], len(flat[i].Index))
+		}
+	}
+
+	loses := func(field *FieldMetadata) bool {
+		if field.Embedded {
+			return false
+		}
+
+		depth, ties := len(field.Index), 0
+		for _, other := range depths[field.MapKey] {
+			if other < depth {
+				return true
+			}
+			if other == depth {
+				ties++
+			}
+		}
+
+		return ties > 1
+	}
+
+	kept := make([]FieldMetadata, 0, len(flat))
+	positions := make([]int, len(flat)) // New position of kept embedded fields, plus one
+	for i := range flat {
+		if loses(&flat[i]) {
+			continue
+		}
+
+		field := flat[i]
+		if field.owner != 0 {
+			field.owner = positions[field.owner-1]
+		}
+		kept = append(kept, field)
+		positions[i] = len(kept)
+	}
+
+	return kept
+}
+
+// indexFields numbers the fields of flat with their positions and returns the lookup
+// index of non-embedded fields by map key, except remainder fields, and the positions of the fields visited
+// even when their key is absent from the input: embedded structs and fields with defaults,
+// including defaults of embedded structs.
+func indexFields(flat []FieldMetadata) (map[string]*FieldMetadata, []int) {
+	byKey := make(map[string]*FieldMetadata, len(flat))
+	var eager []int
+
+	for i := range flat {
+		field := &flat[i]
+		field.pos = i
+
+		if field.Embedded || field.Default != nil || field.embeddedDefault != nil {
+			eager = append(eager, i)
+		}
+		if !field.Embedded && !isRemain(field) {
+			byKey[field.MapKey] = field
+		}
+	}
+
+	return byKey, eager
+}
+
+// embeddedStruct returns the struct type whose fields an embedded field of type typ
+// promotes, and whether there is one: typ must be a struct or pointer to struct type.
+func embeddedStruct(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ, typ.Kind() == reflect.Struct
+}
+
+// hasTagName reports whether the tag value names the map key, rather than only listing options.
+func hasTagName(tagValue string) bool {
+	return tagValue != "" && tagValue[0] != ','
+}
+
+// parseFieldTag is the package-level parseFieldTag, honoring WithDashKey.
+func (c *StructMetadataCache) parseFieldTag(tagValue, fieldName string) (string, map[string]string, bool, error) {
+	if rest, ok := strings.CutPrefix(tagValue, "-,"); ok && c.dashKey {
+		_, options, _, err := parseFieldTag(fieldName+","+rest, fieldName)
+
+		return "-", options, false, err
+	}
+
+	return parseFieldTag(tagValue, fieldName)
+}
+
+// parseFieldTag extracts the map key and options from a tag value.
+// Returns (mapKey, options, skip, err). If skip is true, the field should be ignored.
+// options is nil when the tag carries no options.
+//
+// The tag is a comma-separated list: the map key followed by options, each either a
+// flag ("omitempty") or a key=value pair ("unit=ms"). Values containing commas, quotes
+// or spaces can be single-quoted ("enum='a,b'") or backslash-escaped ("enum=a\,b");
+// inside quotes, \' and \\ stand for a quote and a backslash. A malformed tag yields
+// the field name as key, no options and a non-nil err.
+func parseFieldTag(tagValue, fieldName string) (string, map[string]string, bool, error) {
 	if tagValue == "" {
-		return fieldName, false
+		return fieldName, nil, false, nil
 	}
 
 	if tagValue == "-" {
-		return "", true
+		return "", nil, true, nil
 	}
 
 	tag, err := tagparser.ParseWithName(tagValue)
-	if err != nil || tag.Name == "" {
-		return fieldName, false
+	if err != nil {
+		return fieldName, nil, false, err
 	}
 
 	if tag.Name == "-" {
-		return "", true
+		return "", nil, true, nil
+	}
+
+	var options map[string]string
+	if len(tag.Options) > 0 {
+		options = tag.Options
+	}
+
+	if tag.Name == "" {
+		return fieldName, options, false, nil
 	}
 
-	return tag.Name, false
+	return tag.Name, options, false, nil
 }
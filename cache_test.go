@@ -14,7 +14,9 @@ func TestParseFieldTag(t *testing.T) {
 		tagValue  string
 		fieldName string
 		wantKey   string
+		wantOpts  map[string]string
 		wantSkip  bool
+		wantErr   bool
 	}{
 		{
 			name:      "empty tag uses field name",
@@ -42,6 +44,7 @@ func TestParseFieldTag(t *testing.T) {
 			tagValue:  "custom_name,omitempty",
 			fieldName: "MyField",
 			wantKey:   "custom_name",
+			wantOpts:  map[string]string{"omitempty": ""},
 			wantSkip:  false,
 		},
 		{
@@ -56,14 +59,79 @@ func TestParseFieldTag(t *testing.T) {
 			tagValue:  "custom_name,format:date",
 			fieldName: "MyField",
 			wantKey:   "custom_name",
+			wantOpts:  map[string]string{"format:date": ""},
 			wantSkip:  false,
 		},
+		{
+			name:      "name with equals option",
+			tagValue:  "created,unit=ms",
+			fieldName: "MyField",
+			wantKey:   "created",
+			wantOpts:  map[string]string{"unit": "ms"},
+			wantSkip:  false,
+		},
+		{
+			name:      "options without name use field name",
+			tagValue:  ",unit=ms",
+			fieldName: "MyField",
+			wantKey:   "MyField",
+			wantOpts:  map[string]string{"unit": "ms"},
+			wantSkip:  false,
+		},
+		{
+			name:      "quoted value with commas",
+			tagValue:  "tags,split=',',enum='a,b'",
+			fieldName: "MyField",
+			wantKey:   "tags",
+			wantOpts:  map[string]string{"split": ",", "enum": "a,b"},
+		},
+		{
+			name:      "escaped comma",
+			tagValue:  `tags,enum=a\,b`,
+			fieldName: "MyField",
+			wantKey:   "tags",
+			wantOpts:  map[string]string{"enum": "a,b"},
+		},
+		{
+			name:      "escaped quote and backslash inside quotes",
+			tagValue:  `re,pattern='it\'s \\d'`,
+			fieldName: "MyField",
+			wantKey:   "re",
+			wantOpts:  map[string]string{"pattern": `it's \d`},
+		},
+		{
+			name:      "colons need no quoting",
+			tagValue:  "url,example=http://localhost:8080",
+			fieldName: "MyField",
+			wantKey:   "url",
+			wantOpts:  map[string]string{"example": "http://localhost:8080"},
+		},
+		{
+			name:      "quoted key",
+			tagValue:  "'a,b',unit=ms",
+			fieldName: "MyField",
+			wantKey:   "a,b",
+			wantOpts:  map[string]string{"unit": "ms"},
+		},
+		{
+			name:      "unterminated quote",
+			tagValue:  "name,split='",
+			fieldName: "MyField",
+			wantKey:   "MyField",
+			wantErr:   true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotKey, gotSkip := parseFieldTag(tt.tagValue, tt.fieldName)
+			gotKey, gotOpts, gotSkip, err := parseFieldTag(tt.tagValue, tt.fieldName)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
 			assert.Equal(t, tt.wantKey, gotKey)
+			assert.Equal(t, tt.wantOpts, gotOpts)
 			assert.Equal(t, tt.wantSkip, gotSkip)
 		})
 	}
@@ -315,3 +383,314 @@ func TestStructMetadataCache_CustomDefaultTag(t *testing.T) {
 		assert.Equal(t, "NYC", *defaultMap["City"])
 	})
 }
+
+func TestStructMetadataCache_FlattenedFields(t *testing.T) {
+	type Base struct {
+		ID   string `schema:"id"`
+		Name string `schema:"name"`
+	}
+	type Audit struct {
+		Base
+		By string `schema:"by"`
+	}
+	type Left struct {
+		Label string `schema:"label"`
+	}
+	type Right struct {
+		Label string `schema:"label"`
+	}
+	type Record struct {
+		Audit
+		Left
+		Right
+		Name string `schema:"name"`
+	}
+
+	metadata := NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Record]())
+
+	type entry struct {
+		Key   string
+		Index []int
+		Owner int
+	}
+	var got []entry
+	for _, field := range metadata.flat {
+		got = append(got, entry{Key: field.MapKey, Index: field.Index, Owner: field.owner})
+	}
+
+	// Record.Name shadows Base.Name; Left.Label and Right.Label tie and are both dropped.
+	assert.Equal(t, []entry{
+		{Key: "Audit", Index: []int{0}},
+		{Key: "Base", Index: []int{0, 0}, Owner: 1},
+		{Key: "id", Index: []int{0, 0, 0}, Owner: 2},
+		{Key: "by", Index: []int{0, 1}, Owner: 1},
+		{Key: "Left", Index: []int{1}},
+		{Key: "Right", Index: []int{2}},
+		{Key: "name", Index: []int{3}},
+	}, got)
+
+	// Public metadata lists own fields only
+	assert.Len(t, metadata.Fields, 4)
+}
+
+func TestStructMetadataCache_EmbeddedPointers(t *testing.T) {
+	type Base struct {
+		ID string `schema:"id"`
+	}
+	type Node struct {
+		*Node
+		*Base
+		Value int `schema:"value"`
+	}
+
+	metadata := NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Node]())
+
+	var keys []string
+	for _, field := range metadata.flat {
+		keys = append(keys, field.MapKey)
+	}
+
+	// *Base is promoted like Base would be; *Node is not, as it embeds itself
+	assert.Equal(t, []string{"Node", "Base", "id", "value"}, keys)
+	assert.True(t, metadata.HasKey("id"))
+
+	field, ok := metadata.FieldByKey("id")
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 0}, field.Index)
+}
+
+type embeddedBase struct {
+	ID string `schema:"id"`
+}
+
+func TestStructMetadataCache_EmbeddedNames(t *testing.T) {
+	type Audit struct {
+		By string `schema:"by"`
+	}
+	type Record struct {
+		embeddedBase
+		Audit `schema:"audit"`
+	}
+
+	metadata := NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Record]())
+
+	// Unexported embedded structs promote their fields; tagged ones are named fields
+	require.Len(t, metadata.flat, 3)
+	assert.True(t, metadata.flat[0].Embedded)
+	assert.Equal(t, "id", metadata.flat[1].MapKey)
+	assert.Equal(t, "audit", metadata.flat[2].MapKey)
+	assert.False(t, metadata.flat[2].Embedded)
+
+	assert.True(t, metadata.HasKey("id"))
+	assert.True(t, metadata.HasKey("audit"))
+	assert.False(t, metadata.HasKey("by"))
+	assert.False(t, metadata.HasKey("embeddedBase"))
+
+	var result Record
+	data := map[string]any{"id": "1", "by": "x", "audit": map[string]any{"by": "me"}}
+	require.NoError(t, NewDefaultUnmarshaler().Unmarshal(data, &result))
+	assert.Equal(t, Record{embeddedBase: embeddedBase{ID: "1"}, Audit: Audit{By: "me"}}, result)
+}
+
+func TestStructMetadataCache_DashKey(t *testing.T) {
+	type Record struct {
+		Dash    string `json:"-,omitempty"`
+		Skipped string `json:"-"`
+	}
+
+	t.Run("skipped by default", func(t *testing.T) {
+		metadata := NewStructMetadataCache("json", "").GetMetadata(reflect.TypeFor[Record]())
+		assert.Empty(t, metadata.flat)
+	})
+
+	t.Run("dash key", func(t *testing.T) {
+		metadata := NewStructMetadataCache("json", "", WithDashKey()).GetMetadata(reflect.TypeFor[Record]())
+
+		require.Len(t, metadata.flat, 1)
+		assert.Equal(t, "Dash", metadata.flat[0].StructFieldName)
+		assert.Equal(t, "-", metadata.flat[0].MapKey)
+		assert.Equal(t, map[string]string{"omitempty": ""}, metadata.flat[0].Options)
+	})
+}
+
+func TestStructMetadata_FieldByKey(t *testing.T) {
+	type Base struct {
+		ID string `schema:"id"`
+	}
+	type Record struct {
+		Base
+		Name  string `schema:"name" default:"anon"`
+		Count int    `schema:"count"`
+	}
+
+	metadata := NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Record]())
+
+	field, ok := metadata.FieldByKey("id")
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 0}, field.Index)
+
+	field, ok = metadata.FieldByKey("count")
+	require.True(t, ok)
+	assert.Equal(t, "Count", field.StructFieldName)
+
+	_, ok = metadata.FieldByKey("Base")
+	assert.False(t, ok, "embedded structs are not decoded by key")
+
+	_, ok = metadata.FieldByKey("missing")
+	assert.False(t, ok)
+
+	// Embedded structs and fields with defaults are visited for sparse input
+	assert.Equal(t, []int{0, 2}, metadata.eager)
+}
+
+func TestStructMetadataCache_ResolveField(t *testing.T) {
+	type Audit struct {
+		By    string `schema:"by"`
+		Label string `schema:"label"`
+	}
+	type Record struct {
+		*Audit
+		Name    string `schema:"name"`
+		Label   string `schema:"label"`
+		Secret  string `schema:"-"`
+		OnSave  func() `schema:"on_save"`
+		private string
+	}
+
+	cache := NewDefaultStructMetadataCache()
+	typ := reflect.TypeFor[Record]()
+
+	tests := []struct {
+		name      string
+		typ       reflect.Type
+		key       string
+		wantField string
+		wantIndex []int
+	}{
+		{name: "own field", typ: typ, key: "name", wantField: "Name", wantIndex: []int{1}},
+		{name: "promoted field", typ: typ, key: "by", wantField: "By", wantIndex: []int{0, 0}},
+		{name: "shadowing field wins", typ: typ, key: "label", wantField: "Label", wantIndex: []int{2}},
+		{name: "pointer to struct", typ: reflect.PointerTo(typ), key: "name", wantField: "Name", wantIndex: []int{1}},
+		{name: "skipped field", typ: typ, key: "Secret"},
+		{name: "func field", typ: typ, key: "on_save"},
+		{name: "unexported field", typ: typ, key: "private"},
+		{name: "embedded struct", typ: typ, key: "Audit"},
+		{name: "unknown key", typ: typ, key: "missing"},
+		{name: "not a struct", typ: reflect.TypeFor[map[string]any](), key: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, ok := cache.ResolveField(tt.typ, tt.key)
+			if tt.wantField == "" {
+				assert.False(t, ok)
+				assert.Nil(t, field)
+
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, tt.wantField, field.StructFieldName)
+			assert.Equal(t, tt.wantIndex, field.Index)
+		})
+	}
+}
+
+func TestStructMetadata_ResolvedFields(t *testing.T) {
+	type Audit struct {
+		By   string `schema:"by"`
+		Name string `schema:"name"`
+	}
+	type Record struct {
+		Audit
+		Name string `schema:"name"`
+		Port int    `schema:"port"`
+	}
+
+	metadata := NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Record]())
+
+	var keys []string
+	for _, field := range metadata.ResolvedFields() {
+		keys = append(keys, field.MapKey)
+	}
+
+	// Audit.Name is shadowed by Record.Name; the embedded struct itself is not decoded by key
+	assert.Equal(t, []string{"by", "name", "port"}, keys)
+	assert.NoError(t, metadata.Err())
+}
+
+func TestStructMetadataCache_TaggedFieldsOnly(t *testing.T) {
+	type Audit struct {
+		By    string `schema:"by"`
+		Cache string
+	}
+	type Config struct {
+		Audit
+		Name     string `schema:"name"`
+		Port     int    `schema:",omitempty"`
+		Computed string
+		Derived  int `json:"derived"`
+	}
+
+	keys := func(cache *StructMetadataCache) []string {
+		var keys []string
+		for _, field := range cache.GetMetadata(reflect.TypeFor[Config]()).flat {
+			keys = append(keys, field.MapKey)
+		}
+
+		return keys
+	}
+
+	t.Run("all exported fields by default", func(t *testing.T) {
+		assert.Equal(t, []string{"Audit", "by", "Cache", "name", "Port", "Computed", "Derived"},
+			keys(NewDefaultStructMetadataCache()))
+	})
+
+	t.Run("tagged fields only", func(t *testing.T) {
+		cache := NewStructMetadataCache(DefaultTagName, DefaultValueTagName, WithTaggedFieldsOnly())
+		assert.Equal(t, []string{"Audit", "by", "name", "Port"}, keys(cache))
+	})
+
+	t.Run("ignored with tags disabled", func(t *testing.T) {
+		cache := NewStructMetadataCache("-", DefaultValueTagName, WithTaggedFieldsOnly())
+		assert.Len(t, keys(cache), 7)
+	})
+
+	t.Run("decoding skips untagged fields", func(t *testing.T) {
+		cache := NewStructMetadataCache(DefaultTagName, DefaultValueTagName, WithTaggedFieldsOnly())
+		u := NewUnmarshaler(cache, NewDefaultConverterRegistry())
+
+		var result Config
+		data := map[string]any{"name": "app", "Computed": "x", "Cache": "y", "by": "me"}
+		require.NoError(t, u.Unmarshal(data, &result))
+		assert.Equal(t, Config{Audit: Audit{By: "me"}, Name: "app"}, result)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_TagError(t *testing.T) {
+	type Bad struct {
+		Name string `schema:"name,split='"`
+	}
+	type Outer struct {
+		Bad
+		ID string `schema:"id"`
+	}
+
+	for _, target := range []any{&Bad{}, &Outer{}} {
+		err := Unmarshal(map[string]any{"id": "1"}, target)
+
+		var tagErr *TagError
+		require.ErrorAs(t, err, &tagErr)
+		assert.Equal(t, "Name", tagErr.Field)
+		assert.Equal(t, reflect.TypeFor[Bad](), tagErr.Type)
+		assert.Contains(t, err.Error(), "unterminated quote")
+	}
+
+	var tagErr *TagError
+	require.ErrorAs(t, NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Outer]()).Err(), &tagErr)
+	assert.Equal(t, "Name", tagErr.Field)
+
+	var strs Bad
+	err := UnmarshalStrings(map[string]string{"name": "x"}, &strs)
+	require.ErrorAs(t, err, &tagErr)
+}
@@ -0,0 +1,192 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CallOption configures a single Unmarshal call.
+type CallOption func(*callOptions)
+
+// callOptions holds the per-call settings applied by CallOption values.
+type callOptions struct {
+	groups     []string
+	version    int
+	hasVersion bool
+	stats      *DecodeStats   // Counters filled in for the call, see WithStats
+	values     map[any]any    // User values attached to the call, see WithValue
+	quarantine map[string]any // Raw input of fields that failed to decode, see WithQuarantine
+	metadata   *Metadata      // Keys and fields used by the call, see UnmarshalWithMetadata
+	scratch    scratch        // Allocator of intermediate values, nil for the heap, see WithArena
+}
+
+// WithGroups selects the field groups decoded by the call. Fields tagged with a
+// "groups" option (e.g. `schema:"cost,groups=admin"`) are decoded only when one of
+// their groups is selected; fields without groups are always decoded.
+func WithGroups(groups ...string) CallOption {
+	return func(o *callOptions) {
+		o.groups = append(o.groups, groups...)
+	}
+}
+
+// Version range tag options, see WithVersion.
+const (
+	OptionSince = "since"
+	OptionUntil = "until"
+)
+
+// WithVersion selects the wire version decoded by the call. Fields tagged with
+// "since" or "until" (e.g. `schema:"email,since=2,until=3"`) are decoded, and receive
+// their defaults, only when version lies in their inclusive range. Without WithVersion
+// all fields are decoded regardless of their version range.
+func WithVersion(version int) CallOption {
+	return func(o *callOptions) {
+		o.version = version
+		o.hasVersion = true
+	}
+}
+
+// WithValue attaches value to the call under key, for delegating converters to read with
+// ConvertContext.Value, e.g. the requesting tenant or feature flags deciding which enum
+// values are allowed. As with context.Context, key must be comparable and should be of an
+// unexported type to avoid collisions; later values replace earlier ones for the same key.
+func WithValue(key, value any) CallOption {
+	return func(o *callOptions) {
+		if o.values == nil {
+			o.values = make(map[any]any)
+		}
+		o.values[key] = value
+	}
+}
+
+// decodeState holds the mutable state of a single Unmarshal call.
+type decodeState struct {
+	validationErrors []error // Errors reported by struct validators, see WithValidators
+	depth            int     // Nesting of the value being decoded, see WithMaxDepth
+
+	refs        map[string]reflect.Value // Pointers declared with an id, see WithReferences
+	pendingRefs []pendingRef             // References to objects not decoded when met
+
+	target  reflect.Type // Type decoded by the call
+	started time.Time    // Start of the call, when stats or metrics are requested
+}
+
+// beginCall returns the unmarshaler used for one Unmarshal call decoding a value of type
+// typ: a copy of u configured with opts and fresh per-call state, or u itself when the
+// call needs neither.
+func (u *Unmarshaler) beginCall(typ reflect.Type, opts []CallOption) *Unmarshaler {
+	if len(opts) == 0 && len(u.validators) == 0 && u.maxDepth == 0 && !u.references && u.metrics == nil {
+		return u
+	}
+
+	configured := *u
+	configured.call = callOptions{}
+	for _, opt := range opts {
+		opt(&configured.call)
+	}
+	configured.state = &decodeState{target: typ}
+	if stats := configured.call.stats; stats != nil {
+		*stats = DecodeStats{}
+	}
+	if configured.call.stats != nil || configured.metrics != nil {
+		configured.state.started = time.Now()
+	}
+
+	return &configured
+}
+
+// endCall completes an Unmarshal call started with beginCall, aggregating the errors
+// collected during decoding with err.
+func (u *Unmarshaler) endCall(err error) error {
+	if u.state == nil {
+		return err
+	}
+	if s := u.call.scratch; s != nil {
+		defer s.release()
+	}
+
+	if err == nil {
+		err = u.resolvePendingRefs()
+	}
+	if err == nil {
+		err = errors.Join(u.state.validationErrors...)
+	}
+	u.observeCall(err)
+
+	return err
+}
+
+// fieldSelected reports whether field belongs to the groups and version selected for the call.
+func (u *Unmarshaler) fieldSelected(field *FieldMetadata) bool {
+	if u.call.hasVersion {
+		if field.Since != 0 && u.call.version < field.Since {
+			return false
+		}
+		if field.Until != 0 && u.call.version > field.Until {
+			return false
+		}
+	}
+
+	if len(field.Groups) == 0 {
+		return true
+	}
+
+	for _, group := range field.Groups {
+		if slices.Contains(u.call.groups, group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseGroups splits the value of a "groups" tag option. Groups are separated by "|",
+// or by "," inside a quoted option value (groups='admin,internal').
+func parseGroups(value string) []string {
+	groups := strings.FieldsFunc(value, func(r rune) bool {
+		return r == '|' || r == ','
+	})
+
+	for i := range groups {
+		groups[i] = strings.TrimSpace(groups[i])
+	}
+
+	return slices.DeleteFunc(groups, func(g string) bool { return g == "" })
+}
+
+// parseVersions parses the "since" and "until" tag options of a field.
+// Missing options yield 0, meaning unbounded.
+func parseVersions(options map[string]string) (since, until int, err error) {
+	if since, err = parseVersion(options, OptionSince); err != nil {
+		return 0, 0, err
+	}
+	if until, err = parseVersion(options, OptionUntil); err != nil {
+		return 0, 0, err
+	}
+	if since != 0 && until != 0 && until < since {
+		return 0, 0, fmt.Errorf("until version %d is before since version %d", until, since)
+	}
+
+	return since, until, nil
+}
+
+// parseVersion parses the value of a "since" or "until" tag option.
+// A missing option yields 0, meaning unbounded.
+func parseVersion(options map[string]string, name string) (int, error) {
+	value, ok := options[name]
+	if !ok {
+		return 0, nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %s version %q", name, value)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,220 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroups(t *testing.T) {
+	assert.Equal(t, []string{"admin"}, parseGroups("admin"))
+	assert.Equal(t, []string{"admin", "internal"}, parseGroups("admin|internal"))
+	assert.Equal(t, []string{"admin", "internal"}, parseGroups("admin, internal"))
+	assert.Empty(t, parseGroups(""))
+	assert.Empty(t, parseGroups(" | "))
+}
+
+func TestUnmarshaler_Unmarshal_Groups(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `schema:"created_by"`
+	}
+
+	type Product struct {
+		Name  string  `schema:"name"`
+		Price float64 `schema:"price"`
+		Cost  float64 `schema:"cost,groups=admin"`
+		Notes string  `schema:"notes,groups='admin,support'" default:"none"`
+		Audit `schema:",groups=internal"`
+	}
+
+	data := map[string]any{
+		"name":       "Widget",
+		"price":      9.99,
+		"cost":       4.5,
+		"created_by": "alice",
+	}
+
+	t.Run("no groups decodes ungrouped fields only", func(t *testing.T) {
+		var result Product
+		err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, Product{Name: "Widget", Price: 9.99}, result)
+	})
+
+	t.Run("admin group", func(t *testing.T) {
+		var result Product
+		err := NewDefaultUnmarshaler().Unmarshal(data, &result, WithGroups("admin"))
+
+		require.NoError(t, err)
+		assert.InDelta(t, 4.5, result.Cost, 1e-9)
+		assert.Equal(t, "none", result.Notes)
+		assert.Empty(t, result.CreatedBy)
+	})
+
+	t.Run("multiple groups", func(t *testing.T) {
+		var result Product
+		err := Unmarshal(data, &result, WithGroups("support"), WithGroups("internal"))
+
+		require.NoError(t, err)
+		assert.Zero(t, result.Cost)
+		assert.Equal(t, "none", result.Notes)
+		assert.Equal(t, "alice", result.CreatedBy)
+	})
+
+	t.Run("call options do not leak into later calls", func(t *testing.T) {
+		u := NewDefaultUnmarshaler()
+
+		var first, second Product
+		require.NoError(t, u.Unmarshal(data, &first, WithGroups("admin")))
+		require.NoError(t, u.Unmarshal(data, &second))
+
+		assert.InDelta(t, 4.5, first.Cost, 1e-9)
+		assert.Zero(t, second.Cost)
+	})
+}
+
+func TestParseVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   map[string]string
+		wantSince int
+		wantUntil int
+		wantErr   string
+	}{
+		{name: "range", options: map[string]string{"since": "2", "until": "3"}, wantSince: 2, wantUntil: 3},
+		{name: "single version", options: map[string]string{"since": "2", "until": "2"}, wantSince: 2, wantUntil: 2},
+		{name: "unbounded", options: nil},
+		{name: "not a number", options: map[string]string{"since": "two"}, wantErr: `invalid since version "two"`},
+		{name: "negative", options: map[string]string{"until": "-1"}, wantErr: `invalid until version "-1"`},
+		{name: "empty", options: map[string]string{"since": ""}, wantErr: `invalid since version ""`},
+		{name: "reversed", options: map[string]string{"since": "3", "until": "2"}, wantErr: "until version 2 is before since version 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since, until, err := parseVersions(tt.options)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSince, since)
+			assert.Equal(t, tt.wantUntil, until)
+		})
+	}
+
+	var result struct {
+		Name string `schema:"name,since=abc"`
+	}
+	err := Unmarshal(map[string]any{}, &result)
+
+	var tagErr *TagError
+	require.ErrorAs(t, err, &tagErr)
+	assert.Equal(t, "Name", tagErr.Field)
+}
+
+func TestUnmarshaler_Unmarshal_Versions(t *testing.T) {
+	type Message struct {
+		ID       string `schema:"id"`
+		Name     string `schema:"name,until=1"`
+		FullName string `schema:"full_name,since=2"`
+		Priority int    `schema:"priority,since=2,until=3" default:"5"`
+		Urgent   bool   `schema:"urgent,since=4" default:"false"`
+		Extra    string `schema:"extra,since=2,groups=admin"`
+	}
+
+	data := map[string]any{
+		"id":        "m1",
+		"name":      "old",
+		"full_name": "new",
+		"extra":     "x",
+	}
+
+	tests := []struct {
+		name string
+		opts []CallOption
+		want Message
+	}{
+		{name: "no version decodes all fields", want: Message{ID: "m1", Name: "old", FullName: "new", Priority: 5}},
+		{name: "version 1", opts: []CallOption{WithVersion(1)}, want: Message{ID: "m1", Name: "old"}},
+		{name: "version 2 applies defaults in range", opts: []CallOption{WithVersion(2)}, want: Message{ID: "m1", FullName: "new", Priority: 5}},
+		{name: "version 4", opts: []CallOption{WithVersion(4)}, want: Message{ID: "m1", FullName: "new"}},
+		{name: "version and groups combine", opts: []CallOption{WithVersion(3), WithGroups("admin")}, want: Message{ID: "m1", FullName: "new", Priority: 5, Extra: "x"}},
+		{name: "groups without matching version", opts: []CallOption{WithVersion(1), WithGroups("admin")}, want: Message{ID: "m1", Name: "old"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Message
+			err := NewDefaultUnmarshaler().Unmarshal(data, &result, tt.opts...)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+type tenantKey struct{}
+
+func TestUnmarshaler_Unmarshal_WithValue(t *testing.T) {
+	type Plan string
+	type Account struct {
+		Plan  Plan   `schema:"plan"`
+		Plans []Plan `schema:"plans"`
+	}
+
+	allowed := map[string][]Plan{
+		"acme":    {"free", "pro"},
+		"initech": {"free", "pro", "enterprise"},
+	}
+	plan := func(value any, ctx *ConvertContext) (reflect.Value, error) {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		p := Plan(fmt.Sprint(value))
+		if !slices.Contains(allowed[tenant], p) {
+			return reflect.Value{}, fmt.Errorf("plan %q is not available to tenant %q", p, tenant)
+		}
+
+		return reflect.ValueOf(p), nil
+	}
+	converters := NewDefaultConverterRegistry().WithDelegatingConverters(map[reflect.Type]DelegatingConverter{
+		reflect.TypeFor[Plan](): plan,
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+	data := map[string]any{"plan": "enterprise", "plans": []any{"free", "enterprise"}}
+
+	tests := []struct {
+		name     string
+		opts     []CallOption
+		wantPath string
+	}{
+		{name: "allowed", opts: []CallOption{WithValue(tenantKey{}, "initech")}},
+		{name: "not allowed", opts: []CallOption{WithValue(tenantKey{}, "acme")}, wantPath: "plan"},
+		{name: "later values win", opts: []CallOption{WithValue(tenantKey{}, "initech"), WithValue(tenantKey{}, "acme")}, wantPath: "plan"},
+		{name: "missing value", wantPath: "plan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Account
+			err := u.Unmarshal(data, &result, tt.opts...)
+			if tt.wantPath == "" {
+				require.NoError(t, err)
+				assert.Equal(t, Account{Plan: "enterprise", Plans: []Plan{"free", "enterprise"}}, result)
+
+				return
+			}
+
+			var convErr *ConversionError
+			require.True(t, errors.As(err, &convErr))
+			assert.Equal(t, tt.wantPath, convErr.FieldPath)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Normalize returns data in the canonical shape of the struct type typ, without building the struct.
+// This is a convenience function that uses a shared default unmarshaler.
+func Normalize(data map[string]any, typ reflect.Type) (map[string]any, error) {
+	return defaultUnmarshaler.Normalize(data, typ)
+}
+
+// Normalize returns data in the canonical shape of the struct type typ, or a pointer to
+// one, for storing cleaned payloads: keyed by the canonical map keys Marshal produces and
+// with values coerced to the field types and marshaled back, so "42" for an int field
+// becomes 42 and a duration string its time.Duration. data is not modified.
+//
+// Only keys of data appear in the result: defaults are not added, nil values stay nil,
+// input that fails to decode is replaced with the field's fallback, and keys no field
+// accepts are dropped unless a remainder field collects them. Transformers rewrite the
+// input as when decoding, and nested maps of embedded structs are flattened into promoted
+// keys. Nested structs, pointers to structs and slices of them are normalized key by key;
+// other values are decoded into a value of the field type and marshaled. Fields with an
+// expr option keep their input value, so decoding the result gives the same struct as
+// decoding data. Decoding errors are reported as by Unmarshal; validators, verifiers and
+// default functions do not run, since no struct is built.
+func (u *Unmarshaler) Normalize(data map[string]any, typ reflect.Type) (map[string]any, error) {
+	if typ == nil || structType(typ).Kind() != reflect.Struct {
+		return nil, NewValidationError("type must be a struct or a pointer to a struct")
+	}
+
+	var result map[string]any
+	err := u.run(data, structType(typ), nil, func(call *Unmarshaler) error {
+		var err error
+		result, err = call.normalizeStruct(data, structType(typ), "")
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// normalizeStruct returns dataMap in the canonical shape of the struct type typ.
+func (u *Unmarshaler) normalizeStruct(dataMap map[string]any, typ reflect.Type, fieldPath string) (map[string]any, error) {
+	metadata := u.fieldCache.GetMetadata(typ)
+	if metadata.tagErr != nil {
+		return nil, metadata.tagErr
+	}
+
+	if err := u.enter(fieldPath); err != nil {
+		return nil, err
+	}
+	defer u.leave()
+
+	dataMap = u.transform(typ, dataMap)
+
+	if metadata.Options.Strict && metadata.remain == 0 {
+		if err := checkUnknownKeys(dataMap, metadata, fieldPath); err != nil {
+			return nil, err
+		}
+	}
+
+	closed := u.closedEmbeds(dataMap, metadata)
+	if err := u.checkOneOf(dataMap, metadata, closed, fieldPath); err != nil {
+		return nil, err
+	}
+	if err := u.checkFuncFields(dataMap, metadata, fieldPath); err != nil {
+		return nil, err
+	}
+	if err := u.checkUnsetFields(dataMap, metadata, closed, fieldPath); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]any, len(dataMap))
+	var nested []map[string]any // Promoted keys of embedded structs given as nested maps
+	for i := range metadata.flat {
+		field := &metadata.flat[i]
+		if !isOpen(closed, field) || !u.fieldSelected(field) || isRemain(field) {
+			continue
+		}
+
+		if field.Embedded {
+			if nestedData, ok := u.embeddedInput(dataMap, metadata, field); ok {
+				promoted, err := u.normalizeStruct(nestedData, structType(field.Type), fieldPath)
+				if err != nil {
+					return nil, err
+				}
+				nested = append(nested, promoted)
+			}
+
+			continue
+		}
+
+		value, ok := dataMap[field.MapKey]
+		if !ok {
+			continue
+		}
+
+		fullPath := buildFieldPath(fieldPath, field.MapKey)
+		normalized, err := u.normalizeField(value, field, fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fullPath, err)
+		}
+		result[field.MapKey] = normalized
+	}
+
+	// Keys of the struct's own fields take precedence over those of nested maps
+	for _, promoted := range nested {
+		for key, value := range promoted {
+			if _, ok := result[key]; !ok {
+				result[key] = value
+			}
+		}
+	}
+
+	// Keys no field accepts are kept for the remainder field, as marshaled
+	if metadata.remain != 0 && isOpen(closed, &metadata.flat[metadata.remain-1]) {
+		for key, value := range dataMap {
+			if _, ok := result[key]; !ok && !metadata.HasKey(key) {
+				result[key] = value
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// normalizeField returns the canonical value of the input value of field.
+func (u *Unmarshaler) normalizeField(value any, field *FieldMetadata, fieldPath string) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	// Expressions are evaluated on every decode, so the input is kept once it decodes
+	var normalized any
+	var err error
+	if source, ok := field.Option(OptionExpr); ok {
+		normalized, err = value, u.unmarshalExpr(value, reflect.New(field.Type).Elem(), fieldPath, source)
+	} else {
+		normalized, err = u.normalizeValue(value, field.Type, fieldPath, field)
+	}
+	if err == nil {
+		return normalized, nil
+	}
+
+	// Input that fails to decode is replaced with the canonical value of the fallback
+	if fallback, ok := field.Option(OptionFallback); ok {
+		if normalized, fallbackErr := u.normalizeValue(fallback, field.Type, fieldPath, field); fallbackErr == nil {
+			u.recordFallback(value, fieldPath, err)
+
+			return normalized, nil
+		}
+	}
+
+	return nil, err
+}
+
+// normalizeValue returns the canonical value of value for a field of type typ.
+func (u *Unmarshaler) normalizeValue(value any, typ reflect.Type, fieldPath string, field *FieldMetadata) (any, error) {
+	switch data := value.(type) {
+	case map[string]any:
+		if u.diffable(typ) {
+			return u.normalizeStruct(data, structType(typ), fieldPath)
+		}
+	case []any:
+		if typ.Kind() == reflect.Slice && !u.hasConverter(typ) && u.diffable(typ.Elem()) {
+			return u.normalizeElements(data, typ.Elem(), fieldPath, field)
+		}
+	}
+
+	decoded := reflect.New(typ).Elem()
+	if err := u.unmarshalValue(value, decoded, fieldPath, field); err != nil {
+		return nil, err
+	}
+	if err := u.normalizeStrings(decoded, fieldPath, field); err != nil {
+		return nil, err
+	}
+	if err := checkLength(decoded, fieldPath, field); err != nil {
+		return nil, err
+	}
+
+	return NewMarshaler(u.fieldCache).marshalValue(decoded, fieldPath, make(map[copyKey]struct{}))
+}
+
+// normalizeElements returns the canonical values of the struct elements of a slice of
+// elemType, dropping those that fail to normalize when field has the skipinvalid option.
+func (u *Unmarshaler) normalizeElements(elems []any, elemType reflect.Type, fieldPath string, field *FieldMetadata) ([]any, error) {
+	_, skipInvalid := field.Option(OptionSkipInvalid)
+
+	result := make([]any, 0, len(elems))
+	for i, elem := range elems {
+		elemPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+		if elem == nil {
+			result = append(result, nil)
+
+			continue
+		}
+
+		normalized, err := u.normalizeValue(elem, elemType, elemPath, nil)
+		if err != nil {
+			if skipInvalid {
+				continue
+			}
+
+			return nil, err
+		}
+		result = append(result, normalized)
+	}
+
+	if err := checkLength(reflect.ValueOf(result), fieldPath, field); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// structType returns the struct type of typ, a struct or a pointer to one.
+func structType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		return typ.Elem()
+	}
+
+	return typ
+}
@@ -0,0 +1,168 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type normalizeBase struct {
+	ID string `schema:"id"`
+}
+
+type normalizeLine struct {
+	SKU string `schema:"sku,upper"`
+	Qty int    `schema:"qty" default:"1"`
+}
+
+type normalizeOrder struct {
+	normalizeBase
+	Total  int             `schema:"total"`
+	Cents  int             `schema:"cents,expr=value*100"`
+	Rate   float64         `schema:"rate"`
+	Paid   *bool           `schema:"paid"`
+	Status string          `schema:"status" default:"new"`
+	Ship   *normalizeLine  `schema:"ship"`
+	Lines  []normalizeLine `schema:"lines,skipinvalid"`
+	Tags   []string        `schema:"tags"`
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]any
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "values coerced to field types",
+			data: map[string]any{"id": 7, "total": "42", "rate": "1.5", "paid": "true", "tags": []any{"a", 1}},
+			want: map[string]any{"id": "7", "total": 42, "rate": 1.5, "paid": true, "tags": []any{"a", "1"}},
+		},
+		{
+			name: "unknown keys dropped, defaults not added",
+			data: map[string]any{"id": "x", "extra": 1},
+			want: map[string]any{"id": "x"},
+		},
+		{
+			name: "nested structs normalized key by key",
+			data: map[string]any{
+				"ship":  map[string]any{"sku": "a1"},
+				"lines": []any{map[string]any{"sku": "b", "qty": "2"}, "invalid", nil},
+			},
+			want: map[string]any{
+				"ship":  map[string]any{"sku": "A1"},
+				"lines": []any{map[string]any{"sku": "B", "qty": 2}, nil},
+			},
+		},
+		{
+			name: "nil values and expressions kept",
+			data: map[string]any{"paid": nil, "cents": "12.5"},
+			want: map[string]any{"paid": nil, "cents": "12.5"},
+		},
+		{
+			name:    "decoding errors",
+			data:    map[string]any{"ship": map[string]any{"qty": "many"}},
+			wantErr: "ship.qty",
+		},
+		{
+			name:    "invalid expression input",
+			data:    map[string]any{"cents": "lots"},
+			wantErr: "cents",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.data, reflect.TypeFor[normalizeOrder]())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// The normalized map decodes into the same struct as the input
+			var fromInput, fromNormalized normalizeOrder
+			require.NoError(t, Unmarshal(tt.data, &fromInput))
+			require.NoError(t, Unmarshal(got, &fromNormalized))
+			assert.Equal(t, fromInput, fromNormalized)
+		})
+	}
+}
+
+func TestUnmarshaler_Normalize(t *testing.T) {
+	t.Run("pointer type", func(t *testing.T) {
+		got, err := Normalize(map[string]any{"total": "1"}, reflect.TypeFor[*normalizeOrder]())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"total": 1}, got)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := Normalize(map[string]any{}, reflect.TypeFor[map[string]any]())
+		require.ErrorAs(t, err, new(*ValidationError))
+
+		_, err = Normalize(map[string]any{}, nil)
+		require.ErrorAs(t, err, new(*ValidationError))
+	})
+
+	t.Run("nested embedded map flattened", func(t *testing.T) {
+		type Base struct {
+			ID string `schema:"id"`
+		}
+		type Event struct {
+			Base
+			Name string `schema:"name"`
+		}
+
+		got, err := Normalize(map[string]any{"Base": map[string]any{"id": 1}, "name": "a"}, reflect.TypeFor[Event]())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": "1", "name": "a"}, got)
+	})
+
+	t.Run("transformers rekey input", func(t *testing.T) {
+		u := NewDefaultUnmarshaler().WithTransformers(map[reflect.Type]Transformer{
+			reflect.TypeFor[normalizeLine](): func(data map[string]any) map[string]any {
+				rewritten := make(map[string]any, len(data))
+				for k, v := range data {
+					rewritten[strings.ToLower(k)] = v
+				}
+
+				return rewritten
+			},
+		})
+
+		data := map[string]any{"lines": []any{map[string]any{"SKU": "a", "Qty": 3}}}
+		got, err := u.Normalize(data, reflect.TypeFor[normalizeOrder]())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"lines": []any{map[string]any{"sku": "A", "qty": 3}}}, got)
+		assert.Equal(t, map[string]any{"SKU": "a", "Qty": 3}, data["lines"].([]any)[0], "input unchanged")
+	})
+
+	t.Run("remainder keys kept", func(t *testing.T) {
+		got, err := Normalize(map[string]any{"event": "push", "attempt": "2", "repo": "x"}, reflect.TypeFor[webhook]())
+		require.NoError(t, err)
+
+		var fromInput, fromNormalized webhook
+		require.NoError(t, Unmarshal(map[string]any{"event": "push", "attempt": "2", "repo": "x"}, &fromInput))
+		require.NoError(t, Unmarshal(got, &fromNormalized))
+		assert.Equal(t, fromInput, fromNormalized)
+		assert.Equal(t, "x", got["repo"])
+	})
+
+	t.Run("strict structs", func(t *testing.T) {
+		type Strict struct {
+			_    struct{} `schema:",strict"`
+			Name string   `schema:"name"`
+		}
+
+		_, err := Normalize(map[string]any{"name": "a", "other": 1}, reflect.TypeFor[Strict]())
+		assert.ErrorContains(t, err, `unknown key "other"`)
+	})
+}
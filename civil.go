@@ -0,0 +1,109 @@
+package mapstructure
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DateLayout is the layout used to parse and format Date values.
+	DateLayout = "2006-01-02"
+
+	// TimeOfDayLayout is the layout used to format TimeOfDay values.
+	TimeOfDayLayout = "15:04:05.999999999"
+)
+
+// timeOfDayLayouts lists the accepted layouts for time-of-day inputs, most specific first.
+var timeOfDayLayouts = []string{
+	"15:04:05.999999999",
+	"15:04:05",
+	"15:04",
+}
+
+// Date represents a calendar date without a time-of-day or location.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the Date in which t occurs in t's location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses a string in "2006-01-02" format into a Date.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("cannot parse %q as date", s)
+	}
+
+	return DateOf(t), nil
+}
+
+// String returns the date in "2006-01-02" format.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether d is the zero Date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns the time.Time at midnight of d in the given location.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// TimeOfDay represents a wall-clock time without a date or location.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// TimeOfDayOf returns the TimeOfDay at which t occurs in t's location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond()}
+}
+
+// ParseTimeOfDay parses a string in "15:04", "15:04:05" or "15:04:05.999999999" format.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := parseTimeOfDay(s)
+	if err != nil {
+		return TimeOfDay{}, err
+	}
+
+	return TimeOfDayOf(t), nil
+}
+
+// String returns the time in "15:04:05" format, with fractional seconds if non-zero.
+func (t TimeOfDay) String() string {
+	return t.On(Date{Year: 0, Month: time.January, Day: 1}, time.UTC).Format(TimeOfDayLayout)
+}
+
+// IsZero reports whether t is midnight.
+func (t TimeOfDay) IsZero() bool {
+	return t == TimeOfDay{}
+}
+
+// On returns the time.Time at t on date d in the given location.
+func (t TimeOfDay) On(d Date, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, loc)
+}
+
+// parseTimeOfDay parses a time-of-day string into a time.Time on the zero date (0000-01-01 UTC).
+func parseTimeOfDay(s string) (time.Time, error) {
+	for _, layout := range timeOfDayLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as time of day", s)
+}
@@ -0,0 +1,66 @@
+package mapstructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	d, err := ParseDate("2024-01-15")
+	require.NoError(t, err)
+	assert.Equal(t, Date{Year: 2024, Month: time.January, Day: 15}, d)
+	assert.Equal(t, "2024-01-15", d.String())
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), d.In(time.UTC))
+	assert.False(t, d.IsZero())
+	assert.True(t, Date{}.IsZero())
+
+	_, err = ParseDate("2024-13-01")
+	require.Error(t, err)
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    TimeOfDay
+		wantStr string
+		wantErr bool
+	}{
+		{name: "hours and minutes", input: "14:30", want: TimeOfDay{Hour: 14, Minute: 30}, wantStr: "14:30:00"},
+		{name: "with seconds", input: "14:30:15", want: TimeOfDay{Hour: 14, Minute: 30, Second: 15}, wantStr: "14:30:15"},
+		{
+			name:    "with fraction",
+			input:   "14:30:15.5",
+			want:    TimeOfDay{Hour: 14, Minute: 30, Second: 15, Nanosecond: 500000000},
+			wantStr: "14:30:15.5",
+		},
+		{name: "invalid", input: "25:00", wantErr: true},
+		{name: "garbage", input: "noon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimeOfDay(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantStr, got.String())
+		})
+	}
+}
+
+func TestTimeOfDay_On(t *testing.T) {
+	tod := TimeOfDay{Hour: 9, Minute: 15}
+	d := Date{Year: 2024, Month: time.March, Day: 2}
+
+	assert.Equal(t, time.Date(2024, 3, 2, 9, 15, 0, 0, time.UTC), tod.On(d, time.UTC))
+	assert.True(t, TimeOfDay{}.IsZero())
+	assert.Equal(t, tod, TimeOfDayOf(tod.On(d, time.UTC)))
+}
@@ -0,0 +1,182 @@
+// Command mapstructure-inspect prints how mapstructure decodes a struct type: the map
+// key of every field, including promoted ones, with its Go field path, type, default and
+// tag options, followed by the struct's options and the fields that cannot be decoded.
+//
+// Usage:
+//
+//	mapstructure-inspect [-tag name] [-default-tag name] [-docs] <package> <Type>
+//
+// The package is an import path or a relative directory, e.g. ./config. Types are only
+// known at run time, so the command writes a small program importing the package into
+// a temporary directory under the current one and runs it with "go run". Run it inside
+// the module of the package, which must require github.com/talav/mapstructure.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/talav/mapstructure"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// config describes the report the generated program writes.
+type config struct {
+	ImportPath string // Import path of the package declaring the type
+	Dir        string // Source directory of the package, set when docs are requested
+	Type       string // Name of the struct type
+	Tag        string // Struct tag read for map keys
+	DefaultTag string // Struct tag read for default values
+}
+
+// run runs the command with args and returns its exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mapstructure-inspect", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	tag := flags.String("tag", mapstructure.DefaultTagName, "struct tag read for map keys")
+	defaultTag := flags.String("default-tag", mapstructure.DefaultValueTagName, "struct tag read for default values")
+	docs := flags.Bool("docs", false, "show field doc comments parsed from the package source")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: mapstructure-inspect [-tag name] [-default-tag name] [-docs] <package> <Type>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 || !token.IsIdentifier(flags.Arg(1)) {
+		flags.Usage()
+
+		return 2
+	}
+
+	if err := inspect(flags.Arg(0), flags.Arg(1), *tag, *defaultTag, *docs, stdout, stderr); err != nil {
+		fmt.Fprintln(stderr, "mapstructure-inspect:", err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// inspect writes the report of the type typeName of pkg to stdout.
+func inspect(pkg, typeName, tag, defaultTag string, docs bool, stdout, stderr io.Writer) error {
+	importPath, dir, err := listPackage(pkg)
+	if err != nil {
+		return err
+	}
+
+	cfg := config{ImportPath: importPath, Type: typeName, Tag: tag, DefaultTag: defaultTag}
+	if docs {
+		cfg.Dir = dir
+	}
+
+	src, err := programSource(cfg)
+	if err != nil {
+		return err
+	}
+
+	return runProgram(src, stdout, stderr)
+}
+
+// listPackage resolves pkg with "go list" and returns its import path and directory.
+func listPackage(pkg string) (string, string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}\n{{.Dir}}", pkg)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("go list %s: %w: %s", pkg, err, strings.TrimSpace(stderr.String()))
+	}
+
+	importPath, dir, ok := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if !ok {
+		return "", "", fmt.Errorf("go list %s: unexpected output %q", pkg, out)
+	}
+
+	return importPath, dir, nil
+}
+
+var programTemplate = template.Must(template.New("program").Parse(`package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/talav/mapstructure"
+	"github.com/talav/mapstructure/inspect"
+
+	target {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	var opts []mapstructure.CacheOption
+{{- if .Dir}}
+	docs, err := mapstructure.ParseFieldDocs({{printf "%q" .ImportPath}}, {{printf "%q" .Dir}})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	opts = append(opts, mapstructure.WithFieldDocs(docs))
+{{- end}}
+
+	cache := mapstructure.NewStructMetadataCache({{printf "%q" .Tag}}, {{printf "%q" .DefaultTag}}, opts...)
+	u := mapstructure.NewUnmarshaler(cache, mapstructure.NewDefaultConverterRegistry())
+	if err := inspect.Write(os.Stdout, reflect.TypeFor[target.{{.Type}}](), cache, u); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// programSource returns the source of the program writing the report described by cfg.
+func programSource(cfg config) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := programTemplate.Execute(&buf, cfg); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// runProgram runs the program src with "go run" from a temporary directory under the
+// current one, so it builds within the current module.
+func runProgram(src []byte, stdout, stderr io.Writer) error {
+	dir, err := os.MkdirTemp(".", "_mapstructure_inspect_")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0o600); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); errors.As(err, &exitErr) {
+		return fmt.Errorf("inspection program failed: %w", err)
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
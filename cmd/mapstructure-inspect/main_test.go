@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramSource(t *testing.T) {
+	t.Run("without docs", func(t *testing.T) {
+		src, err := programSource(config{ImportPath: "example.com/app/config", Type: "Server", Tag: "json", DefaultTag: "default"})
+
+		require.NoError(t, err)
+		assert.Contains(t, string(src), `target "example.com/app/config"`)
+		assert.Contains(t, string(src), `mapstructure.NewStructMetadataCache("json", "default", opts...)`)
+		assert.Contains(t, string(src), `reflect.TypeFor[target.Server]()`)
+		assert.NotContains(t, string(src), "ParseFieldDocs")
+	})
+
+	t.Run("with docs", func(t *testing.T) {
+		src, err := programSource(config{ImportPath: "example.com/app", Dir: `/src/app "quoted"`, Type: "T", Tag: "schema", DefaultTag: "default"})
+
+		require.NoError(t, err)
+		assert.Contains(t, string(src), `mapstructure.ParseFieldDocs("example.com/app", "/src/app \"quoted\"")`)
+	})
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing arguments", args: []string{"./config"}},
+		{name: "invalid type name", args: []string{"./config", "pkg.Server"}},
+		{name: "unknown flag", args: []string{"-nope", "./config", "Server"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, 2, run(tt.args, &stdout, &stderr))
+			assert.Contains(t, stderr.String(), "usage: mapstructure-inspect")
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs an inspection program")
+	}
+
+	t.Run("report", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.Equal(t, 0, run([]string{"-docs", "github.com/talav/mapstructure", "FuzzTarget"}, &stdout, &stderr), stderr.String())
+
+		assert.Contains(t, stdout.String(), `mapstructure.FuzzTarget (tag "schema")`)
+		assert.Regexp(t, `(?m)^id\s+FuzzEmbedded\.ID\s+string`, stdout.String())
+		assert.Regexp(t, `(?m)^name\s+Name\s+string\s+anonymous\s+maxlen=64,minlen=1,trim`, stdout.String())
+	})
+
+	t.Run("unknown package", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 1, run([]string{"./missing", "Server"}, &stdout, &stderr))
+		assert.Contains(t, stderr.String(), "go list ./missing")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 1, run([]string{"github.com/talav/mapstructure", "Missing"}, &stdout, &stderr))
+		assert.Contains(t, stderr.String(), "inspection program failed")
+	})
+}
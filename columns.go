@@ -0,0 +1,86 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// UnmarshalColumns decodes column-major data into the slice pointed to by result using
+// the default unmarshaler. See Unmarshaler.UnmarshalColumns.
+func UnmarshalColumns(cols map[string][]any, result any, opts ...CallOption) error {
+	return defaultUnmarshaler.UnmarshalColumns(cols, result, opts...)
+}
+
+// UnmarshalColumns decodes column-major data, as exported by dataframes or Arrow-like
+// analytics payloads, into the slice pointed to by result (e.g. *[]Row or *[]*Row).
+// cols maps keys to parallel columns of equal length; row i is decoded from the map of
+// every key to its i-th value, with the same rules as Unmarshal, so defaults, converters
+// and validators apply per row. Errors are reported with the row index, e.g. "[2].age".
+// opts configure this call only, e.g. WithGroups("admin").
+func (u *Unmarshaler) UnmarshalColumns(cols map[string][]any, result any, opts ...CallOption) error {
+	rv, err := validateResultPointer(result)
+	if err != nil {
+		return err
+	}
+	if rv.Kind() != reflect.Slice {
+		return NewValidationError("result must be a pointer to a slice")
+	}
+	if err := u.validateTarget(rv.Type().Elem()); err != nil {
+		return err
+	}
+	if err := u.checkNilInput(cols == nil); err != nil {
+		return err
+	}
+
+	// Malformed tags of the row type are reported even without rows, as by Unmarshal
+	if row := structType(rv.Type().Elem()); row.Kind() == reflect.Struct && !u.hasConverter(row) {
+		if err := u.fieldCache.GetMetadata(row).Err(); err != nil {
+			return err
+		}
+	}
+
+	call := u.beginCall(rv.Type(), opts)
+	rows, err := call.zipColumns(cols, rv.Type().Elem())
+	if err != nil {
+		return call.endCall(err)
+	}
+
+	return call.endCall(call.unmarshalValue(rows, rv, "", nil))
+}
+
+// zipColumns turns parallel columns into rows, one map per index, for a slice of elemType.
+func (u *Unmarshaler) zipColumns(cols map[string][]any, elemType reflect.Type) ([]any, error) {
+	keys := make([]string, 0, len(cols))
+	for key := range cols {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	length := -1
+	for _, key := range keys {
+		switch n := len(cols[key]); {
+		case length < 0:
+			length = n
+		case n != length:
+			return nil, NewValidationError(fmt.Sprintf("column %q has %d values, column %q has %d", key, n, keys[0], length))
+		}
+	}
+
+	// Rows are only referenced during the call, unless assigned as they are to []any
+	var rows []any
+	if elemType.Kind() == reflect.Interface {
+		rows = make([]any, max(length, 0))
+	} else {
+		rows = u.scratchAnySlice(max(length, 0))
+	}
+	for i := range rows {
+		row := make(map[string]any, len(keys))
+		for _, key := range keys {
+			row[key] = cols[key][i]
+		}
+		rows[i] = row
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,125 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_UnmarshalColumns(t *testing.T) {
+	type Row struct {
+		City  string  `schema:"city"`
+		Temp  float64 `schema:"temp"`
+		Rainy bool    `schema:"rainy" default:"false"`
+		Unit  string  `schema:"unit" default:"C"`
+	}
+
+	cols := map[string][]any{
+		"city":  {"Paris", "Oslo", "Rome"},
+		"temp":  {21.5, "-3", 30},
+		"rainy": {true, false, 0},
+	}
+
+	t.Run("rows", func(t *testing.T) {
+		var rows []Row
+		require.NoError(t, UnmarshalColumns(cols, &rows))
+		assert.Equal(t, []Row{
+			{City: "Paris", Temp: 21.5, Rainy: true, Unit: "C"},
+			{City: "Oslo", Temp: -3, Unit: "C"},
+			{City: "Rome", Temp: 30, Unit: "C"},
+		}, rows)
+	})
+
+	t.Run("pointer rows replace existing content", func(t *testing.T) {
+		rows := []*Row{{City: "stale"}, {}, {}, {}}
+		require.NoError(t, UnmarshalColumns(cols, &rows))
+		require.Len(t, rows, 3)
+		assert.Equal(t, "Paris", rows[0].City)
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		rows := []Row{{City: "stale"}}
+		require.NoError(t, UnmarshalColumns(map[string][]any{}, &rows))
+		assert.Empty(t, rows)
+	})
+
+	t.Run("row errors carry the index", func(t *testing.T) {
+		var rows []Row
+		err := UnmarshalColumns(map[string][]any{"city": {"a", "b"}, "temp": {1, "hot"}}, &rows)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "[1].temp", convErr.FieldPath)
+	})
+
+	t.Run("call options and validators apply per row", func(t *testing.T) {
+		validated := 0
+		u := NewDefaultUnmarshaler().WithValidators(map[reflect.Type]Validator{
+			reflect.TypeFor[Row](): func(structPtr any) error {
+				validated++
+				if row, _ := structPtr.(*Row); row.Temp > 25 {
+					return errors.New("too hot")
+				}
+
+				return nil
+			},
+		})
+
+		var stats DecodeStats
+		var rows []Row
+		err := u.UnmarshalColumns(cols, &rows, WithStats(&stats))
+		var validationErr *StructValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "[2]", validationErr.FieldPath)
+		assert.Equal(t, 3, validated)
+		assert.Equal(t, 12, stats.FieldsSet)
+	})
+
+	t.Run("nil input policy", func(t *testing.T) {
+		rows := []Row{{City: "stale"}}
+		err := NewDefaultUnmarshaler(WithNilInputPolicy(NilInputError)).UnmarshalColumns(nil, &rows)
+		require.ErrorIs(t, err, ErrNilInput)
+		assert.Equal(t, []Row{{City: "stale"}}, rows, "result unchanged")
+
+		require.NoError(t, UnmarshalColumns(nil, &rows))
+		assert.Empty(t, rows)
+	})
+
+	t.Run("unsupported rows", func(t *testing.T) {
+		var rows []func()
+		var targetErr *UnsupportedTargetError
+		require.ErrorAs(t, UnmarshalColumns(cols, &rows), &targetErr)
+	})
+
+	t.Run("malformed tags", func(t *testing.T) {
+		type Broken struct {
+			Bad int `schema:"bad,squash"`
+		}
+
+		var rows []*Broken
+		var tagErr *TagError
+		require.ErrorAs(t, UnmarshalColumns(map[string][]any{}, &rows), &tagErr)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			cols   map[string][]any
+			result any
+		}{
+			{name: "uneven columns", cols: map[string][]any{"city": {"a"}, "temp": {1, 2}}, result: &[]Row{}},
+			{name: "not a pointer", cols: cols, result: []Row{}},
+			{name: "not a slice", cols: cols, result: &Row{}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, UnmarshalColumns(tt.cols, tt.result), &validationErr)
+			})
+		}
+	})
+}
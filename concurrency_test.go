@@ -0,0 +1,155 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests share one Unmarshaler between goroutines with every hook and option
+// enabled; run them with -race to check that decoding does not write shared state.
+
+type sharedAddress struct {
+	City string `schema:"city,trim,sanitize=space"`
+	Zip  string `schema:"zip" default:"00000"`
+}
+
+type sharedUser struct {
+	ID       int                 `schema:"id"`
+	Name     string              `schema:"name,minlen=1"`
+	Greeting string              `schema:"greeting" default:"hello {{.Name}}"`
+	Created  time.Time           `schema:"created"`
+	Tags     []string            `schema:"tags,split=,"`
+	Address  *sharedAddress      `schema:"address"`
+	Friends  []*sharedUser       `schema:"friends"`
+	Extra    map[string]any      `schema:"extra"`
+	Roles    map[string]struct{} `schema:"roles"`
+}
+
+func newSharedUnmarshaler(metrics Metrics) *Unmarshaler {
+	userType := reflect.TypeFor[sharedUser]()
+
+	return NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry().WithAuthoritative(timeType),
+		WithMaxDepth(32), WithReferences(nil), WithMetrics(metrics), WithAnyPolicy(AnyCopy|AnyNumbers), WithCopyReferences(),
+	).WithValidators(map[reflect.Type]Validator{
+		userType: func(structPtr any) error {
+			if user, _ := structPtr.(*sharedUser); user != nil && user.ID < 0 {
+				return errors.New("negative id")
+			}
+
+			return nil
+		},
+	}).WithTransformers(map[reflect.Type]Transformer{
+		userType: func(data map[string]any) map[string]any {
+			if _, ok := data["name"]; ok {
+				return data
+			}
+			out := make(map[string]any, len(data)+1)
+			for k, v := range data {
+				out[k] = v
+			}
+			out["name"] = "anonymous"
+
+			return out
+		},
+	})
+}
+
+func sharedUserInput(i int) map[string]any {
+	return map[string]any{
+		"id":      i,
+		"created": "2024-01-02T03:04:05Z",
+		"tags":    "a,b,c",
+		"address": map[string]any{"city": "  Paris  "},
+		"friends": []any{
+			map[string]any{"$id": fmt.Sprintf("user-%d", i+1), "id": i + 1, "name": "friend"},
+			map[string]any{"$ref": fmt.Sprintf("user-%d", i+1)},
+		},
+		"extra": map[string]any{"n": i},
+		"roles": []any{"admin", "user"},
+	}
+}
+
+func TestUnmarshaler_ConcurrentUse(t *testing.T) {
+	metrics := &recordingMetrics{}
+	u := newSharedUnmarshaler(metrics)
+	decoder := NewDecoder[sharedUser](u)
+	variant := u.With(WithIterationStrategy(IterateKeys))
+
+	const goroutines = 16
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Go(func() {
+			for i := range 20 {
+				id := g*100 + i
+				var stats DecodeStats
+
+				var user sharedUser
+				if !assert.NoError(t, u.Unmarshal(sharedUserInput(id), &user, WithStats(&stats))) {
+					return
+				}
+				assert.Equal(t, id, user.ID)
+				assert.Equal(t, "Paris", user.Address.City)
+				assert.Equal(t, "hello anonymous", user.Greeting)
+				assert.Same(t, user.Friends[0], user.Friends[1])
+				assert.Positive(t, stats.FieldsSet)
+
+				decoded, err := decoder.Decode(sharedUserInput(id), WithGroups("admin"))
+				assert.NoError(t, err)
+				assert.Equal(t, id, decoded.ID)
+
+				var viaVariant sharedUser
+				assert.NoError(t, variant.Unmarshal(sharedUserInput(id), &viaVariant))
+
+				var failed sharedUser
+				assert.Error(t, u.Unmarshal(map[string]any{"id": -1}, &failed))
+
+				_, err = u.ConvertValue("42", reflect.TypeFor[int]())
+				assert.NoError(t, err)
+
+				assert.NoError(t, u.UnmarshalStrings(map[string]string{"city": "Rome"}, &sharedAddress{}))
+
+				_, err = u.Diff(&user, map[string]any{"name": strings.Repeat("x", i+1)})
+				assert.NoError(t, err)
+
+				_, err = Marshal(&decoded)
+				assert.NoError(t, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	// Seven calls per iteration: three decodes, a failed decode, ConvertValue, UnmarshalStrings and Diff
+	assert.Len(t, metrics.decodes, goroutines*20*7, "every call is observed")
+}
+
+func TestStructMetadataCache_ConcurrentBuild(t *testing.T) {
+	cache := NewDefaultStructMetadataCache()
+	types := []reflect.Type{
+		reflect.TypeFor[sharedUser](), reflect.TypeFor[sharedAddress](), reflect.TypeFor[treeNode](), reflect.TypeFor[FuzzTarget](),
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]*StructMetadata, 8)
+	for g := range results {
+		wg.Go(func() {
+			for _, typ := range types {
+				results[g] = append(results[g], cache.GetMetadata(typ))
+			}
+		})
+	}
+	wg.Wait()
+
+	for _, got := range results[1:] {
+		for i := range types {
+			require.Same(t, results[0][i], got[i], "all goroutines share the cached metadata of %v", types[i])
+		}
+	}
+}
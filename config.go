@@ -0,0 +1,97 @@
+package mapstructure
+
+// Config is a snapshot of the effective settings of an Unmarshaler, returned by
+// Unmarshaler.Config so frameworks embedding the package can log or debug their decoding
+// configuration. It is a plain value: changing it does not affect the unmarshaler.
+// Strictness is not listed, since it is set per struct (see Struct Options).
+type Config struct {
+	TagName          string // Struct tag read for field mapping, e.g. "schema"
+	DefaultTagName   string // Struct tag read for default values, e.g. "default"
+	TaggedFieldsOnly bool   // Fields without the tag are skipped, see WithTaggedFieldsOnly
+	DashKey          bool   // Fields tagged "-," map to the key "-", see WithDashKey
+	FieldDocs        int    // Types with field doc comments, see WithFieldDocs
+
+	Converters           int // Registered converters, including field and delegating converters
+	FieldConverters      int // Registered field converters
+	DelegatingConverters int // Registered delegating converters
+	AuthoritativeTypes   int // Types whose converters run before assignment, see ConverterRegistry.WithAuthoritative
+	ConvertersFirst      bool
+
+	Transformers int // Registered input transformers, see WithTransformers
+	DefaultFuncs int // Registered default funcs, see WithDefaultFuncs
+	Validators   int // Registered struct validators, see WithValidators
+	Verifiers    int // Registered struct verifiers, see WithVerifiers
+	Hooks        int // Registered decode hooks, see WithHooks
+	Sanitizers   int // Registered sanitizers, see WithSanitizers
+	ExprFuncs    int // Registered expression functions, see WithExprFuncs
+
+	AnyPolicy       AnyPolicy
+	CopyReferences  bool
+	VerifyInput     bool
+	Iteration       IterationStrategy
+	FuncFieldPolicy FuncFieldPolicy
+	NullPolicy      NullPolicy
+	NilInputPolicy  NilInputPolicy
+	EmbeddedPolicy  EmbeddedPolicy
+	DuplicateKeys   DuplicateKeyPolicy
+	BytesAsStrings  bool // []byte input decodes like strings, see WithBytesAsStrings
+	ErrorUnset      bool // Fields receiving no value fail, see WithErrorUnset
+	MaxDepth        int  // 0 when input nesting is not limited
+	PrepareDefaults bool // Converted defaults are cached, see WithPreparedDefaults
+	NoDefaults      bool // Default tags and default funcs are ignored, see WithoutDefaults
+	YAML            bool // YAML bodies are decoded, see WithYAMLUnmarshal
+	References      bool // "$ref" input is resolved, see WithReferences
+	RefResolver     bool // A RefResolver is set
+	Metrics         bool // A Metrics hook is set
+	Logger          bool // A logger is set, see WithLogger
+}
+
+// Config returns a snapshot of the effective settings of u.
+func (u *Unmarshaler) Config() Config {
+	config := Config{
+		Transformers:    len(u.transformers),
+		DefaultFuncs:    len(u.defaultFuncs),
+		Validators:      len(u.validators),
+		Verifiers:       len(u.verifiers),
+		Hooks:           len(u.hooks),
+		Sanitizers:      len(u.sanitizers),
+		ExprFuncs:       len(u.exprFuncs),
+		ConvertersFirst: u.convertersFirst,
+		AnyPolicy:       u.anyPolicy,
+		CopyReferences:  u.copyReferences,
+		VerifyInput:     u.verifyInput,
+		Iteration:       u.iteration,
+		FuncFieldPolicy: u.funcPolicy,
+		NullPolicy:      u.nullPolicy,
+		NilInputPolicy:  u.nilInput,
+		EmbeddedPolicy:  u.embeddedPolicy,
+		DuplicateKeys:   u.duplicates,
+		BytesAsStrings:  u.bytesAsStrings,
+		ErrorUnset:      u.errorUnset,
+		MaxDepth:        u.maxDepth,
+		PrepareDefaults: u.defaults != nil,
+		NoDefaults:      u.noDefaults,
+		YAML:            u.yamlUnmarshal != nil,
+		References:      u.references,
+		RefResolver:     u.resolver != nil,
+		Metrics:         u.metrics != nil,
+		Logger:          u.logger != nil,
+	}
+
+	if c := u.fieldCache; c != nil {
+		config.TagName = c.tagName
+		config.DefaultTagName = c.defaultTagName
+		config.TaggedFieldsOnly = c.taggedOnly
+		config.DashKey = c.dashKey
+		config.FieldDocs = len(c.docs)
+	}
+
+	if r := u.converters; r != nil {
+		config.FieldConverters = len(r.fieldConverters)
+		config.DelegatingConverters = len(r.delegatingConverters)
+		config.Converters = len(r.converters) + config.FieldConverters + config.DelegatingConverters
+		config.AuthoritativeTypes = len(r.authoritative)
+	}
+
+	return config
+}
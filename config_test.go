@@ -0,0 +1,77 @@
+package mapstructure
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_Config(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		config := NewDefaultUnmarshaler().Config()
+
+		assert.Equal(t, DefaultTagName, config.TagName)
+		assert.Equal(t, DefaultValueTagName, config.DefaultTagName)
+		assert.False(t, config.TaggedFieldsOnly)
+		assert.Equal(t, len(NewDefaultConverterRegistry().converters)+3, config.Converters)
+		assert.Equal(t, 3, config.FieldConverters, "time.Time, Date and TimeOfDay field converters")
+		assert.Zero(t, config.DelegatingConverters)
+		assert.Zero(t, config.Validators)
+		assert.Equal(t, IterateAuto, config.Iteration)
+		assert.Zero(t, config.MaxDepth)
+		assert.False(t, config.Metrics)
+		assert.False(t, config.Logger)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		cache := NewStructMetadataCache("json", "fallback", WithTaggedFieldsOnly(), WithDashKey(),
+			WithFieldDocs(FieldDocs{"example.com/app.Server": {"Port": "Listening port."}}))
+		converters := NewConverterRegistry(map[reflect.Type]Converter{reflect.TypeFor[int](): convertInt}).
+			WithDelegatingConverters(map[reflect.Type]DelegatingConverter{
+				timeType: func(value any, ctx *ConvertContext) (reflect.Value, error) { return reflect.Value{}, ErrSkipConverter },
+			}).
+			WithAuthoritative(timeType)
+		u := NewUnmarshaler(cache, converters,
+			WithMaxDepth(32), WithReferences(nil), WithMetrics(&recordingMetrics{}), WithConvertersFirst(),
+			WithAnyPolicy(AnyCopy), WithCopyReferences(), WithIterationStrategy(IterateKeys), WithFuncFieldPolicy(FuncFieldsError),
+			WithNullPolicy(NullsKeep), WithNilInputPolicy(NilInputError), WithBytesAsStrings(), WithErrorUnset(),
+			WithEmbeddedPolicy(EmbeddedPromotedFirst), WithPreparedDefaults(), WithoutDefaults(), WithDuplicateKeyPolicy(DuplicatesError),
+			WithYAMLUnmarshal(func([]byte, any) error { return nil }), WithLogger(slog.New(slog.DiscardHandler)),
+		).WithValidators(map[reflect.Type]Validator{
+			reflect.TypeFor[struct{}](): func(value any) error { return nil },
+		}).WithHooks(func(value any, from, to reflect.Type) (any, error) { return value, nil })
+
+		assert.Equal(t, Config{
+			TagName:              "json",
+			DefaultTagName:       "fallback",
+			TaggedFieldsOnly:     true,
+			DashKey:              true,
+			FieldDocs:            1,
+			Converters:           2,
+			DelegatingConverters: 1,
+			AuthoritativeTypes:   1,
+			ConvertersFirst:      true,
+			Validators:           1,
+			Hooks:                1,
+			AnyPolicy:            AnyCopy,
+			CopyReferences:       true,
+			Iteration:            IterateKeys,
+			FuncFieldPolicy:      FuncFieldsError,
+			NullPolicy:           NullsKeep,
+			NilInputPolicy:       NilInputError,
+			EmbeddedPolicy:       EmbeddedPromotedFirst,
+			DuplicateKeys:        DuplicatesError,
+			BytesAsStrings:       true,
+			ErrorUnset:           true,
+			MaxDepth:             32,
+			PrepareDefaults:      true,
+			NoDefaults:           true,
+			YAML:                 true,
+			References:           true,
+			Metrics:              true,
+			Logger:               true,
+		}, u.Config())
+	})
+}
@@ -0,0 +1,195 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The conformance tests decode the same JSON documents with encoding/json and, through
+// an intermediate map, with an unmarshaler configured for the same semantics, and
+// expect identical results.
+
+type conformanceAudit struct {
+	By   string `json:"by"`
+	Note string `json:"note"`
+}
+
+type ConformanceOwner struct {
+	Owner string `json:"owner"`
+}
+
+type ConformanceLeft struct {
+	Label string
+}
+
+type ConformanceRight struct {
+	Label string
+}
+
+type ConformanceTags struct {
+	Name     string `json:"name"`
+	Skipped  string `json:"-"`
+	Dash     string `json:"-,"`
+	Untagged string
+	Empty    string `json:",omitempty"`
+	hidden   string //nolint:unused // Unexported fields are never decoded
+}
+
+type ConformanceEmbedded struct {
+	conformanceAudit
+	*ConformanceOwner
+	ConformanceLeft
+	ConformanceRight
+	Note string `json:"note"`
+}
+
+type ConformanceNamedEmbed struct {
+	ConformanceLeft `json:"left"`
+	Name            string `json:"name"`
+}
+
+type ConformancePointers struct {
+	Count   *int                `json:"count"`
+	Nested  **string            `json:"nested"`
+	Audit   *conformanceAudit   `json:"audit"`
+	Items   []*conformanceAudit `json:"items"`
+	Numbers []*int              `json:"numbers"`
+}
+
+type ConformanceNulls struct {
+	Name   string           `json:"name"`
+	Count  int              `json:"count"`
+	Ok     bool             `json:"ok"`
+	Audit  conformanceAudit `json:"audit"`
+	Ptr    *int             `json:"ptr"`
+	Tags   []string         `json:"tags"`
+	Labels map[string]any   `json:"labels"`
+	Extra  any              `json:"extra"`
+	Pair   [2]int           `json:"pair"`
+	Counts []int            `json:"counts"`
+}
+
+// newConformanceUnmarshaler returns an unmarshaler matching encoding/json where the
+// two overlap.
+func newConformanceUnmarshaler() *Unmarshaler {
+	return NewUnmarshaler(
+		NewStructMetadataCache("json", "", WithDashKey()),
+		NewDefaultConverterRegistry(),
+		WithNullPolicy(NullsKeep),
+	)
+}
+
+// assertJSONConformance decodes input into copies of seed with encoding/json and u,
+// and asserts the results are equal.
+func assertJSONConformance[T any](t *testing.T, u *Unmarshaler, seed func() T, input string) {
+	t.Helper()
+
+	want := seed()
+	require.NoError(t, json.Unmarshal([]byte(input), &want))
+
+	var data map[string]any
+	require.NoError(t, json.Unmarshal([]byte(input), &data))
+
+	got := seed()
+	require.NoError(t, u.Unmarshal(data, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestConformance_Tags(t *testing.T) {
+	u := newConformanceUnmarshaler()
+	seed := func() ConformanceTags { return ConformanceTags{Skipped: "keep"} }
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "tagged and untagged fields", input: `{"name":"a","Untagged":"b","Empty":"c"}`},
+		{name: "dash skips the field", input: `{"-":"x","Skipped":"y"}`},
+		{name: "dash with comma is the key", input: `{"-":"x"}`},
+		{name: "unexported fields are ignored", input: `{"hidden":"x"}`},
+		{name: "unknown keys are ignored", input: `{"other":1,"name":"a"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSONConformance(t, u, seed, tt.input)
+		})
+	}
+}
+
+func TestConformance_EmbeddedPromotion(t *testing.T) {
+	u := newConformanceUnmarshaler()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "fields promoted from an unexported struct", input: `{"by":"me"}`},
+		{name: "shallower field shadows promoted one", input: `{"note":"outer"}`},
+		{name: "fields tied at the same depth are dropped", input: `{"Label":"x"}`},
+		{name: "embedded pointer allocated", input: `{"owner":"me"}`},
+		{name: "embedded pointer left nil", input: `{"note":"x"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSONConformance(t, u, func() ConformanceEmbedded { return ConformanceEmbedded{} }, tt.input)
+		})
+	}
+
+	t.Run("tagged embedded struct is a named field", func(t *testing.T) {
+		assertJSONConformance(t, u, func() ConformanceNamedEmbed { return ConformanceNamedEmbed{} },
+			`{"left":{"Label":"x"},"Label":"y","name":"n"}`)
+	})
+}
+
+func TestConformance_PointerAllocation(t *testing.T) {
+	u := newConformanceUnmarshaler()
+	empty := func() ConformancePointers { return ConformancePointers{} }
+
+	t.Run("nil pointers are allocated", func(t *testing.T) {
+		assertJSONConformance(t, u, empty,
+			`{"count":1,"nested":"x","audit":{"by":"me"},"items":[{"by":"a"},null],"numbers":[1,null]}`)
+	})
+
+	t.Run("existing pointers are reused", func(t *testing.T) {
+		audit := &conformanceAudit{By: "old", Note: "kept"}
+		seed := func() ConformancePointers { return ConformancePointers{Audit: audit} }
+		assertJSONConformance(t, u, seed, `{"audit":{"by":"new"}}`)
+
+		result := seed()
+		var data map[string]any
+		require.NoError(t, json.Unmarshal([]byte(`{"audit":{"by":"new"}}`), &data))
+		require.NoError(t, u.Unmarshal(data, &result))
+		assert.Same(t, audit, result.Audit)
+	})
+}
+
+func TestConformance_Nulls(t *testing.T) {
+	u := newConformanceUnmarshaler()
+	count := 1
+	seed := func() ConformanceNulls {
+		return ConformanceNulls{
+			Name: "keep", Count: 2, Ok: true, Audit: conformanceAudit{By: "me"}, Ptr: &count,
+			Tags: []string{"a"}, Labels: map[string]any{"a": 1.0}, Extra: "x", Pair: [2]int{1, 2},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "non-nillable values are kept", input: `{"name":null,"count":null,"ok":null,"audit":null,"pair":null}`},
+		{name: "nillable values are cleared", input: `{"ptr":null,"tags":null,"labels":null,"extra":null}`},
+		{name: "null elements are zero", input: `{"counts":[1,null,3]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSONConformance(t, u, seed, tt.input)
+		})
+	}
+}
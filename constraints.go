@@ -0,0 +1,91 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Length constraint tag options.
+const (
+	OptionMinLen = "minlen"
+	OptionMaxLen = "maxlen"
+)
+
+// checkLength enforces the "minlen" and "maxlen" tag options on a decoded field value.
+// Strings are measured in runes; slices, arrays and maps by their element count.
+// Nil pointers are not checked, non-nil pointers are checked through.
+func checkLength(rv reflect.Value, fieldPath string, field *FieldMetadata) error {
+	minLen, hasMin := field.Option(OptionMinLen)
+	maxLen, hasMax := field.Option(OptionMaxLen)
+	if !hasMin && !hasMax {
+		return nil
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	var length int
+
+	//nolint:exhaustive // Only handling types with a length
+	switch rv.Kind() {
+	case reflect.String:
+		length = utf8.RuneCountInString(rv.String())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		length = rv.Len()
+	default:
+		constraint := OptionMinLen
+		if !hasMin {
+			constraint = OptionMaxLen
+		}
+
+		return NewConstraintError(fieldPath, constraint, fmt.Sprintf("length constraints do not apply to %v", rv.Type()))
+	}
+
+	if hasMin {
+		limit, err := parseLengthLimit(fieldPath, OptionMinLen, minLen)
+		if err != nil {
+			return err
+		}
+		if length < limit {
+			return NewConstraintError(fieldPath, OptionMinLen, fmt.Sprintf("length %d is less than minimum %d", length, limit))
+		}
+	}
+
+	if hasMax {
+		limit, err := parseLengthLimit(fieldPath, OptionMaxLen, maxLen)
+		if err != nil {
+			return err
+		}
+		if length > limit {
+			return NewConstraintError(fieldPath, OptionMaxLen, fmt.Sprintf("length %d exceeds maximum %d", length, limit))
+		}
+	}
+
+	return nil
+}
+
+// parseLengthLimit parses the value of a length constraint tag option.
+func parseLengthLimit(fieldPath, constraint, value string) (int, error) {
+	limit, err := parseLength(constraint, value)
+	if err != nil {
+		return 0, NewConstraintError(fieldPath, constraint, err.Error())
+	}
+
+	return limit, nil
+}
+
+// parseLength parses value as a length limit for the constraint tag option.
+func parseLength(constraint, value string) (int, error) {
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid %s value %q", constraint, value)
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,88 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_LengthConstraints(t *testing.T) {
+	type Nested struct {
+		Code string `schema:"code,minlen=2,maxlen=3"`
+	}
+
+	type Payload struct {
+		Name   string              `schema:"name,minlen=1,maxlen=5"`
+		Tags   []string            `schema:"tags,maxlen=2"`
+		Note   *string             `schema:"note,maxlen=3"`
+		Roles  map[string]struct{} `schema:"roles,minlen=1"`
+		Nested Nested              `schema:"nested"`
+		Count  int                 `schema:"count,maxlen=1"`
+	}
+
+	tests := []struct {
+		name           string
+		data           map[string]any
+		wantPath       string
+		wantConstraint string
+		wantMsg        string
+	}{
+		{name: "valid", data: map[string]any{"name": "héllo", "tags": []any{"a", "b"}, "note": "abc", "roles": []any{"x"}}},
+		{name: "missing fields are not checked", data: map[string]any{}},
+		{name: "string too short", data: map[string]any{"name": ""}, wantPath: "name", wantConstraint: "minlen", wantMsg: "name: length 0 is less than minimum 1"},
+		{name: "string too long", data: map[string]any{"name": "abcdef"}, wantPath: "name", wantConstraint: "maxlen", wantMsg: "name: length 6 exceeds maximum 5"},
+		{name: "slice too long", data: map[string]any{"tags": []any{"a", "b", "c"}}, wantPath: "tags", wantConstraint: "maxlen", wantMsg: "tags: length 3 exceeds maximum 2"},
+		{name: "pointer checked through", data: map[string]any{"note": "abcd"}, wantPath: "note", wantConstraint: "maxlen"},
+		{name: "set too small", data: map[string]any{"roles": []any{}}, wantPath: "roles", wantConstraint: "minlen"},
+		{name: "nested path", data: map[string]any{"nested": map[string]any{"code": "x"}}, wantPath: "nested.code", wantConstraint: "minlen"},
+		{name: "non-length type", data: map[string]any{"count": 1}, wantPath: "count", wantConstraint: "maxlen", wantMsg: "count: length constraints do not apply to int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Payload
+			err := NewDefaultUnmarshaler().Unmarshal(tt.data, &result)
+			if tt.wantPath == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var constraintErr *ConstraintError
+			require.ErrorAs(t, err, &constraintErr)
+			assert.Equal(t, tt.wantPath, constraintErr.FieldPath)
+			assert.Equal(t, tt.wantConstraint, constraintErr.Constraint)
+			if tt.wantMsg != "" {
+				assert.EqualError(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_InvalidLengthLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  any
+		wantMsg string
+	}{
+		{name: "not a number", target: &struct {
+			Bad string `schema:"bad,minlen=abc"`
+		}{}, wantMsg: `invalid minlen value "abc"`},
+		{name: "negative", target: &struct {
+			Bad string `schema:"bad,maxlen=-1"`
+		}{}, wantMsg: `invalid maxlen value "-1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reported without input for the field
+			err := NewDefaultUnmarshaler().Unmarshal(map[string]any{}, tt.target)
+
+			var tagErr *TagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.Equal(t, "Bad", tagErr.Field)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package mapstructure
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+var errorType = reflect.TypeFor[error]()
+
+// Constructor builds values by calling a function with arguments taken from the keys of
+// an input map, for types configured through a constructor rather than exported fields,
+// such as *rate.Limiter built by rate.NewLimiter from {"rps": 10, "burst": 20}.
+// Create it with NewConstructor and register it with ConverterRegistry.WithConstructors.
+type Constructor struct {
+	fn     reflect.Value
+	typ    reflect.Type
+	params []string
+}
+
+// NewConstructor returns a Constructor calling fn, a function returning T or (T, error),
+// with one argument per name in params: argument i is decoded from the input key params[i]
+// with the rules of the running unmarshaler, so converters, formats and nested structs
+// apply to it, and keeps the zero value of its type when the key is missing.
+func NewConstructor(fn any, params ...string) (*Constructor, error) {
+	fnVal := reflect.ValueOf(fn)
+	if fnVal.Kind() != reflect.Func || fnVal.IsNil() {
+		return nil, NewValidationError(fmt.Sprintf("constructor must be a non-nil function, got %T", fn))
+	}
+
+	fnType := fnVal.Type()
+	if fnType.IsVariadic() {
+		return nil, NewValidationError(fmt.Sprintf("constructor %s must not be variadic", fnType))
+	}
+	if fnType.NumIn() != len(params) {
+		return nil, NewValidationError(fmt.Sprintf("constructor %s takes %d arguments, got %d parameter names",
+			fnType, fnType.NumIn(), len(params)))
+	}
+	switch {
+	case fnType.NumOut() == 1:
+	case fnType.NumOut() == 2 && fnType.Out(1) == errorType:
+	default:
+		return nil, NewValidationError(fmt.Sprintf("constructor %s must return T or (T, error)", fnType))
+	}
+
+	for i, name := range params {
+		if name == "" || slices.Contains(params[:i], name) {
+			return nil, NewValidationError(fmt.Sprintf("constructor %s has empty or duplicate parameter name %q", fnType, name))
+		}
+	}
+
+	return &Constructor{fn: fnVal, typ: fnType.Out(0), params: slices.Clone(params)}, nil
+}
+
+// Type returns the type of the values built by the constructor, the first result of its function.
+func (c *Constructor) Type() reflect.Type {
+	return c.typ
+}
+
+// Params returns the input keys bound to the arguments of the constructor, in order.
+func (c *Constructor) Params() []string {
+	return slices.Clone(c.params)
+}
+
+// Converter returns a DelegatingConverter calling the constructor for map input.
+// Input that is not a map[string]any is declined with ErrSkipConverter, and keys that
+// are not parameters of the constructor are rejected with a *ConstraintError.
+func (c *Constructor) Converter() DelegatingConverter {
+	return func(value any, ctx *ConvertContext) (reflect.Value, error) {
+		dataMap, ok := value.(map[string]any)
+		if !ok {
+			return reflect.Value{}, ErrSkipConverter
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(dataMap)) {
+			if !slices.Contains(c.params, key) {
+				return reflect.Value{}, ctx.fail(NewConstraintError(buildFieldPath(ctx.fieldPath, key),
+					"constructor", fmt.Sprintf("unknown key %q, expected one of %q", key, c.params)))
+			}
+		}
+
+		args := make([]reflect.Value, len(c.params))
+		for i, name := range c.params {
+			args[i] = reflect.New(c.fn.Type().In(i)).Elem()
+
+			arg, ok := dataMap[name]
+			if !ok {
+				continue
+			}
+
+			err := ctx.u.unmarshalValue(arg, args[i], buildFieldPath(ctx.fieldPath, name), nil)
+			if err != nil {
+				return reflect.Value{}, ctx.fail(err)
+			}
+		}
+
+		out := c.fn.Call(args)
+		if len(out) == 2 && !out[1].IsNil() {
+			err, _ := out[1].Interface().(error)
+
+			return reflect.Value{}, err
+		}
+
+		return out[0], nil
+	}
+}
+
+// WithConstructors returns a new registry extending r with the converters of the given
+// constructors, registered as delegating converters for their types. The receiver is
+// left unchanged.
+func (r *ConverterRegistry) WithConstructors(constructors ...*Constructor) *ConverterRegistry {
+	delegating := make(map[reflect.Type]DelegatingConverter, len(constructors))
+	for _, c := range constructors {
+		delegating[c.typ] = c.Converter()
+	}
+
+	return r.WithDelegatingConverters(delegating)
+}
@@ -0,0 +1,180 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLimiter stands for types configured only through a constructor, like *rate.Limiter.
+type testLimiter struct {
+	rps   float64
+	burst int
+}
+
+func newTestLimiter(rps float64, burst int) *testLimiter {
+	return &testLimiter{rps: rps, burst: burst}
+}
+
+type testBackoff struct {
+	min, max int
+}
+
+func newTestBackoff(minMs, maxMs int) (testBackoff, error) {
+	if maxMs < minMs {
+		return testBackoff{}, errors.New("max below min")
+	}
+
+	return testBackoff{min: minMs, max: maxMs}, nil
+}
+
+func TestNewConstructor(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      any
+		params  []string
+		wantErr string
+	}{
+		{name: "value result", fn: newTestLimiter, params: []string{"rps", "burst"}},
+		{name: "value and error results", fn: newTestBackoff, params: []string{"min", "max"}},
+		{name: "not a function", fn: 42, params: nil, wantErr: "must be a non-nil function, got int"},
+		{name: "nil function", fn: (func() int)(nil), params: nil, wantErr: "must be a non-nil function"},
+		{name: "variadic", fn: func(...int) int { return 0 }, params: []string{"n"}, wantErr: "must not be variadic"},
+		{name: "parameter count", fn: newTestLimiter, params: []string{"rps"}, wantErr: "takes 2 arguments, got 1 parameter names"},
+		{name: "no result", fn: func(int) {}, params: []string{"n"}, wantErr: "must return T or (T, error)"},
+		{name: "second result not error", fn: func(int) (int, int) { return 0, 0 }, params: []string{"n"}, wantErr: "must return T or (T, error)"},
+		{name: "duplicate parameter", fn: newTestLimiter, params: []string{"rps", "rps"}, wantErr: `duplicate parameter name "rps"`},
+		{name: "empty parameter", fn: newTestLimiter, params: []string{"rps", ""}, wantErr: `duplicate parameter name ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewConstructor(tt.fn, tt.params...)
+			if tt.wantErr != "" {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, reflect.TypeOf(tt.fn).Out(0), c.Type())
+			assert.Equal(t, tt.params, c.Params())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_Constructors(t *testing.T) {
+	type Config struct {
+		Limiter *testLimiter `schema:"limiter"`
+		Backoff testBackoff  `schema:"backoff"`
+	}
+
+	limiter, err := NewConstructor(newTestLimiter, "rps", "burst")
+	require.NoError(t, err)
+	backoff, err := NewConstructor(newTestBackoff, "min", "max")
+	require.NoError(t, err)
+
+	u := NewUnmarshaler(NewStructMetadataCache("schema", "default"),
+		NewDefaultConverterRegistry().WithConstructors(limiter, backoff))
+
+	tests := []struct {
+		name    string
+		data    map[string]any
+		want    Config
+		wantErr string
+		errAs   func(error) bool
+	}{
+		{
+			name: "arguments from keys",
+			data: map[string]any{
+				"limiter": map[string]any{"rps": 10, "burst": 20},
+				"backoff": map[string]any{"min": 100, "max": 5000},
+			},
+			want: Config{Limiter: &testLimiter{rps: 10, burst: 20}, Backoff: testBackoff{min: 100, max: 5000}},
+		},
+		{
+			name: "arguments converted",
+			data: map[string]any{"limiter": map[string]any{"rps": "2.5", "burst": "4"}},
+			want: Config{Limiter: &testLimiter{rps: 2.5, burst: 4}},
+		},
+		{
+			name: "missing keys are zero",
+			data: map[string]any{"limiter": map[string]any{"rps": 1}},
+			want: Config{Limiter: &testLimiter{rps: 1}},
+		},
+		{
+			name: "nil input",
+			data: map[string]any{"limiter": nil},
+			want: Config{},
+		},
+		{
+			name:    "unknown key",
+			data:    map[string]any{"limiter": map[string]any{"rps": 1, "brust": 2}},
+			wantErr: `limiter.brust: unknown key "brust", expected one of ["rps" "burst"]`,
+			errAs:   isErr[*ConstraintError],
+		},
+		{
+			name:    "argument conversion error",
+			data:    map[string]any{"limiter": map[string]any{"burst": "many"}},
+			wantErr: "limiter.burst",
+			errAs:   isErr[*ConversionError],
+		},
+		{
+			name:    "constructor error",
+			data:    map[string]any{"backoff": map[string]any{"min": 10, "max": 1}},
+			wantErr: "max below min",
+			errAs:   isErr[*ConversionError],
+		},
+		{
+			name:  "other input falls through",
+			data:  map[string]any{"backoff": "fast"},
+			errAs: isErr[*ConversionError],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Config
+			err := u.Unmarshal(tt.data, &got)
+			if tt.errAs != nil {
+				require.Error(t, err)
+				assert.True(t, tt.errAs(err), "unexpected error type %T", err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConverterRegistry_WithConstructors(t *testing.T) {
+	limiter, err := NewConstructor(newTestLimiter, "rps", "burst")
+	require.NoError(t, err)
+
+	base := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[*testLimiter](): func(any) (reflect.Value, error) { return reflect.Value{}, nil },
+	})
+	registry := base.WithConstructors(limiter)
+
+	_, ok := registry.FindDelegating(reflect.TypeFor[*testLimiter]())
+	assert.True(t, ok)
+	_, ok = registry.Find(reflect.TypeFor[*testLimiter]())
+	assert.False(t, ok, "constructors override converters for their type")
+
+	_, ok = base.FindDelegating(reflect.TypeFor[*testLimiter]())
+	assert.False(t, ok, "receiver unchanged")
+}
+
+func isErr[E error](err error) bool {
+	var target E
+
+	return errors.As(err, &target)
+}
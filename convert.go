@@ -0,0 +1,28 @@
+package mapstructure
+
+import "reflect"
+
+// ConvertValue converts value to the type to with the coercion rules of Unmarshal.
+// This is a convenience function that uses a shared default unmarshaler.
+func ConvertValue(value any, to reflect.Type) (any, error) {
+	return defaultUnmarshaler.ConvertValue(value, to)
+}
+
+// ConvertValue converts value to the type to with the converters and rules used to decode
+// struct fields, so other layers such as query builders or template engines can coerce
+// single values without defining a struct. Slices, pointers, sets and structs (from
+// map[string]any) are handled as for fields; the returned value has type to.
+// Conversion errors carry an empty field path.
+func (u *Unmarshaler) ConvertValue(value any, to reflect.Type) (any, error) {
+	if to == nil {
+		return nil, NewValidationError("target type is nil")
+	}
+
+	rv := reflect.New(to).Elem()
+	call := u.beginCall(to, nil)
+	if err := call.endCall(call.unmarshalValue(value, rv, "", nil)); err != nil {
+		return nil, err
+	}
+
+	return rv.Interface(), nil
+}
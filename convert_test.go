@@ -0,0 +1,81 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_ConvertValue(t *testing.T) {
+	type Filter struct {
+		Field string `schema:"field"`
+		Limit int    `schema:"limit" default:"10"`
+	}
+
+	ten := 10
+
+	tests := []struct {
+		name  string
+		value any
+		to    reflect.Type
+		want  any
+	}{
+		{name: "string to int", value: "42", to: reflect.TypeFor[int](), want: 42},
+		{name: "string to bool", value: "true", to: reflect.TypeFor[bool](), want: true},
+		{name: "float to uint8", value: 7.0, to: reflect.TypeFor[uint8](), want: uint8(7)},
+		{name: "slice elements", value: []any{"1", 2}, to: reflect.TypeFor[[]int](), want: []int{1, 2}},
+		{name: "pointer", value: "10", to: reflect.TypeFor[*int](), want: &ten},
+		{name: "time", value: "2024-03-05T10:00:00Z", to: reflect.TypeFor[time.Time](), want: time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)},
+		{name: "struct", value: map[string]any{"field": "name"}, to: reflect.TypeFor[Filter](), want: Filter{Field: "name", Limit: 10}},
+		{name: "nil to pointer", value: nil, to: reflect.TypeFor[*int](), want: (*int)(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertValue(tt.value, tt.to)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("custom converter", func(t *testing.T) {
+		type Level int
+		converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+			reflect.TypeFor[Level](): func(value any) (reflect.Value, error) {
+				if value == "high" {
+					return reflect.ValueOf(Level(3)), nil
+				}
+
+				return reflect.Value{}, errors.New("unknown level")
+			},
+		})
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+		got, err := u.ConvertValue("high", reflect.TypeFor[Level]())
+		require.NoError(t, err)
+		assert.Equal(t, Level(3), got)
+
+		_, err = u.ConvertValue("low", reflect.TypeFor[Level]())
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ConvertValue("x", reflect.TypeFor[int]())
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+
+		_, err = ConvertValue(1, reflect.TypeFor[complex128]())
+		var unsupported *UnsupportedTypeError
+		require.ErrorAs(t, err, &unsupported)
+
+		_, err = ConvertValue(1, nil)
+		var valErr *ValidationError
+		require.ErrorAs(t, err, &valErr)
+	})
+}
@@ -1,6 +1,139 @@
 package mapstructure
 
-import "reflect"
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrSkipConverter is returned by a Converter or FieldConverter to decline a value it does
+// not handle. Decoding then continues as if no converter were registered for the type,
+// so a converter can special-case some input shapes (e.g. strings) of a struct type
+// while maps still decode into it field by field.
+var ErrSkipConverter = errors.New("skip converter")
+
+// DelegatingConverter converts a value to a reflect.Value of a specific type and may hand
+// values back to the unmarshaler through ctx, e.g. to pre-process the input map of a
+// struct and then decode it with the standard field mapping. Register it with
+// ConverterRegistry.WithDelegatingConverters.
+type DelegatingConverter func(value any, ctx *ConvertContext) (reflect.Value, error)
+
+// ConvertContext gives a DelegatingConverter access to the decode call running it.
+type ConvertContext struct {
+	u         *Unmarshaler
+	field     *FieldMetadata
+	fieldPath string
+	decodeErr error // Last error returned by DecodeInto
+}
+
+// Field returns the metadata of the struct field being decoded, nil at the top level.
+func (c *ConvertContext) Field() *FieldMetadata {
+	return c.field
+}
+
+// FieldPath returns the path of the value being converted, as used in errors.
+func (c *ConvertContext) FieldPath() string {
+	return c.fieldPath
+}
+
+// Value returns the value attached to the call under key with WithValue, nil if none.
+func (c *ConvertContext) Value(key any) any {
+	return c.u.call.values[key]
+}
+
+// DecodeInto decodes value into the settable rv with the rules of the running unmarshaler,
+// except that no converter is looked up for the type of rv itself: structs are decoded
+// from maps field by field, pointers, slices and sets element by element, and nested
+// values use their converters as usual. Returning its error from the converter reports
+// it unchanged, with the path of the failing field.
+func (c *ConvertContext) DecodeInto(value any, rv reflect.Value) error {
+	if !rv.CanSet() {
+		return NewValidationError("DecodeInto target must be settable")
+	}
+
+	c.decodeErr = c.u.unmarshalStructural(value, rv, c.fieldPath, c.field)
+
+	return c.decodeErr
+}
+
+// fail records err as returned by DecodeInto, so the converter returning it reports it unchanged.
+func (c *ConvertContext) fail(err error) error {
+	c.decodeErr = err
+
+	return err
+}
 
 // Converter converts a value to a reflect.Value of a specific type.
 type Converter func(value any) (reflect.Value, error)
+
+// FieldConverter converts a value to a reflect.Value of a specific type using the
+// metadata of the field being decoded, so tag options (e.g. "unit=ms") can influence
+// conversion. field is nil when the value is not decoded into a struct field
+// (for example the top-level target).
+type FieldConverter func(value any, field *FieldMetadata) (reflect.Value, error)
+
+// setConverted stores the output of a converter registered for the type of rv into rv.
+// Converters may return T or *T for either a T or a *T target: pointers are dereferenced,
+// a nil pointer yielding the zero value, and values are stored through a new pointer.
+// Other outputs are reported as a *ConverterContractError instead of panicking in reflect.
+func setConverted(rv, converted reflect.Value, fieldPath string) error {
+	typ := rv.Type()
+
+	switch {
+	case !converted.IsValid():
+		return NewConverterContractError(fieldPath, typ, nil)
+	case converted.Type().AssignableTo(typ):
+		rv.Set(converted)
+	case converted.Kind() == reflect.Ptr && converted.Type().Elem().AssignableTo(typ):
+		if converted.IsNil() {
+			rv.SetZero()
+		} else {
+			rv.Set(converted.Elem())
+		}
+	case typ.Kind() == reflect.Ptr && converted.Type().AssignableTo(typ.Elem()):
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(converted)
+		rv.Set(ptr)
+	default:
+		return NewConverterContractError(fieldPath, typ, converted.Type())
+	}
+
+	return nil
+}
+
+// convert runs the converter registered for typ on data: the delegating converter, the
+// field converter, or else the plain converter. It reports false when there is none or
+// it returned ErrSkipConverter. Errors are returned as *ConversionError for fieldPath,
+// except those of decoding delegated through ConvertContext.DecodeInto, returned as they are.
+func (u *Unmarshaler) convert(data any, typ reflect.Type, fieldPath string, field *FieldMetadata) (reflect.Value, bool, error) {
+	var converted reflect.Value
+	var err error
+	var ctx *ConvertContext
+
+	if delegating, ok := u.converters.FindDelegating(typ); ok {
+		ctx = &ConvertContext{u: u, field: field, fieldPath: fieldPath}
+		converted, err = delegating(data, ctx)
+	} else if fieldConv, ok := u.converters.FindField(typ); ok {
+		converted, err = fieldConv(data, field)
+	} else if conv, ok := u.converters.Find(typ); ok {
+		converted, err = conv(data)
+	} else {
+		return reflect.Value{}, false, nil
+	}
+	u.countConversion(data, converted)
+
+	if errors.Is(err, ErrSkipConverter) {
+		return reflect.Value{}, false, nil
+	}
+	if u.metrics != nil {
+		u.metrics.ObserveConversion(typ, err)
+	}
+
+	switch {
+	case err == nil:
+		return converted, true, nil
+	case ctx != nil && ctx.decodeErr != nil && errors.Is(err, ctx.decodeErr):
+		return reflect.Value{}, true, err
+	default:
+		return reflect.Value{}, true, NewConversionError(fieldPath, data, typ, err)
+	}
+}
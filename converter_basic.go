@@ -2,13 +2,41 @@ package mapstructure
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 )
 
-// convertBool converts a value to bool.
+// BoolFormat controls how string input is read into bool fields.
+// The zero value gives the default rules: "" is false, and strings accepted by
+// strconv.ParseBool ("1", "t", "true", "0", "f", "false" and their upper case forms)
+// are parsed; other strings fail.
+type BoolFormat struct {
+	// NumericStrings also accepts any numeric string, true when non-zero like numeric
+	// input: "2" and "-0.5" are true, "0.0" and "0e3" false. "NaN" still fails.
+	NumericStrings bool
+}
+
+// NewBoolConverter returns a converter for bool fields applying format, replacing the
+// default one:
+//
+//	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+//		reflect.TypeOf(false): NewBoolConverter(BoolFormat{NumericStrings: true}),
+//	})
+func NewBoolConverter(format BoolFormat) Converter {
+	return func(value any) (reflect.Value, error) {
+		return parseBool(value, format)
+	}
+}
+
+// convertBool converts a value to bool with the default BoolFormat.
 // Handles bool, int, uint, float directly; parses string values.
 func convertBool(value any) (reflect.Value, error) {
+	return parseBool(value, BoolFormat{})
+}
+
+// parseBool converts value to a bool according to format.
+func parseBool(value any, format BoolFormat) (reflect.Value, error) {
 	dataVal := reflect.Indirect(reflect.ValueOf(value))
 	kind := getKind(dataVal)
 
@@ -30,6 +58,9 @@ func convertBool(value any) (reflect.Value, error) {
 		if b, err := strconv.ParseBool(s); err == nil {
 			return reflect.ValueOf(b), nil
 		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil && format.NumericStrings && !math.IsNaN(f) {
+			return reflect.ValueOf(f != 0), nil
+		}
 
 		return reflect.Value{}, fmt.Errorf("cannot parse %q as bool", s)
 	default:
@@ -37,41 +68,10 @@ func convertBool(value any) (reflect.Value, error) {
 	}
 }
 
-// convertString converts a value to string.
+// convertString converts a value to string with the default StringFormat.
 // Handles string, bool, int, uint, float, and []byte directly.
 func convertString(value any) (reflect.Value, error) {
-	dataVal := reflect.Indirect(reflect.ValueOf(value))
-	kind := getKind(dataVal)
-
-	//nolint:exhaustive // Only handling convertible types
-	switch kind {
-	case reflect.String:
-		return reflect.ValueOf(dataVal.String()), nil
-	case reflect.Bool:
-		if dataVal.Bool() {
-			return reflect.ValueOf("1"), nil
-		}
-
-		return reflect.ValueOf("0"), nil
-	case reflect.Int:
-		return reflect.ValueOf(strconv.FormatInt(dataVal.Int(), 10)), nil
-	case reflect.Uint:
-		return reflect.ValueOf(strconv.FormatUint(dataVal.Uint(), 10)), nil
-	case reflect.Float32:
-		return reflect.ValueOf(strconv.FormatFloat(dataVal.Float(), 'f', -1, 64)), nil
-	case reflect.Slice:
-		// Handle []byte
-		if dataVal.Type().Elem().Kind() == reflect.Uint8 {
-			//nolint:forcetypeassert // Type checked above
-			bytes := dataVal.Interface().([]byte)
-
-			return reflect.ValueOf(string(bytes)), nil
-		}
-
-		return reflect.Value{}, fmt.Errorf("cannot convert %T to string", value)
-	default:
-		return reflect.Value{}, fmt.Errorf("cannot convert %T to string", value)
-	}
+	return formatString(value, StringFormat{})
 }
 
 // getKind normalizes reflect.Kind to base types.
@@ -1,6 +1,8 @@
 package mapstructure
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -107,3 +109,56 @@ func TestConverter_convertString(t *testing.T) {
 		})
 	}
 }
+
+func TestNewBoolConverter(t *testing.T) {
+	conv := NewBoolConverter(BoolFormat{NumericStrings: true})
+
+	tests := []struct {
+		input     any
+		want      bool
+		wantError bool
+	}{
+		{"2", true, false},
+		{"-1", true, false},
+		{"0.5", true, false},
+		{"0.0", false, false},
+		{"-0", false, false},
+		{"0e3", false, false},
+		{"1e-9", true, false},
+		{"true", true, false},
+		{"F", false, false},
+		{"", false, false},
+		{7, true, false},
+		{"NaN", false, true},
+		{"yes", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.input), func(t *testing.T) {
+			result, err := conv(tt.input)
+			if tt.wantError {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Bool())
+		})
+	}
+
+	// The default converter keeps rejecting other numeric strings
+	_, err := convertBool("2")
+	require.Error(t, err)
+	_, err = convertBool("0.0")
+	require.Error(t, err)
+
+	// Registered for bool fields
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeOf(false): conv,
+	}))
+	var got struct {
+		Enabled bool `schema:"enabled"`
+	}
+	require.NoError(t, u.Unmarshal(map[string]any{"enabled": "2"}, &got))
+	assert.True(t, got.Enabled)
+}
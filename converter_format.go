@@ -0,0 +1,223 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OptionFormat is the tag option selecting the wire format of string input, such as
+// "limit,format=bytes". Time fields take FormatUnix, FormatUnixMilli or a layout instead.
+const OptionFormat = "format"
+
+// Supported values of the "format" tag option. A format describes how string input is
+// written on the wire; the parsed number is then converted to the field type as usual,
+// so "limit,format=bytes" works for int64, uint64 or float64 fields alike.
+const (
+	// FormatBytes parses sizes such as "512", "10KB" or "1.5GiB" into an int64 byte count.
+	// Suffixes with an "i" are binary (KiB = 1024), the others decimal (KB = 1000).
+	FormatBytes = "bytes"
+
+	// FormatCount parses counts with SI suffixes such as "1.5k" or "2M" into a number.
+	FormatCount = "count"
+
+	// FormatPercent parses percentages such as "75%" into a fraction (0.75).
+	// Strings without a "%" sign are taken as fractions already.
+	FormatPercent = "percent"
+)
+
+// byteUnits maps size suffixes (upper-cased) to their multiplier.
+var byteUnits = map[string]uint64{
+	"":    1,
+	"B":   1,
+	"K":   1e3,
+	"KB":  1e3,
+	"M":   1e6,
+	"MB":  1e6,
+	"G":   1e9,
+	"GB":  1e9,
+	"T":   1e12,
+	"TB":  1e12,
+	"P":   1e15,
+	"PB":  1e15,
+	"E":   1e18,
+	"EB":  1e18,
+	"KI":  1 << 10,
+	"KIB": 1 << 10,
+	"MI":  1 << 20,
+	"MIB": 1 << 20,
+	"GI":  1 << 30,
+	"GIB": 1 << 30,
+	"TI":  1 << 40,
+	"TIB": 1 << 40,
+	"PI":  1 << 50,
+	"PIB": 1 << 50,
+	"EI":  1 << 60,
+	"EIB": 1 << 60,
+}
+
+// countUnits maps SI count suffixes to their multiplier.
+var countUnits = map[string]uint64{
+	"":  1,
+	"k": 1e3,
+	"K": 1e3,
+	"M": 1e6,
+	"G": 1e9,
+	"T": 1e12,
+	"P": 1e15,
+	"E": 1e18,
+}
+
+// applyFormat parses string input for a value of type typ according to the field's
+// "format" tag option. Non-string values, empty strings and fields without a format are
+// returned unchanged, as are values of time.Time, Date and TimeOfDay, whose formats the
+// time converters handle.
+func applyFormat(value any, field *FieldMetadata, typ reflect.Type) (any, error) {
+	format, ok := field.Option(OptionFormat)
+	if !ok || isTimeType(typ) {
+		return value, nil
+	}
+
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return value, nil
+	}
+
+	s = strings.TrimSpace(s)
+
+	switch format {
+	case FormatBytes:
+		return parseBytes(s)
+	case FormatCount:
+		return parseCount(s)
+	case FormatPercent:
+		return parsePercent(s)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// checkFormat reports an error when format is not supported by fields of type typ.
+// Pointers, slices and maps are checked through their elements, as decoding passes the
+// option on to them; any format of a time type is a layout.
+func checkFormat(format string, typ reflect.Type) error {
+	switch format {
+	case FormatBytes, FormatCount, FormatPercent:
+		return nil
+	}
+
+	//nolint:exhaustive // Only container kinds pass the option on
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return checkFormat(format, typ.Elem())
+	case reflect.Map:
+		if checkFormat(format, typ.Key()) == nil {
+			return nil
+		}
+
+		return checkFormat(format, typ.Elem())
+	}
+
+	if isTimeType(typ) {
+		return nil
+	}
+
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// parseBytes parses a byte size with an optional decimal or binary unit suffix.
+func parseBytes(s string) (int64, error) {
+	number, unit := splitUnit(s)
+
+	multiplier, ok := byteUnits[strings.ToUpper(unit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, unit)
+	}
+
+	n, err := scaleNumber(number, multiplier)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+	}
+
+	i, ok := n.(int64)
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: fractional bytes", s)
+	}
+
+	return i, nil
+}
+
+// parseCount parses a count with an optional SI suffix.
+func parseCount(s string) (any, error) {
+	number, unit := splitUnit(s)
+
+	multiplier, ok := countUnits[unit]
+	if !ok {
+		return nil, fmt.Errorf("invalid count %q: unknown unit %q", s, unit)
+	}
+
+	n, err := scaleNumber(number, multiplier)
+	if err != nil {
+		return nil, fmt.Errorf("invalid count %q: %w", s, err)
+	}
+
+	return n, nil
+}
+
+// parsePercent parses "75%" as 0.75; strings without "%" are parsed as plain fractions.
+func parsePercent(s string) (float64, error) {
+	number, isPercent := strings.CutSuffix(s, "%")
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+
+	if isPercent {
+		f /= 100
+	}
+
+	return f, nil
+}
+
+// splitUnit splits "1.5 GiB" into its numeric part and unit suffix.
+func splitUnit(s string) (string, string) {
+	i := strings.LastIndexFunc(s, func(r rune) bool {
+		return (r >= '0' && r <= '9') || r == '.'
+	})
+
+	return strings.TrimSpace(s[:i+1]), strings.TrimSpace(s[i+1:])
+}
+
+// scaleNumber parses number and multiplies it by multiplier.
+// Integral results are returned as int64 (exactly, for integer input), others as float64.
+func scaleNumber(number string, multiplier uint64) (any, error) {
+	m := int64(multiplier) //nolint:gosec // Unit multipliers are at most 1<<60
+
+	if i, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if i > math.MaxInt64/m || i < math.MinInt64/m {
+			return nil, errors.New("value out of range")
+		}
+
+		return i * m, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("invalid number %q", number)
+	}
+
+	n := f * float64(m)
+	if math.Abs(n) >= math.MaxInt64 {
+		return nil, errors.New("value out of range")
+	}
+
+	if n == math.Trunc(n) {
+		return int64(n), nil
+	}
+
+	return n, nil
+}
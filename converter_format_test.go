@@ -0,0 +1,136 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_applyFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   any
+		want    any
+		wantErr string
+	}{
+		{name: "bytes plain", format: "bytes", input: "512", want: int64(512)},
+		{name: "bytes decimal unit", format: "bytes", input: "10KB", want: int64(10000)},
+		{name: "bytes binary unit", format: "bytes", input: "1.5GiB", want: int64(1610612736)},
+		{name: "bytes short binary unit", format: "bytes", input: "2Mi", want: int64(2 << 20)},
+		{name: "bytes lowercase with space", format: "bytes", input: " 4 kib ", want: int64(4096)},
+		{name: "bytes suffix B", format: "bytes", input: "100B", want: int64(100)},
+		{name: "bytes fractional", format: "bytes", input: "1.5B", wantErr: `invalid byte size "1.5B": fractional bytes`},
+		{name: "bytes unknown unit", format: "bytes", input: "3 parsecs", wantErr: `invalid byte size "3 parsecs": unknown unit "parsecs"`},
+		{name: "bytes overflow", format: "bytes", input: "9EiB", wantErr: `invalid byte size "9EiB": value out of range`},
+		{name: "bytes missing number", format: "bytes", input: "GiB", wantErr: `invalid byte size "GiB": invalid number ""`},
+		{name: "count plain", format: "count", input: "42", want: int64(42)},
+		{name: "count kilo", format: "count", input: "1.5k", want: int64(1500)},
+		{name: "count mega", format: "count", input: "2M", want: int64(2000000)},
+		{name: "count fractional", format: "count", input: "0.5", want: 0.5},
+		{name: "count unknown unit", format: "count", input: "3x", wantErr: `invalid count "3x": unknown unit "x"`},
+		{name: "percent", format: "percent", input: "75%", want: 0.75},
+		{name: "percent with space", format: "percent", input: "12.5 %", want: 0.125},
+		{name: "percent fraction", format: "percent", input: "0.3", want: 0.3},
+		{name: "percent invalid", format: "percent", input: "lots%", wantErr: `invalid percentage "lots%"`},
+		{name: "non-string unchanged", format: "bytes", input: 1024, want: 1024},
+		{name: "empty string unchanged", format: "bytes", input: "", want: ""},
+		{name: "unknown format", format: "roman", input: "XII", wantErr: `unknown format "roman"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := &FieldMetadata{Options: map[string]string{"format": tt.format}}
+
+			got, err := applyFormat(tt.input, field, reflect.TypeFor[int64]())
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("no format option", func(t *testing.T) {
+		got, err := applyFormat("75%", nil, reflect.TypeFor[float64]())
+		require.NoError(t, err)
+		assert.Equal(t, "75%", got)
+	})
+
+	t.Run("time formats are left to the converter", func(t *testing.T) {
+		field := &FieldMetadata{Options: map[string]string{"format": "2006-01-02"}}
+		for _, typ := range []reflect.Type{timeType, reflect.TypeFor[Date](), reflect.TypeFor[TimeOfDay]()} {
+			got, err := applyFormat("2024-01-15", field, typ)
+			require.NoError(t, err, typ)
+			assert.Equal(t, "2024-01-15", got, typ)
+		}
+	})
+}
+
+func TestUnmarshaler_Unmarshal_Formats(t *testing.T) {
+	type Quota struct {
+		Limit     int64     `schema:"limit,format=bytes"`
+		Soft      uint64    `schema:"soft,format=bytes" default:"512MiB"`
+		Threshold float64   `schema:"threshold,format=percent"`
+		Requests  int       `schema:"requests,format=count"`
+		Steps     []float32 `schema:"steps,format=percent"`
+		Label     string    `schema:"label,format=bytes"`
+	}
+
+	data := map[string]any{
+		"limit":     "1.5GiB",
+		"threshold": "75%",
+		"requests":  "10k",
+		"steps":     []any{"25%", "50%", 1},
+		"label":     "1KiB",
+	}
+
+	var result Quota
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1610612736), result.Limit)
+	assert.Equal(t, uint64(512<<20), result.Soft)
+	assert.InDelta(t, 0.75, result.Threshold, 1e-9)
+	assert.Equal(t, 10000, result.Requests)
+	assert.Equal(t, []float32{0.25, 0.5, 1}, result.Steps)
+	assert.Equal(t, "1024", result.Label)
+
+	t.Run("invalid format value reports field", func(t *testing.T) {
+		var result Quota
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"limit": "lots"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "limit", convErr.FieldPath)
+		assert.Equal(t, "lots", convErr.Value)
+	})
+
+	t.Run("unknown format is a tag error", func(t *testing.T) {
+		type Bad struct {
+			Limit int64 `schema:"limit,format=roman"`
+		}
+
+		var result Bad
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{}, &result)
+
+		var tagErr *TagError
+		require.ErrorAs(t, err, &tagErr)
+		assert.Equal(t, "Limit", tagErr.Field)
+		assert.Contains(t, err.Error(), `unknown format "roman"`)
+	})
+
+	t.Run("time layouts are checked through containers", func(t *testing.T) {
+		type Days struct {
+			Dates []*Date                `schema:"dates,format=02/01/2006"`
+			Seen  map[time.Time]struct{} `schema:"seen,format=unix"`
+		}
+
+		require.NoError(t, NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Days]()).Err())
+	})
+}
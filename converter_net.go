@@ -0,0 +1,118 @@
+package mapstructure
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+)
+
+// convertAddr converts a value to netip.Addr.
+// Handles netip.Addr, net.IP and strings in IPv4, IPv6 or IPv6-with-zone form.
+func convertAddr(value any) (reflect.Value, error) {
+	switch v := value.(type) {
+	case netip.Addr:
+		return reflect.ValueOf(v), nil
+	case net.IP:
+		addr, ok := netip.AddrFromSlice(v)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid IP address: %d bytes, want 4 or 16", len(v))
+		}
+
+		return reflect.ValueOf(addr.Unmap()), nil
+	case string:
+		if v == "" {
+			return reflect.ValueOf(netip.Addr{}), nil
+		}
+
+		addr, err := parseAddr(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(addr), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to netip.Addr", value)
+}
+
+// convertPrefix converts a value to netip.Prefix.
+// Handles netip.Prefix, *net.IPNet and CIDR strings ("10.0.0.0/8").
+func convertPrefix(value any) (reflect.Value, error) {
+	switch v := value.(type) {
+	case netip.Prefix:
+		return reflect.ValueOf(v), nil
+	case *net.IPNet:
+		return convertPrefix(v.String())
+	case string:
+		if v == "" {
+			return reflect.ValueOf(netip.Prefix{}), nil
+		}
+
+		prefix, err := parsePrefix(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(prefix), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to netip.Prefix", value)
+}
+
+// convertHardwareAddr converts a value to net.HardwareAddr.
+// Handles net.HardwareAddr and strings in any format accepted by net.ParseMAC.
+func convertHardwareAddr(value any) (reflect.Value, error) {
+	switch v := value.(type) {
+	case net.HardwareAddr:
+		return reflect.ValueOf(v), nil
+	case string:
+		if v == "" {
+			return reflect.ValueOf(net.HardwareAddr(nil)), nil
+		}
+
+		mac, err := net.ParseMAC(v)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid MAC address %q", v)
+		}
+
+		return reflect.ValueOf(mac), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to net.HardwareAddr", value)
+}
+
+// parseAddr parses an IP address, reporting CIDR input explicitly.
+func parseAddr(s string) (netip.Addr, error) {
+	if strings.Contains(s, "/") {
+		return netip.Addr{}, fmt.Errorf("invalid IP address %q: unexpected prefix length", s)
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid IP address %q", s)
+	}
+
+	return addr, nil
+}
+
+// parsePrefix parses a CIDR prefix with precise messages for the common mistakes.
+func parsePrefix(s string) (netip.Prefix, error) {
+	addrPart, bitsPart, found := strings.Cut(s, "/")
+	if !found {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q: missing prefix length", s)
+	}
+
+	addr, err := netip.ParseAddr(addrPart)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q: invalid IP address %q", s, addrPart)
+	}
+
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q: invalid prefix length %q for %d-bit address", s, bitsPart, addr.BitLen())
+	}
+
+	return prefix, nil
+}
@@ -0,0 +1,143 @@
+package mapstructure
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_convertAddr(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   any
+		want    netip.Addr
+		wantErr string
+	}{
+		{name: "addr", input: netip.MustParseAddr("10.0.0.1"), want: netip.MustParseAddr("10.0.0.1")},
+		{name: "ipv4 string", input: "192.168.1.10", want: netip.MustParseAddr("192.168.1.10")},
+		{name: "ipv6 string", input: "fe80::1%eth0", want: netip.MustParseAddr("fe80::1%eth0")},
+		{name: "net.IP", input: net.ParseIP("10.0.0.1"), want: netip.MustParseAddr("10.0.0.1")},
+		{name: "empty string", input: "", want: netip.Addr{}},
+		{name: "cidr", input: "10.0.0.0/8", wantErr: `invalid IP address "10.0.0.0/8": unexpected prefix length`},
+		{name: "garbage", input: "10.0.0.256", wantErr: `invalid IP address "10.0.0.256"`},
+		{name: "short net.IP", input: net.IP{1, 2}, wantErr: "invalid IP address: 2 bytes, want 4 or 16"},
+		{name: "int", input: 42, wantErr: "cannot convert int to netip.Addr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertAddr(tt.input)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Interface())
+		})
+	}
+}
+
+func TestConverter_convertPrefix(t *testing.T) {
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+
+	tests := []struct {
+		name    string
+		input   any
+		want    netip.Prefix
+		wantErr string
+	}{
+		{name: "prefix", input: netip.MustParsePrefix("10.0.0.0/8"), want: netip.MustParsePrefix("10.0.0.0/8")},
+		{name: "ipv4 string", input: "192.168.0.0/16", want: netip.MustParsePrefix("192.168.0.0/16")},
+		{name: "ipv6 string", input: "2001:db8::/32", want: netip.MustParsePrefix("2001:db8::/32")},
+		{name: "ipnet", input: ipNet, want: netip.MustParsePrefix("10.0.0.0/8")},
+		{name: "empty string", input: "", want: netip.Prefix{}},
+		{name: "missing prefix length", input: "10.0.0.0", wantErr: `invalid CIDR "10.0.0.0": missing prefix length`},
+		{name: "invalid address", input: "10.0.0/8", wantErr: `invalid CIDR "10.0.0/8": invalid IP address "10.0.0"`},
+		{name: "prefix too long", input: "10.0.0.0/33", wantErr: `invalid CIDR "10.0.0.0/33": invalid prefix length "33" for 32-bit address`},
+		{name: "bool", input: true, wantErr: "cannot convert bool to netip.Prefix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertPrefix(tt.input)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Interface())
+		})
+	}
+}
+
+func TestConverter_convertHardwareAddr(t *testing.T) {
+	mac, _ := net.ParseMAC("00:1a:2b:3c:4d:5e")
+
+	tests := []struct {
+		name    string
+		input   any
+		want    net.HardwareAddr
+		wantErr string
+	}{
+		{name: "hardware addr", input: mac, want: mac},
+		{name: "colon string", input: "00:1a:2b:3c:4d:5e", want: mac},
+		{name: "dash string", input: "00-1A-2B-3C-4D-5E", want: mac},
+		{name: "dot string", input: "001a.2b3c.4d5e", want: mac},
+		{name: "empty string", input: "", want: nil},
+		{name: "invalid", input: "00:1a:2b", wantErr: `invalid MAC address "00:1a:2b"`},
+		{name: "int", input: 1, wantErr: "cannot convert int to net.HardwareAddr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertHardwareAddr(tt.input)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Interface())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_NetworkTypes(t *testing.T) {
+	type Interface struct {
+		Address netip.Addr       `schema:"address"`
+		Subnet  netip.Prefix     `schema:"subnet"`
+		MAC     net.HardwareAddr `schema:"mac"`
+		DNS     []netip.Addr     `schema:"dns"`
+	}
+
+	data := map[string]any{
+		"address": "10.0.0.5",
+		"subnet":  "10.0.0.0/24",
+		"mac":     "00:1a:2b:3c:4d:5e",
+		"dns":     []any{"1.1.1.1", "8.8.8.8"},
+	}
+
+	var result Interface
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.5"), result.Address)
+	assert.True(t, result.Subnet.Contains(result.Address))
+	assert.Equal(t, "00:1a:2b:3c:4d:5e", result.MAC.String())
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("1.1.1.1"), netip.MustParseAddr("8.8.8.8")}, result.DNS)
+
+	t.Run("validation error reports field", func(t *testing.T) {
+		var result Interface
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"subnet": "10.0.0.0"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "subnet", convErr.FieldPath)
+		assert.Contains(t, err.Error(), "invalid CIDR \"10.0.0.0\": missing prefix length")
+	})
+}
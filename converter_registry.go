@@ -3,13 +3,19 @@ package mapstructure
 import (
 	"io"
 	"maps"
+	"net"
+	"net/netip"
 	"reflect"
+	"time"
 )
 
 // ConverterRegistry manages type converters.
 // Immutable after construction, safe for concurrent reads.
 type ConverterRegistry struct {
-	converters map[reflect.Type]Converter
+	converters           map[reflect.Type]Converter
+	fieldConverters      map[reflect.Type]FieldConverter
+	delegatingConverters map[reflect.Type]DelegatingConverter
+	authoritative        map[reflect.Type]struct{} // Types whose converters run before assignment
 }
 
 // NewConverterRegistry creates a registry with the given converters.
@@ -45,6 +51,9 @@ func NewDefaultConverterRegistry(additional ...map[reflect.Type]Converter) *Conv
 		reflect.TypeOf(float64(0)):                   convertFloat64,
 		reflect.TypeOf([]byte(nil)):                  convertBytes,
 		reflect.TypeOf((*io.ReadCloser)(nil)).Elem(): convertReadCloser,
+		reflect.TypeOf(netip.Addr{}):                 convertAddr,
+		reflect.TypeOf(netip.Prefix{}):               convertPrefix,
+		reflect.TypeOf(net.HardwareAddr(nil)):        convertHardwareAddr,
 	}
 
 	// Merge additional converters (allows override)
@@ -52,15 +61,134 @@ func NewDefaultConverterRegistry(additional ...map[reflect.Type]Converter) *Conv
 		maps.Copy(converters, additionalMap)
 	}
 
+	fieldConverters := map[reflect.Type]FieldConverter{
+		reflect.TypeOf(time.Time{}): convertTimeField,
+		reflect.TypeOf(Date{}):      convertDateField,
+		reflect.TypeOf(TimeOfDay{}): convertTimeOfDayField,
+	}
+
+	// Plain converters registered for the same type take precedence
+	for typ := range converters {
+		delete(fieldConverters, typ)
+	}
+
 	return &ConverterRegistry{
-		converters: converters,
+		converters:      converters,
+		fieldConverters: fieldConverters,
 	}
 }
 
+// WithFieldConverters returns a new registry extending r with the given field converters.
+// Field converters override any converter previously registered for the same type.
+// The receiver is left unchanged.
+func (r *ConverterRegistry) WithFieldConverters(fieldConverters map[reflect.Type]FieldConverter) *ConverterRegistry {
+	converters := maps.Clone(r.converters)
+	delegating := maps.Clone(r.delegatingConverters)
+	merged := maps.Clone(r.fieldConverters)
+	if merged == nil {
+		merged = make(map[reflect.Type]FieldConverter, len(fieldConverters))
+	}
+
+	for typ, conv := range fieldConverters {
+		delete(converters, typ)
+		delete(delegating, typ)
+		merged[typ] = conv
+	}
+
+	return &ConverterRegistry{
+		converters:           converters,
+		fieldConverters:      merged,
+		delegatingConverters: delegating,
+		authoritative:        r.authoritative,
+	}
+}
+
+// WithDelegatingConverters returns a new registry extending r with the given delegating
+// converters. They override any converter or field converter previously registered for
+// the same type. The receiver is left unchanged.
+func (r *ConverterRegistry) WithDelegatingConverters(delegatingConverters map[reflect.Type]DelegatingConverter) *ConverterRegistry {
+	converters := maps.Clone(r.converters)
+	fieldConverters := maps.Clone(r.fieldConverters)
+	merged := maps.Clone(r.delegatingConverters)
+	if merged == nil {
+		merged = make(map[reflect.Type]DelegatingConverter, len(delegatingConverters))
+	}
+
+	for typ, conv := range delegatingConverters {
+		delete(converters, typ)
+		delete(fieldConverters, typ)
+		merged[typ] = conv
+	}
+
+	return &ConverterRegistry{
+		converters:           converters,
+		fieldConverters:      fieldConverters,
+		delegatingConverters: merged,
+		authoritative:        r.authoritative,
+	}
+}
+
+// WithAuthoritative returns a new registry extending r with types marked as authoritative:
+// input for these types is always passed to their converter, even when it already has the
+// target type and would otherwise be assigned directly, so converters can normalize values
+// such as enums or ranges. The flag applies to whichever converter is registered for the
+// type. The receiver is left unchanged.
+func (r *ConverterRegistry) WithAuthoritative(types ...reflect.Type) *ConverterRegistry {
+	authoritative := maps.Clone(r.authoritative)
+	if authoritative == nil {
+		authoritative = make(map[reflect.Type]struct{}, len(types))
+	}
+
+	for _, typ := range types {
+		authoritative[typ] = struct{}{}
+	}
+
+	return &ConverterRegistry{
+		converters:           r.converters,
+		fieldConverters:      r.fieldConverters,
+		delegatingConverters: r.delegatingConverters,
+		authoritative:        authoritative,
+	}
+}
+
+// IsAuthoritative reports whether typ was marked with WithAuthoritative.
+// Lock-free read, safe for concurrent use.
+func (r *ConverterRegistry) IsAuthoritative(typ reflect.Type) bool {
+	_, ok := r.authoritative[typ]
+
+	return ok
+}
+
 // Find finds a converter for the given type.
+// Field converters are returned as a Converter invoked without field metadata.
 // Lock-free read, safe for concurrent use.
 func (r *ConverterRegistry) Find(typ reflect.Type) (Converter, bool) {
-	conv, ok := r.converters[typ]
+	if conv, ok := r.converters[typ]; ok {
+		return conv, true
+	}
+
+	if fieldConv, ok := r.fieldConverters[typ]; ok {
+		return func(value any) (reflect.Value, error) {
+			return fieldConv(value, nil)
+		}, true
+	}
+
+	return nil, false
+}
+
+// FindField finds a field converter for the given type.
+// Only converters registered as FieldConverter are returned.
+// Lock-free read, safe for concurrent use.
+func (r *ConverterRegistry) FindField(typ reflect.Type) (FieldConverter, bool) {
+	conv, ok := r.fieldConverters[typ]
+
+	return conv, ok
+}
+
+// FindDelegating finds a delegating converter for the given type.
+// Lock-free read, safe for concurrent use.
+func (r *ConverterRegistry) FindDelegating(typ reflect.Type) (DelegatingConverter, bool) {
+	conv, ok := r.delegatingConverters[typ]
 
 	return conv, ok
 }
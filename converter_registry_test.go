@@ -3,6 +3,7 @@ package mapstructure
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -99,3 +100,84 @@ func TestNewDefaultConverterRegistry(t *testing.T) {
 		assert.Equal(t, 222, result.Interface().(int), "later map should override earlier")
 	})
 }
+
+func TestConverterRegistry_WithFieldConverters(t *testing.T) {
+	intType := reflect.TypeOf(int(0))
+	fieldConv := func(value any, field *FieldMetadata) (reflect.Value, error) {
+		v, _ := field.Option("value")
+
+		return reflect.ValueOf(len(v)), nil
+	}
+
+	base := NewDefaultConverterRegistry()
+	registry := base.WithFieldConverters(map[reflect.Type]FieldConverter{intType: fieldConv})
+
+	found, ok := registry.FindField(intType)
+	require.True(t, ok, "should find field converter")
+	result, err := found(0, &FieldMetadata{Options: map[string]string{"value": "abc"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Interface())
+
+	// Find wraps field converters with nil metadata
+	conv, ok := registry.Find(intType)
+	require.True(t, ok)
+	result, err = conv(0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Interface())
+
+	// Receiver is unchanged
+	_, ok = base.FindField(intType)
+	assert.False(t, ok, "base registry should not gain field converters")
+	_, ok = base.FindField(reflect.TypeOf(time.Time{}))
+	assert.True(t, ok, "default registry should provide time.Time field converter")
+}
+
+func TestConverterRegistry_WithDelegatingConverters(t *testing.T) {
+	intType := reflect.TypeOf(int(0))
+	delegating := func(value any, ctx *ConvertContext) (reflect.Value, error) {
+		return reflect.ValueOf(time.Time{}), nil
+	}
+
+	base := NewDefaultConverterRegistry()
+	registry := base.WithDelegatingConverters(map[reflect.Type]DelegatingConverter{timeType: delegating})
+
+	_, ok := registry.FindDelegating(timeType)
+	assert.True(t, ok, "should find delegating converter")
+	_, ok = registry.FindField(timeType)
+	assert.False(t, ok, "delegating converter overrides the field converter")
+	_, ok = registry.Find(timeType)
+	assert.False(t, ok, "Find does not return delegating converters")
+
+	// Field converters registered later override delegating ones
+	overridden := registry.WithFieldConverters(map[reflect.Type]FieldConverter{
+		timeType: convertTimeField,
+	})
+	_, ok = overridden.FindDelegating(timeType)
+	assert.False(t, ok)
+	_, ok = overridden.Find(intType)
+	assert.True(t, ok, "other converters are kept")
+
+	// Receiver is unchanged
+	_, ok = base.FindDelegating(timeType)
+	assert.False(t, ok)
+	_, ok = registry.FindDelegating(timeType)
+	assert.True(t, ok)
+}
+
+func TestConverterRegistry_WithAuthoritative(t *testing.T) {
+	intType := reflect.TypeOf(int(0))
+
+	base := NewDefaultConverterRegistry()
+	registry := base.WithAuthoritative(timeType)
+
+	assert.True(t, registry.IsAuthoritative(timeType))
+	assert.False(t, registry.IsAuthoritative(intType))
+	assert.False(t, base.IsAuthoritative(timeType), "receiver is unchanged")
+
+	// The flag survives converters registered later
+	extended := registry.WithFieldConverters(map[reflect.Type]FieldConverter{timeType: convertTimeField}).
+		WithDelegatingConverters(map[reflect.Type]DelegatingConverter{})
+	assert.True(t, extended.IsAuthoritative(timeType))
+	_, ok := extended.Find(intType)
+	assert.True(t, ok, "converters are kept")
+}
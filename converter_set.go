@@ -0,0 +1,133 @@
+package mapstructure
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// OptionUnique is the tag option removing duplicate elements from a decoded slice.
+const OptionUnique = "unique"
+
+// UniqueSorted is the value of the "unique" tag option that also sorts the deduplicated elements.
+const UniqueSorted = "sorted"
+
+// checkUniqueMode reports an error for a value of the "unique" tag option other than
+// none and UniqueSorted.
+func checkUniqueMode(mode string) error {
+	if mode != "" && mode != UniqueSorted {
+		return fmt.Errorf("unknown unique mode %q", mode)
+	}
+
+	return nil
+}
+
+// isSetType reports whether typ is a set type of the form map[T]struct{}.
+func isSetType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Map && typ.Elem().Kind() == reflect.Struct && typ.Elem().NumField() == 0
+}
+
+// unmarshalSet unmarshals a slice (or a delimited string, see the "split" tag option)
+// into a map[T]struct{} set. Elements are converted to T like slice elements.
+func (u *Unmarshaler) unmarshalSet(data any, rv reflect.Value, fieldPath string, field *FieldMetadata) error {
+	if data == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+
+		return nil
+	}
+
+	keys := reflect.New(reflect.SliceOf(rv.Type().Key())).Elem()
+	pendingRefs := u.pendingRefCount()
+	if err := u.unmarshalSlice(data, keys, fieldPath, field); err != nil {
+		return err
+	}
+	if err := u.checkCopiedRefs(pendingRefs); err != nil {
+		return err
+	}
+
+	set := reflect.MakeMapWithSize(rv.Type(), keys.Len())
+	member := reflect.Zero(rv.Type().Elem())
+
+	for i := range keys.Len() {
+		key := keys.Index(i)
+		if !key.Comparable() {
+			return NewConversionError(fmt.Sprintf("%s[%d]", fieldPath, i), key.Interface(), rv.Type(), errors.New("set element is not comparable"))
+		}
+		set.SetMapIndex(key, member)
+	}
+
+	rv.Set(set)
+
+	return nil
+}
+
+// uniqueSlice removes duplicate elements from the decoded slice rv, keeping the first
+// occurrence, when the field has the "unique" tag option. With "unique=sorted" the
+// remaining elements are sorted in ascending order. A new slice is always built so
+// input data shared through direct assignment is never modified.
+func uniqueSlice(rv reflect.Value, fieldPath string, field *FieldMetadata) error {
+	mode, ok := field.Option(OptionUnique)
+	if !ok || rv.IsNil() {
+		return nil
+	}
+
+	seen := make(map[any]struct{}, rv.Len())
+	result := reflect.MakeSlice(rv.Type(), 0, rv.Len())
+
+	for i := range rv.Len() {
+		elem := rv.Index(i)
+		if !elem.Comparable() {
+			return NewConversionError(fmt.Sprintf("%s[%d]", fieldPath, i), elem.Interface(), rv.Type().Elem(), errors.New("unique element is not comparable"))
+		}
+
+		key := elem.Interface()
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = reflect.Append(result, elem)
+	}
+
+	if mode == UniqueSorted {
+		if err := sortSlice(result); err != nil {
+			return NewConversionError(fieldPath, rv.Interface(), rv.Type(), err)
+		}
+	}
+
+	rv.Set(result)
+
+	return nil
+}
+
+// sortSlice sorts a slice of integers, floats or strings in place.
+func sortSlice(rv reflect.Value) error {
+	var less func(a, b reflect.Value) int
+
+	//nolint:exhaustive // Only ordered kinds can be sorted
+	switch getKind(reflect.Zero(rv.Type().Elem())) {
+	case reflect.Int:
+		less = func(a, b reflect.Value) int { return cmp.Compare(a.Int(), b.Int()) }
+	case reflect.Uint:
+		less = func(a, b reflect.Value) int { return cmp.Compare(a.Uint(), b.Uint()) }
+	case reflect.Float32:
+		less = func(a, b reflect.Value) int { return cmp.Compare(a.Float(), b.Float()) }
+	case reflect.String:
+		less = func(a, b reflect.Value) int { return cmp.Compare(a.String(), b.String()) }
+	default:
+		return fmt.Errorf("cannot sort elements of type %v", rv.Type().Elem())
+	}
+
+	elems := make([]reflect.Value, rv.Len())
+	for i := range elems {
+		elems[i] = reflect.ValueOf(rv.Index(i).Interface())
+	}
+	slices.SortStableFunc(elems, less)
+
+	for i, elem := range elems {
+		rv.Index(i).Set(elem)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_UniqueSlices(t *testing.T) {
+	type Tag string
+
+	type Labels struct {
+		Tags    []string  `schema:"tags,unique"`
+		Sorted  []Tag     `schema:"sorted,unique=sorted"`
+		IDs     []int     `schema:"ids,unique=sorted"`
+		Scores  []float64 `schema:"scores,unique=sorted"`
+		Split   []string  `schema:"split,split,unique"`
+		Any     []any     `schema:"any,unique"`
+		Matrix  [][]int   `schema:"matrix,unique"`
+		Structs []struct {
+			A int
+		} `schema:"structs,unique=sorted"`
+	}
+
+	input := []string{"b", "a", "b", "c", "a"}
+	data := map[string]any{
+		"tags":   input,
+		"sorted": []Tag{"b", "a", "b"},
+		"ids":    []any{3, "1", 2.0, 3, 1},
+		"scores": []any{0.5, -1, 0.5},
+		"split":  "x, y, x",
+		"any":    []any{1, "1", 1},
+	}
+
+	var result Labels
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, result.Tags)
+	assert.Equal(t, []string{"b", "a", "b", "c", "a"}, input, "input must not be modified")
+	assert.Equal(t, []Tag{"a", "b"}, result.Sorted)
+	assert.Equal(t, []int{1, 2, 3}, result.IDs)
+	assert.Equal(t, []float64{-1, 0.5}, result.Scores)
+	assert.Equal(t, []string{"x", "y"}, result.Split)
+	assert.Equal(t, []any{1, "1"}, result.Any)
+
+	t.Run("non-comparable elements", func(t *testing.T) {
+		var result Labels
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"matrix": [][]int{{1}}}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "matrix[0]", convErr.FieldPath)
+	})
+
+	t.Run("unsortable elements", func(t *testing.T) {
+		var result Labels
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"structs": []any{map[string]any{"A": 1}}}, &result)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot sort elements")
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		var result struct {
+			Mode []string `schema:"mode,unique=random"`
+		}
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{}, &result)
+
+		var tagErr *TagError
+		require.ErrorAs(t, err, &tagErr)
+		assert.Equal(t, "Mode", tagErr.Field)
+		assert.Contains(t, err.Error(), `unknown unique mode "random"`)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_Sets(t *testing.T) {
+	type Permissions struct {
+		Roles  map[string]struct{}  `schema:"roles"`
+		Ports  map[int]struct{}     `schema:"ports,split"`
+		Groups *map[string]struct{} `schema:"groups"`
+		Sizes  map[int64]struct{}   `schema:"sizes,split,format=bytes"`
+		Keys   map[any]struct{}     `schema:"keys"`
+	}
+
+	data := map[string]any{
+		"roles":  []any{"admin", "viewer", "admin"},
+		"ports":  "80, 443",
+		"groups": []string{"ops"},
+		"sizes":  "1KiB,1024",
+	}
+
+	var result Permissions
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"admin": {}, "viewer": {}}, result.Roles)
+	assert.Equal(t, map[int]struct{}{80: {}, 443: {}}, result.Ports)
+	require.NotNil(t, result.Groups)
+	assert.Equal(t, map[string]struct{}{"ops": {}}, *result.Groups)
+	assert.Equal(t, map[int64]struct{}{1024: {}}, result.Sizes)
+
+	t.Run("nil clears set", func(t *testing.T) {
+		result := Permissions{Roles: map[string]struct{}{"x": {}}}
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"roles": nil}, &result)
+		require.NoError(t, err)
+		assert.Nil(t, result.Roles)
+	})
+
+	t.Run("invalid element", func(t *testing.T) {
+		var result Permissions
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"ports": "80,http"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "ports[1]", convErr.FieldPath)
+	})
+
+	t.Run("non-comparable element", func(t *testing.T) {
+		var result Permissions
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"keys": []any{[]int{1}}}, &result)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "set element is not comparable")
+	})
+}
@@ -0,0 +1,100 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OptionSplit is the tag option decoding a delimited string into a slice or set.
+const OptionSplit = "split"
+
+// DefaultSplitDelimiter is the delimiter used by the "split" tag option when no value is given.
+const DefaultSplitDelimiter = ","
+
+// splitDelimiter returns the delimiter configured by the field's "split" tag option,
+// e.g. `schema:"tags,split"` or `schema:"path,split=':'"`.
+func splitDelimiter(field *FieldMetadata) (string, bool) {
+	delim, ok := field.Option(OptionSplit)
+	if !ok {
+		return "", false
+	}
+
+	if delim == "" {
+		delim = DefaultSplitDelimiter
+	}
+
+	return delim, true
+}
+
+// splitDelimited splits s into elements separated by delim, CSV-style.
+// Elements are trimmed of surrounding whitespace. An element may be enclosed in double
+// quotes to contain the delimiter or whitespace; a doubled quote ("") inside a quoted
+// element stands for a literal quote. The empty string yields no elements.
+func splitDelimited(s, delim string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return []string{}, nil
+	}
+
+	var elems []string
+	rest := s
+
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+
+		var elem string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, remaining, err := readQuoted(rest)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", len(elems), err)
+			}
+
+			remaining = strings.TrimLeft(remaining, " \t")
+			if remaining != "" && !strings.HasPrefix(remaining, delim) {
+				return nil, fmt.Errorf("element %d: unexpected text after closing quote", len(elems))
+			}
+
+			elem, rest = quoted, remaining
+		} else {
+			end := strings.Index(rest, delim)
+			if end < 0 {
+				end = len(rest)
+			}
+
+			elem, rest = strings.TrimSpace(rest[:end]), rest[end:]
+		}
+
+		elems = append(elems, elem)
+
+		if rest == "" {
+			return elems, nil
+		}
+
+		rest = rest[len(delim):]
+	}
+}
+
+// readQuoted reads a double-quoted element from the start of s and returns its
+// unescaped content and the remainder of s after the closing quote.
+func readQuoted(s string) (string, string, error) {
+	var b strings.Builder
+
+	for i := 1; i < len(s); i++ {
+		if s[i] != '"' {
+			b.WriteByte(s[i])
+
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == '"' {
+			b.WriteByte('"')
+			i++
+
+			continue
+		}
+
+		return b.String(), s[i+1:], nil
+	}
+
+	return "", "", errors.New("unterminated quoted element")
+}
@@ -0,0 +1,97 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDelimited(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		delim   string
+		want    []string
+		wantErr string
+	}{
+		{name: "simple", input: "a,b,c", delim: ",", want: []string{"a", "b", "c"}},
+		{name: "trims whitespace", input: " a , b ,c ", delim: ",", want: []string{"a", "b", "c"}},
+		{name: "empty elements", input: "a,,b,", delim: ",", want: []string{"a", "", "b", ""}},
+		{name: "empty string", input: "  ", delim: ",", want: []string{}},
+		{name: "custom delimiter", input: "/usr/bin:/bin", delim: ":", want: []string{"/usr/bin", "/bin"}},
+		{name: "multi-character delimiter", input: "a::b::c", delim: "::", want: []string{"a", "b", "c"}},
+		{name: "quoted delimiter", input: `"Doe, John",Jane`, delim: ",", want: []string{"Doe, John", "Jane"}},
+		{name: "escaped quote", input: `"say ""hi""", bye`, delim: ",", want: []string{`say "hi"`, "bye"}},
+		{name: "quoted whitespace kept", input: `" padded " , x`, delim: ",", want: []string{" padded ", "x"}},
+		{name: "quote inside unquoted element", input: `5" screen,x`, delim: ",", want: []string{`5" screen`, "x"}},
+		{name: "unterminated quote", input: `a,"b`, delim: ",", wantErr: "element 1: unterminated quoted element"},
+		{name: "text after quote", input: `"a"b,c`, delim: ",", wantErr: "element 0: unexpected text after closing quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitDelimited(tt.input, tt.delim)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_SplitStrings(t *testing.T) {
+	type Query struct {
+		Tags    []string  `schema:"tags,split"`
+		IDs     []int     `schema:"ids,split"`
+		Path    []string  `schema:"path,split=':'"`
+		Names   *[]string `schema:"names,split=';'"`
+		Sizes   []int64   `schema:"sizes,split,format=bytes"`
+		Literal []string  `schema:"literal"`
+	}
+
+	data := map[string]any{
+		"tags":  "go, maps,\"a,b\"",
+		"ids":   "1,2,3",
+		"path":  "/usr/bin:/bin",
+		"names": `Doe, John;"Smith; Jane"`,
+		"sizes": "1KiB,2KB",
+	}
+
+	var result Query
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "maps", "a,b"}, result.Tags)
+	assert.Equal(t, []int{1, 2, 3}, result.IDs)
+	assert.Equal(t, []string{"/usr/bin", "/bin"}, result.Path)
+	require.NotNil(t, result.Names)
+	assert.Equal(t, []string{"Doe, John", "Smith; Jane"}, *result.Names)
+	assert.Equal(t, []int64{1024, 2000}, result.Sizes)
+
+	t.Run("slices still accepted", func(t *testing.T) {
+		var result Query
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"tags": []any{"x", "y"}}, &result)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"x", "y"}, result.Tags)
+	})
+
+	t.Run("without split option strings are rejected", func(t *testing.T) {
+		var result Query
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"literal": "a,b"}, &result)
+		require.Error(t, err)
+	})
+
+	t.Run("malformed quoting reports field", func(t *testing.T) {
+		var result Query
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"tags": `"open`}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "tags", convErr.FieldPath)
+		assert.Contains(t, err.Error(), "unterminated quoted element")
+	})
+}
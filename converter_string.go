@@ -0,0 +1,154 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// StringFormat controls how non-string input is written into string fields.
+// The zero value gives the default rules:
+//   - strings, including json.Number, are kept verbatim
+//   - bools become "1" or "0"
+//   - integers are written in base 10
+//   - floats are written in plain notation (no exponent) with the fewest digits that
+//     parse back to the same value at their own precision, so 42.0 becomes "42" and
+//     float32(3.14) becomes "3.14"
+//   - []byte is taken as UTF-8 text
+//   - maps, other slices, arrays and structs fail
+type StringFormat struct {
+	// TrueString and FalseString are written for bools, e.g. "true" and "false".
+	// Empty strings keep the defaults "1" and "0".
+	TrueString  string
+	FalseString string
+
+	// Decimals, when positive, rounds floats to that many digits after the decimal point
+	// (of the mantissa in scientific notation), e.g. 2 writes 3.14159 as "3.14".
+	// 0 writes the fewest digits that round-trip.
+	Decimals int
+
+	// ScientificThreshold, when positive, writes floats in scientific notation ("1.5e+21")
+	// when their absolute value is at least the threshold, or non-zero and below its
+	// inverse: 1e21 matches JavaScript's number formatting. 0 always uses plain notation.
+	ScientificThreshold float64
+
+	// ExactNumbers rejects float input, whose original text (e.g. "42.0" or "1e3")
+	// is lost once parsed: numbers written into string fields are then exactly their
+	// source text, taken from json.Number (see json.Decoder.UseNumber) or strings.
+	// Integers, having a single representation, are still accepted.
+	ExactNumbers bool
+
+	// Composites selects how maps, slices other than []byte, arrays and structs are written.
+	// The zero value CompositesError rejects them.
+	Composites CompositeFormat
+}
+
+// CompositeFormat selects how StringFormat writes composite input into string fields.
+type CompositeFormat int
+
+const (
+	// CompositesError fails for composite input, rather than storing text such as "map[a:1]".
+	CompositesError CompositeFormat = iota
+
+	// CompositesSprint writes composite input with fmt.Sprint, e.g. "[a b]" for []any{"a", "b"}.
+	CompositesSprint
+
+	// CompositesJSON writes composite input as JSON, e.g. `{"a":1}` for a map, to keep
+	// nested payloads in a single string field.
+	CompositesJSON
+)
+
+// NewStringConverter returns a converter for string fields applying format, replacing the
+// default one:
+//
+//	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+//		reflect.TypeOf(""): NewStringConverter(StringFormat{ExactNumbers: true}),
+//	})
+func NewStringConverter(format StringFormat) Converter {
+	return func(value any) (reflect.Value, error) {
+		return formatString(value, format)
+	}
+}
+
+// formatString converts value to a string according to format.
+func formatString(value any, format StringFormat) (reflect.Value, error) {
+	dataVal := reflect.Indirect(reflect.ValueOf(value))
+	kind := getKind(dataVal)
+
+	//nolint:exhaustive // Only handling convertible types
+	switch kind {
+	case reflect.String:
+		return reflect.ValueOf(dataVal.String()), nil
+	case reflect.Bool:
+		return reflect.ValueOf(format.formatBool(dataVal.Bool())), nil
+	case reflect.Int:
+		return reflect.ValueOf(strconv.FormatInt(dataVal.Int(), 10)), nil
+	case reflect.Uint:
+		return reflect.ValueOf(strconv.FormatUint(dataVal.Uint(), 10)), nil
+	case reflect.Float32:
+		if format.ExactNumbers {
+			return reflect.Value{}, fmt.Errorf("cannot convert %T to string exactly: the number's text is lost, pass a json.Number or string", value)
+		}
+
+		return reflect.ValueOf(format.formatFloat(dataVal.Float(), dataVal.Type().Bits())), nil
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		// Handle []byte
+		if kind == reflect.Slice && dataVal.Type().Elem().Kind() == reflect.Uint8 {
+			return reflect.ValueOf(string(dataVal.Bytes())), nil
+		}
+
+		return format.formatComposite(dataVal.Interface())
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot convert %T to string", value)
+	}
+}
+
+// formatComposite writes the composite value with the configured CompositeFormat.
+func (f StringFormat) formatComposite(value any) (reflect.Value, error) {
+	switch f.Composites {
+	case CompositesSprint:
+		return reflect.ValueOf(fmt.Sprint(value)), nil
+	case CompositesJSON:
+		text, err := json.Marshal(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot convert %T to string: %w", value, err)
+		}
+
+		return reflect.ValueOf(string(text)), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot convert %T to string", value)
+	}
+}
+
+// formatBool writes b with the configured bool strings.
+func (f StringFormat) formatBool(b bool) string {
+	switch {
+	case b && f.TrueString != "":
+		return f.TrueString
+	case b:
+		return "1"
+	case f.FalseString != "":
+		return f.FalseString
+	default:
+		return "0"
+	}
+}
+
+// formatFloat writes v, a float of bitSize bits, with the configured precision and notation.
+func (f StringFormat) formatFloat(v float64, bitSize int) string {
+	notation := byte('f')
+	if t := f.ScientificThreshold; t > 0 {
+		if abs := math.Abs(v); abs >= t || (abs != 0 && abs < 1/t) {
+			notation = 'e'
+		}
+	}
+
+	precision := -1
+	if f.Decimals > 0 {
+		precision = f.Decimals
+	}
+
+	return strconv.FormatFloat(v, notation, precision, bitSize)
+}
@@ -0,0 +1,89 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStringConverter(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    StringFormat
+		input     any
+		want      string
+		wantError bool
+	}{
+		{name: "json number verbatim", input: json.Number("42.0"), want: "42.0"},
+		{name: "json number exponent", input: json.Number("1e3"), want: "1e3"},
+		{name: "float64 shortest", input: 0.1, want: "0.1"},
+		{name: "float32 at own precision", input: float32(3.14), want: "3.14"},
+		{name: "float plain notation", input: 1e21, want: "1000000000000000000000"},
+		{name: "float shortest digits", input: float64(math.MaxInt64), want: "9223372036854776000"},
+		{name: "exact keeps json number", format: StringFormat{ExactNumbers: true}, input: json.Number("42.0"), want: "42.0"},
+		{name: "exact keeps integers", format: StringFormat{ExactNumbers: true}, input: int64(-7), want: "-7"},
+		{name: "exact keeps strings", format: StringFormat{ExactNumbers: true}, input: "3.10", want: "3.10"},
+		{name: "exact rejects float64", format: StringFormat{ExactNumbers: true}, input: 42.0, wantError: true},
+		{name: "exact rejects float32", format: StringFormat{ExactNumbers: true}, input: float32(1.5), wantError: true},
+		{name: "default bools", input: true, want: "1"},
+		{name: "true string", format: StringFormat{TrueString: "true", FalseString: "false"}, input: true, want: "true"},
+		{name: "false string", format: StringFormat{TrueString: "true", FalseString: "false"}, input: false, want: "false"},
+		{name: "only true string set", format: StringFormat{TrueString: "yes"}, input: false, want: "0"},
+		{name: "decimals round", format: StringFormat{Decimals: 2}, input: 3.14159, want: "3.14"},
+		{name: "decimals pad", format: StringFormat{Decimals: 2}, input: 42.0, want: "42.00"},
+		{name: "decimals leave integers", format: StringFormat{Decimals: 2}, input: 42, want: "42"},
+		{name: "scientific above threshold", format: StringFormat{ScientificThreshold: 1e21}, input: 1.5e21, want: "1.5e+21"},
+		{name: "plain below threshold", format: StringFormat{ScientificThreshold: 1e21}, input: 1.5e20, want: "150000000000000000000"},
+		{name: "scientific small values", format: StringFormat{ScientificThreshold: 1e6}, input: -1.5e-7, want: "-1.5e-07"},
+		{name: "zero stays plain", format: StringFormat{ScientificThreshold: 1e6}, input: 0.0, want: "0"},
+		{name: "scientific with decimals", format: StringFormat{ScientificThreshold: 1e3, Decimals: 1}, input: 12345.0, want: "1.2e+04"},
+		{name: "composites rejected", input: map[string]any{"a": 1}, wantError: true},
+		{name: "slices rejected", input: []any{"a"}, wantError: true},
+		{name: "structs rejected", input: struct{ A int }{A: 1}, wantError: true},
+		{name: "bytes kept", format: StringFormat{Composites: CompositesJSON}, input: []byte("raw"), want: "raw"},
+		{name: "sprint map", format: StringFormat{Composites: CompositesSprint}, input: map[string]any{"a": 1}, want: "map[a:1]"},
+		{name: "sprint slice", format: StringFormat{Composites: CompositesSprint}, input: []any{"a", "b"}, want: "[a b]"},
+		{name: "sprint pointer", format: StringFormat{Composites: CompositesSprint}, input: &[]int{1}, want: "[1]"},
+		{name: "json map", format: StringFormat{Composites: CompositesJSON}, input: map[string]any{"b": 2, "a": []any{1}}, want: `{"a":[1],"b":2}`},
+		{name: "json array", format: StringFormat{Composites: CompositesJSON}, input: [2]string{"x", "y"}, want: `["x","y"]`},
+		{name: "json struct", format: StringFormat{Composites: CompositesJSON}, input: struct{ A int }{A: 1}, want: `{"A":1}`},
+		{name: "json unsupported", format: StringFormat{Composites: CompositesJSON}, input: map[string]any{"ch": make(chan int)}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewStringConverter(tt.format)(tt.input)
+			if tt.wantError {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.String())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_ExactNumberStrings(t *testing.T) {
+	type Payment struct {
+		Amount string `schema:"amount"`
+	}
+
+	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[string](): NewStringConverter(StringFormat{ExactNumbers: true}),
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+	var result Payment
+	require.NoError(t, u.Unmarshal(map[string]any{"amount": json.Number("10.50")}, &result))
+	assert.Equal(t, "10.50", result.Amount)
+
+	err := u.Unmarshal(map[string]any{"amount": 10.5}, &result)
+	var convErr *ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, "amount", convErr.FieldPath)
+}
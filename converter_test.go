@@ -0,0 +1,247 @@
+package mapstructure
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetConverted(t *testing.T) {
+	type Money struct{ Cents int }
+
+	five := &Money{Cents: 5}
+
+	tests := []struct {
+		name      string
+		target    reflect.Type
+		converted reflect.Value
+		want      any
+		wantErr   string
+	}{
+		{name: "value for value", target: reflect.TypeFor[Money](), converted: reflect.ValueOf(Money{Cents: 5}), want: Money{Cents: 5}},
+		{name: "pointer for pointer", target: reflect.TypeFor[*Money](), converted: reflect.ValueOf(five), want: five},
+		{name: "pointer for value", target: reflect.TypeFor[Money](), converted: reflect.ValueOf(five), want: Money{Cents: 5}},
+		{name: "nil pointer for value", target: reflect.TypeFor[Money](), converted: reflect.ValueOf((*Money)(nil)), want: Money{}},
+		{name: "value for pointer", target: reflect.TypeFor[*Money](), converted: reflect.ValueOf(Money{Cents: 5}), want: &Money{Cents: 5}},
+		{name: "wrong type", target: reflect.TypeFor[Money](), converted: reflect.ValueOf("5"), wantErr: "converter for mapstructure.Money returned string"},
+		{name: "no value", target: reflect.TypeFor[Money](), converted: reflect.Value{}, wantErr: "converter for mapstructure.Money returned no value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rv := reflect.New(tt.target).Elem()
+			err := setConverted(rv, tt.converted, "price")
+			if tt.wantErr != "" {
+				var contractErr *ConverterContractError
+				require.ErrorAs(t, err, &contractErr)
+				assert.Equal(t, "price", contractErr.FieldPath)
+				assert.Equal(t, tt.target, contractErr.TargetType)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rv.Interface())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_ConverterPointerOutput(t *testing.T) {
+	type Money struct{ Cents int }
+	type Order struct {
+		Total    Money  `schema:"total"`
+		Discount *Money `schema:"discount"`
+	}
+
+	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		// Returns *Money for a Money target
+		reflect.TypeFor[Money](): func(value any) (reflect.Value, error) {
+			return reflect.ValueOf(&Money{Cents: value.(int)}), nil //nolint:forcetypeassert // Test code
+		},
+		// Returns Money for a *Money target
+		reflect.TypeFor[*Money](): func(value any) (reflect.Value, error) {
+			return reflect.ValueOf(Money{Cents: value.(int)}), nil //nolint:forcetypeassert // Test code
+		},
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+	var result Order
+	err := u.Unmarshal(map[string]any{"total": 500, "discount": 50}, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, Order{Total: Money{Cents: 500}, Discount: &Money{Cents: 50}}, result)
+}
+
+func TestUnmarshaler_Unmarshal_ConverterContract(t *testing.T) {
+	type Config struct {
+		Level int `schema:"level"`
+	}
+
+	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[int](): func(value any) (reflect.Value, error) {
+			return reflect.ValueOf("high"), nil
+		},
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+	var result Config
+	err := u.Unmarshal(map[string]any{"level": "3"}, &result)
+
+	var contractErr *ConverterContractError
+	require.ErrorAs(t, err, &contractErr)
+	assert.Equal(t, "level", contractErr.FieldPath)
+	assert.Equal(t, reflect.TypeFor[int](), contractErr.TargetType)
+	assert.Equal(t, reflect.TypeFor[string](), contractErr.Returned)
+
+	err = u.UnmarshalStrings(map[string]string{"level": "3"}, &result)
+	require.ErrorAs(t, err, &contractErr)
+}
+
+func TestUnmarshaler_Unmarshal_SkipConverter(t *testing.T) {
+	type Money struct {
+		Cents    int    `schema:"cents"`
+		Currency string `schema:"currency" default:"USD"`
+	}
+	type Order struct {
+		Total  Money   `schema:"total"`
+		Refund *Money  `schema:"refund"`
+		Lines  []Money `schema:"lines"`
+	}
+
+	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[Money](): func(value any) (reflect.Value, error) {
+			s, ok := value.(string)
+			if !ok {
+				return reflect.Value{}, ErrSkipConverter
+			}
+
+			var cents int
+			if _, err := fmt.Sscanf(s, "$%d", &cents); err != nil {
+				return reflect.Value{}, err
+			}
+
+			return reflect.ValueOf(Money{Cents: cents * 100, Currency: "USD"}), nil
+		},
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+	var result Order
+	err := u.Unmarshal(map[string]any{
+		"total":  "$5",
+		"refund": map[string]any{"cents": 250, "currency": "EUR"},
+		"lines":  []any{map[string]any{"cents": 100}, "$4"},
+	}, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, Order{
+		Total:  Money{Cents: 500, Currency: "USD"},
+		Refund: &Money{Cents: 250, Currency: "EUR"},
+		Lines:  []Money{{Cents: 100, Currency: "USD"}, {Cents: 400, Currency: "USD"}},
+	}, result)
+
+	t.Run("converter errors are still reported", func(t *testing.T) {
+		var result Order
+		err := u.Unmarshal(map[string]any{"total": "five"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "total", convErr.FieldPath)
+	})
+
+	t.Run("no structural fallback", func(t *testing.T) {
+		type Config struct {
+			Port int `schema:"port"`
+		}
+		converters := NewDefaultConverterRegistry().WithFieldConverters(map[reflect.Type]FieldConverter{
+			reflect.TypeFor[int](): func(any, *FieldMetadata) (reflect.Value, error) {
+				return reflect.Value{}, ErrSkipConverter
+			},
+		})
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+		var result Config
+		err := u.Unmarshal(map[string]any{"port": "80"}, &result)
+
+		var unsupported *UnsupportedTypeError
+		require.ErrorAs(t, err, &unsupported)
+		assert.Equal(t, "port", unsupported.FieldPath)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_DelegatingConverter(t *testing.T) {
+	type Endpoint struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port" default:"443"`
+	}
+	type Config struct {
+		Primary  Endpoint   `schema:"primary"`
+		Fallback *Endpoint  `schema:"fallback"`
+		Mirrors  []Endpoint `schema:"mirrors"`
+	}
+
+	var paths []string
+	converters := NewDefaultConverterRegistry().WithDelegatingConverters(map[reflect.Type]DelegatingConverter{
+		reflect.TypeFor[Endpoint](): func(value any, ctx *ConvertContext) (reflect.Value, error) {
+			paths = append(paths, ctx.FieldPath())
+
+			switch v := value.(type) {
+			case string:
+				host, port, _ := strings.Cut(v, ":")
+				value = map[string]any{"host": host, "port": port}
+			case map[string]any:
+				if addr, ok := v["addr"]; ok { // Legacy key
+					v = maps.Clone(v)
+					v["host"] = addr
+					delete(v, "addr")
+					value = v
+				}
+			}
+
+			var endpoint Endpoint
+			if err := ctx.DecodeInto(value, reflect.ValueOf(&endpoint).Elem()); err != nil {
+				return reflect.Value{}, err
+			}
+
+			return reflect.ValueOf(endpoint), nil
+		},
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+	var result Config
+	err := u.Unmarshal(map[string]any{
+		"primary":  "a.example:8080",
+		"fallback": map[string]any{"addr": "b.example"},
+		"mirrors":  []any{map[string]any{"host": "c.example", "port": 80}},
+	}, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, Config{
+		Primary:  Endpoint{Host: "a.example", Port: 8080},
+		Fallback: &Endpoint{Host: "b.example", Port: 443},
+		Mirrors:  []Endpoint{{Host: "c.example", Port: 80}},
+	}, result)
+	assert.Equal(t, []string{"primary", "fallback", "mirrors[0]"}, paths)
+
+	t.Run("errors of delegated decoding are reported unchanged", func(t *testing.T) {
+		var result Config
+		err := u.Unmarshal(map[string]any{"primary": "a.example:http"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "primary.port", convErr.FieldPath)
+	})
+
+	t.Run("target must be settable", func(t *testing.T) {
+		ctx := &ConvertContext{u: u}
+		err := ctx.DecodeInto(map[string]any{}, reflect.ValueOf(Endpoint{}))
+
+		var valErr *ValidationError
+		require.ErrorAs(t, err, &valErr)
+	})
+}
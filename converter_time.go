@@ -0,0 +1,320 @@
+package mapstructure
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// OptionUnit is the tag option selecting the unit of numeric timestamps decoded into
+// time.Time, Date and TimeOfDay fields, e.g. `schema:"created,unit=ms"`.
+const OptionUnit = "unit"
+
+// Supported values of the "unit" tag option for numeric timestamps.
+const (
+	TimestampUnitSeconds = "s"
+	TimestampUnitMillis  = "ms"
+	TimestampUnitMicros  = "us"
+	TimestampUnitNanos   = "ns"
+
+	// TimestampUnitAuto detects the unit from the magnitude of the value.
+	TimestampUnitAuto = "auto"
+)
+
+// Time values of the "format" tag option. Any other format of a time.Time, Date or
+// TimeOfDay field is a layout for time.Parse, such as "2006-01-02" or time.RFC1123.
+const (
+	// FormatUnix reads numbers and numeric strings as Unix timestamps in seconds.
+	FormatUnix = "unix"
+
+	// FormatUnixMilli reads numbers and numeric strings as Unix timestamps in milliseconds.
+	FormatUnixMilli = "unixmilli"
+)
+
+// timeType is the reflect.Type of time.Time.
+var timeType = reflect.TypeFor[time.Time]()
+
+// isTimeType reports whether typ is decoded by the time converters, which handle
+// the "format" and "unit" tag options themselves.
+func isTimeType(typ reflect.Type) bool {
+	return typ == timeType || typ == reflect.TypeFor[Date]() || typ == reflect.TypeFor[TimeOfDay]()
+}
+
+// Magnitude thresholds used by TimestampUnitAuto. Seconds below 1e11 cover dates up to the
+// year 5138, so larger values are assumed to be in a finer unit.
+const (
+	autoMillisThreshold = 1e11
+	autoMicrosThreshold = 1e14
+	autoNanosThreshold  = 1e17
+)
+
+// convertTime converts a value to time.Time.
+// Handles time.Time, Date, TimeOfDay, and strings in RFC 3339, date-only ("2006-01-02")
+// or time-only ("15:04:05") form. Date-only input yields midnight UTC; time-only input
+// yields that time on the zero date (0000-01-01 UTC).
+func convertTime(value any) (reflect.Value, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return reflect.ValueOf(v), nil
+	case Date:
+		return reflect.ValueOf(v.In(time.UTC)), nil
+	case TimeOfDay:
+		return reflect.ValueOf(v.On(Date{Month: time.January, Day: 1}, time.UTC)), nil
+	case string:
+		t, err := parseTime(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(t), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to time.Time", value)
+}
+
+// convertTimeField converts a value to time.Time, honoring the field's "unit" and "format"
+// tag options. Numeric values, numeric strings and json.Number are treated as Unix
+// timestamps in the given unit, seconds by default; "unit=auto" picks the unit from the value's magnitude.
+// FormatUnix and FormatUnixMilli select the unit, other formats are layouts used to parse
+// strings. All other inputs are handled by convertTime.
+func convertTimeField(value any, field *FieldMetadata) (reflect.Value, error) {
+	unit, _ := field.Option(OptionUnit)
+
+	switch format, _ := field.Option(OptionFormat); format {
+	case "":
+	case FormatUnix:
+		unit = TimestampUnitSeconds
+	case FormatUnixMilli:
+		unit = TimestampUnitMillis
+	default:
+		if s, ok := value.(string); ok {
+			return parseTimeLayout(s, format)
+		}
+	}
+
+	dataVal := reflect.Indirect(reflect.ValueOf(value))
+
+	//nolint:exhaustive // Only handling numeric kinds
+	switch getKind(dataVal) {
+	case reflect.Int:
+		return unixTime(dataVal.Int(), unit)
+	case reflect.Uint:
+		if dataVal.Uint() > math.MaxInt64 {
+			return reflect.Value{}, fmt.Errorf("timestamp %d out of range", dataVal.Uint())
+		}
+
+		return unixTime(int64(dataVal.Uint()), unit)
+	case reflect.Float32:
+		return unixTimeFloat(dataVal.Float(), unit)
+	case reflect.String:
+		// Integers are parsed exactly, as float64 cannot hold nanosecond timestamps
+		if i, err := strconv.ParseInt(dataVal.String(), 10, 64); err == nil {
+			return unixTime(i, unit)
+		}
+		if f, err := strconv.ParseFloat(dataVal.String(), 64); err == nil {
+			return unixTimeFloat(f, unit)
+		}
+	}
+
+	return convertTime(value)
+}
+
+// parseTimeLayout parses s with the time.Parse layout. The empty string yields the zero time.
+func parseTimeLayout(s, layout string) (reflect.Value, error) {
+	if s == "" {
+		return reflect.ValueOf(time.Time{}), nil
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot parse %q with format %q: %w", s, layout, err)
+	}
+
+	return reflect.ValueOf(t), nil
+}
+
+// unixTime converts an integer Unix timestamp in the given unit to a UTC time.Time.
+func unixTime(ts int64, unit string) (reflect.Value, error) {
+	if unit == TimestampUnitAuto {
+		unit = detectTimestampUnit(float64(ts))
+	}
+
+	switch unit {
+	case "", TimestampUnitSeconds:
+		return reflect.ValueOf(time.Unix(ts, 0).UTC()), nil
+	case TimestampUnitMillis:
+		return reflect.ValueOf(time.UnixMilli(ts).UTC()), nil
+	case TimestampUnitMicros:
+		return reflect.ValueOf(time.UnixMicro(ts).UTC()), nil
+	case TimestampUnitNanos:
+		return reflect.ValueOf(time.Unix(0, ts).UTC()), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unknown timestamp unit %q", unit)
+	}
+}
+
+// unixTimeFloat converts a floating-point Unix timestamp in the given unit to a UTC time.Time.
+// Integral values are converted exactly; fractional values keep sub-unit precision.
+func unixTimeFloat(ts float64, unit string) (reflect.Value, error) {
+	if math.IsNaN(ts) || math.IsInf(ts, 0) || math.Abs(ts) >= math.MaxInt64 {
+		return reflect.Value{}, fmt.Errorf("timestamp %v out of range", ts)
+	}
+
+	if ts == math.Trunc(ts) {
+		return unixTime(int64(ts), unit)
+	}
+
+	if unit == TimestampUnitAuto {
+		unit = detectTimestampUnit(ts)
+	}
+
+	var perSecond float64
+	switch unit {
+	case "", TimestampUnitSeconds:
+		perSecond = 1
+	case TimestampUnitMillis:
+		perSecond = 1e3
+	case TimestampUnitMicros:
+		perSecond = 1e6
+	case TimestampUnitNanos:
+		perSecond = 1e9
+	default:
+		return reflect.Value{}, fmt.Errorf("unknown timestamp unit %q", unit)
+	}
+
+	sec, frac := math.Modf(ts / perSecond)
+
+	return reflect.ValueOf(time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC()), nil
+}
+
+// detectTimestampUnit guesses the unit of a Unix timestamp from its magnitude.
+func detectTimestampUnit(ts float64) string {
+	abs := math.Abs(ts)
+
+	switch {
+	case abs < autoMillisThreshold:
+		return TimestampUnitSeconds
+	case abs < autoMicrosThreshold:
+		return TimestampUnitMillis
+	case abs < autoNanosThreshold:
+		return TimestampUnitMicros
+	default:
+		return TimestampUnitNanos
+	}
+}
+
+// convertDate converts a value to Date.
+// Handles Date, time.Time, and strings in date-only or RFC 3339 form.
+func convertDate(value any) (reflect.Value, error) {
+	switch v := value.(type) {
+	case Date:
+		return reflect.ValueOf(v), nil
+	case time.Time:
+		return reflect.ValueOf(DateOf(v)), nil
+	case string:
+		if v == "" {
+			return reflect.ValueOf(Date{}), nil
+		}
+		if d, err := ParseDate(v); err == nil {
+			return reflect.ValueOf(d), nil
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return reflect.ValueOf(DateOf(t)), nil
+		}
+
+		return reflect.Value{}, fmt.Errorf("cannot parse %q as date", v)
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to Date", value)
+}
+
+// convertDateField converts a value to Date, honoring the field's "unit" and "format" tag
+// options as convertTimeField does. Values are handled by convertDate without them.
+func convertDateField(value any, field *FieldMetadata) (reflect.Value, error) {
+	if !hasTimeOptions(value, field) {
+		return convertDate(value)
+	}
+
+	t, err := convertTimeField(value, field)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return reflect.ValueOf(DateOf(t.Interface().(time.Time))), nil //nolint:forcetypeassert // convertTimeField returns time.Time
+}
+
+// convertTimeOfDayField converts a value to TimeOfDay, honoring the field's "unit" and "format"
+// tag options as convertTimeField does. Values are handled by convertTimeOfDay without them.
+func convertTimeOfDayField(value any, field *FieldMetadata) (reflect.Value, error) {
+	if !hasTimeOptions(value, field) {
+		return convertTimeOfDay(value)
+	}
+
+	t, err := convertTimeField(value, field)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return reflect.ValueOf(TimeOfDayOf(t.Interface().(time.Time))), nil //nolint:forcetypeassert // convertTimeField returns time.Time
+}
+
+// hasTimeOptions reports whether field has a "unit" or "format" option applying to value.
+// Empty strings keep decoding to the zero value.
+func hasTimeOptions(value any, field *FieldMetadata) bool {
+	if s, ok := value.(string); ok && s == "" {
+		return false
+	}
+	_, hasUnit := field.Option(OptionUnit)
+	_, hasFormat := field.Option(OptionFormat)
+
+	return hasUnit || hasFormat
+}
+
+// convertTimeOfDay converts a value to TimeOfDay.
+// Handles TimeOfDay, time.Time, and strings in "15:04", "15:04:05" or RFC 3339 form.
+func convertTimeOfDay(value any) (reflect.Value, error) {
+	switch v := value.(type) {
+	case TimeOfDay:
+		return reflect.ValueOf(v), nil
+	case time.Time:
+		return reflect.ValueOf(TimeOfDayOf(v)), nil
+	case string:
+		if v == "" {
+			return reflect.ValueOf(TimeOfDay{}), nil
+		}
+		if t, err := parseTimeOfDay(v); err == nil {
+			return reflect.ValueOf(TimeOfDayOf(t)), nil
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return reflect.ValueOf(TimeOfDayOf(t)), nil
+		}
+
+		return reflect.Value{}, fmt.Errorf("cannot parse %q as time of day", v)
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to TimeOfDay", value)
+}
+
+// parseTime parses an RFC 3339, date-only or time-only string into a time.Time.
+// The empty string yields the zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(DateLayout, s); err == nil {
+		return t, nil
+	}
+
+	if t, err := parseTimeOfDay(s); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as time", s)
+}
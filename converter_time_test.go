@@ -0,0 +1,356 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_convertTime(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   any
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "time", input: ts, want: ts},
+		{name: "rfc3339", input: "2024-01-15T10:30:00Z", want: ts},
+		{name: "date only", input: "2024-01-15", want: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "time only", input: "14:30", want: time.Date(0, 1, 1, 14, 30, 0, 0, time.UTC)},
+		{name: "time only with seconds", input: "14:30:05", want: time.Date(0, 1, 1, 14, 30, 5, 0, time.UTC)},
+		{name: "date value", input: Date{Year: 2024, Month: time.January, Day: 15}, want: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "time of day value", input: TimeOfDay{Hour: 8}, want: time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{name: "empty string", input: "", want: time.Time{}},
+		{name: "invalid string", input: "yesterday", wantErr: true},
+		{name: "invalid bool", input: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertTime(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			//nolint:forcetypeassert // Test code
+			assert.True(t, tt.want.Equal(result.Interface().(time.Time)), "got %v", result.Interface())
+		})
+	}
+}
+
+func TestConverter_convertDate(t *testing.T) {
+	want := Date{Year: 2024, Month: time.January, Day: 15}
+
+	tests := []struct {
+		name    string
+		input   any
+		want    Date
+		wantErr bool
+	}{
+		{name: "date", input: want, want: want},
+		{name: "date string", input: "2024-01-15", want: want},
+		{name: "rfc3339 string", input: "2024-01-15T23:59:59Z", want: want},
+		{name: "time", input: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), want: want},
+		{name: "empty string", input: "", want: Date{}},
+		{name: "invalid string", input: "15/01/2024", wantErr: true},
+		{name: "invalid int", input: 20240115, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertDate(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Interface())
+		})
+	}
+}
+
+func TestConverter_convertTimeOfDay(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   any
+		want    TimeOfDay
+		wantErr bool
+	}{
+		{name: "time of day", input: TimeOfDay{Hour: 14, Minute: 30}, want: TimeOfDay{Hour: 14, Minute: 30}},
+		{name: "short string", input: "14:30", want: TimeOfDay{Hour: 14, Minute: 30}},
+		{name: "rfc3339 string", input: "2024-01-15T14:30:00Z", want: TimeOfDay{Hour: 14, Minute: 30}},
+		{name: "time", input: time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC), want: TimeOfDay{Hour: 14, Minute: 30}},
+		{name: "empty string", input: "", want: TimeOfDay{}},
+		{name: "invalid string", input: "2pm", wantErr: true},
+		{name: "invalid float", input: 14.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertTimeOfDay(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Interface())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_CivilTypes(t *testing.T) {
+	type Booking struct {
+		Day       Date      `schema:"day"`
+		Opens     TimeOfDay `schema:"opens"`
+		CreatedAt time.Time `schema:"created_at"`
+		Birthday  *Date     `schema:"birthday"`
+	}
+
+	data := map[string]any{
+		"day":        "2024-01-15",
+		"opens":      "09:00",
+		"created_at": "2024-01-15",
+		"birthday":   "1990-06-01",
+	}
+
+	var result Booking
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, Date{Year: 2024, Month: time.January, Day: 15}, result.Day)
+	assert.Equal(t, TimeOfDay{Hour: 9}, result.Opens)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), result.CreatedAt)
+	require.NotNil(t, result.Birthday)
+	assert.Equal(t, "1990-06-01", result.Birthday.String())
+}
+
+func TestConverter_convertTimeField(t *testing.T) {
+	seconds := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	withMillis := time.Date(2024, 1, 15, 10, 30, 0, 123000000, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   any
+		unit    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "seconds by default", input: seconds.Unix(), want: seconds},
+		{name: "explicit seconds", input: int32(seconds.Unix()), unit: "s", want: seconds},
+		{name: "milliseconds", input: withMillis.UnixMilli(), unit: "ms", want: withMillis},
+		{name: "microseconds", input: withMillis.UnixMicro(), unit: "us", want: withMillis},
+		{name: "nanoseconds", input: withMillis.UnixNano(), unit: "ns", want: withMillis},
+		{name: "uint", input: uint64(seconds.Unix()), want: seconds},
+		{name: "float seconds", input: float64(seconds.Unix()), want: seconds},
+		{name: "fractional seconds", input: float64(seconds.Unix()) + 0.5, unit: "s", want: seconds.Add(500 * time.Millisecond)},
+		{name: "numeric string with unit", input: "1705314600000", unit: "ms", want: seconds},
+		{name: "numeric string without unit", input: "1705314600", want: seconds},
+		{name: "nanosecond string", input: "1700000000123456789", unit: "ns", want: time.Unix(0, 1700000000123456789)},
+		{name: "json.Number", input: json.Number("1705314600"), want: seconds},
+		{name: "nanosecond json.Number", input: json.Number("1700000000123456789"), unit: "ns", want: time.Unix(0, 1700000000123456789)},
+		{name: "fractional json.Number", input: json.Number("1705314600.5"), want: seconds.Add(500 * time.Millisecond)},
+		{name: "auto seconds", input: seconds.Unix(), unit: "auto", want: seconds},
+		{name: "auto milliseconds", input: withMillis.UnixMilli(), unit: "auto", want: withMillis},
+		{name: "auto microseconds", input: withMillis.UnixMicro(), unit: "auto", want: withMillis},
+		{name: "auto nanoseconds", input: withMillis.UnixNano(), unit: "auto", want: withMillis},
+		{name: "string falls back to time parsing", input: "2024-01-15T10:30:00Z", unit: "ms", want: seconds},
+		{name: "unknown unit", input: int64(1), unit: "days", wantErr: true},
+		{name: "uint out of range", input: uint64(1 << 63), wantErr: true},
+		{name: "float out of range", input: 1e300, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var field *FieldMetadata
+			if tt.unit != "" {
+				field = &FieldMetadata{Options: map[string]string{OptionUnit: tt.unit}}
+			}
+
+			result, err := convertTimeField(tt.input, field)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			//nolint:forcetypeassert // Test code
+			assert.True(t, tt.want.Equal(result.Interface().(time.Time)), "got %v", result.Interface())
+		})
+	}
+}
+
+func TestConverter_convertTimeField_Format(t *testing.T) {
+	seconds := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   any
+		format  string
+		unit    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "layout", input: "15/01/2024", format: "02/01/2006", want: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "named layout", input: "Mon, 15 Jan 2024 10:30:00 UTC", format: time.RFC1123, want: seconds},
+		{name: "layout with empty string", input: "", format: "2006-01-02", want: time.Time{}},
+		{name: "layout ignores numbers", input: seconds.Unix(), format: "2006-01-02", want: seconds},
+		{name: "layout mismatch", input: "2024-01-15", format: "02/01/2006", wantErr: true},
+		{name: "unix", input: seconds.Unix(), format: "unix", want: seconds},
+		{name: "unix string", input: "1705314600", format: "unix", want: seconds},
+		{name: "unixmilli", input: seconds.UnixMilli(), format: "unixmilli", want: seconds},
+		{name: "unixmilli string", input: "1705314600000", format: "unixmilli", want: seconds},
+		{name: "format wins over unit", input: seconds.UnixMilli(), format: "unixmilli", unit: "s", want: seconds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := &FieldMetadata{Options: map[string]string{"format": tt.format}}
+			if tt.unit != "" {
+				field.Options["unit"] = tt.unit
+			}
+
+			result, err := convertTimeField(tt.input, field)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			//nolint:forcetypeassert // Test code
+			assert.True(t, tt.want.Equal(result.Interface().(time.Time)), "got %v", result.Interface())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_TimeFormats(t *testing.T) {
+	type Person struct {
+		Born     time.Time   `schema:"born,format=2006-01-02"`
+		Seen     *time.Time  `schema:"seen,format=unix"`
+		Logins   []time.Time `schema:"logins,format=unixmilli"`
+		Modified time.Time   `schema:"modified"`
+	}
+
+	at := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	data := map[string]any{
+		"born":     "1990-05-17",
+		"seen":     at.Unix(),
+		"logins":   []any{at.UnixMilli(), "1705314600000"},
+		"modified": "2024-01-15T10:30:00Z",
+	}
+
+	var result Person
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.True(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC).Equal(result.Born))
+	require.NotNil(t, result.Seen)
+	assert.True(t, at.Equal(*result.Seen))
+	require.Len(t, result.Logins, 2)
+	assert.True(t, at.Equal(result.Logins[0]))
+	assert.True(t, at.Equal(result.Logins[1]))
+	assert.True(t, at.Equal(result.Modified))
+
+	t.Run("error reports field", func(t *testing.T) {
+		var result Person
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"born": "17.05.1990"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "born", convErr.FieldPath)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_CivilTimeFormats(t *testing.T) {
+	type Shift struct {
+		Born   Date        `schema:"born,format=02/01/2006"`
+		Hired  *Date       `schema:"hired,format=2006-01-02"`
+		Starts TimeOfDay   `schema:"starts,format=3:04PM"`
+		Breaks []TimeOfDay `schema:"breaks,format=3:04PM"`
+		Day    Date        `schema:"day,format=unix"`
+		Plain  Date        `schema:"plain"`
+	}
+
+	var result Shift
+	err := NewDefaultUnmarshaler().Unmarshal(map[string]any{
+		"born":   "17/05/1990",
+		"hired":  "2020-03-01",
+		"starts": "9:30AM",
+		"breaks": []any{"12:00PM", "3:15PM"},
+		"day":    time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC).Unix(),
+		"plain":  "2024-01-15",
+	}, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, Date{Year: 1990, Month: time.May, Day: 17}, result.Born)
+	require.NotNil(t, result.Hired)
+	assert.Equal(t, Date{Year: 2020, Month: time.March, Day: 1}, *result.Hired)
+	assert.Equal(t, TimeOfDay{Hour: 9, Minute: 30}, result.Starts)
+	assert.Equal(t, []TimeOfDay{{Hour: 12}, {Hour: 15, Minute: 15}}, result.Breaks)
+	assert.Equal(t, Date{Year: 2024, Month: time.January, Day: 15}, result.Day)
+	assert.Equal(t, Date{Year: 2024, Month: time.January, Day: 15}, result.Plain)
+
+	t.Run("empty string", func(t *testing.T) {
+		var result Shift
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(map[string]any{"born": ""}, &result))
+		assert.Equal(t, Date{}, result.Born)
+	})
+
+	t.Run("layout mismatch", func(t *testing.T) {
+		var result Shift
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"born": "1990-05-17"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "born", convErr.FieldPath)
+	})
+}
+
+func TestDecodeJSON_Timestamps(t *testing.T) {
+	type Event struct {
+		At      time.Time `schema:"at,unit=ns"`
+		Seconds time.Time `schema:"seconds"`
+	}
+
+	var result Event
+	require.NoError(t, DecodeJSON([]byte(`{"at": 1700000000123456789, "seconds": 1700000000}`), &result))
+	assert.True(t, time.Unix(0, 1700000000123456789).Equal(result.At), "got %v", result.At)
+	assert.True(t, time.Unix(1700000000, 0).Equal(result.Seconds), "decoded like an int")
+}
+
+func TestUnmarshaler_Unmarshal_TimestampUnits(t *testing.T) {
+	type Event struct {
+		Created  time.Time   `schema:"created,unit=ms"`
+		Updated  *time.Time  `schema:"updated,unit=auto"`
+		Seen     []time.Time `schema:"seen,unit=s"`
+		Received time.Time   `schema:"received"`
+	}
+
+	want := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	data := map[string]any{
+		"created":  want.UnixMilli(),
+		"updated":  want.UnixMilli(),
+		"seen":     []any{want.Unix(), float64(want.Unix())},
+		"received": want.Unix(),
+	}
+
+	var result Event
+	err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.True(t, want.Equal(result.Created))
+	require.NotNil(t, result.Updated)
+	assert.True(t, want.Equal(*result.Updated))
+	require.Len(t, result.Seen, 2)
+	assert.True(t, want.Equal(result.Seen[0]))
+	assert.True(t, want.Equal(result.Seen[1]))
+	assert.True(t, want.Equal(result.Received))
+}
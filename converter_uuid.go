@@ -0,0 +1,123 @@
+package mapstructure
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	uuidLen        = 36 // xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+	uuidHexLen     = 32 // xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+	uuidURNPrefix  = "urn:uuid:"
+	uuidBytesCount = 16
+)
+
+// uuidHyphens lists the positions of the hyphens in the canonical UUID form.
+var uuidHyphens = [4]int{8, 13, 18, 23}
+
+// UUIDConverters returns converters for UUIDs stored as [16]byte.
+// They are not part of the default registry; pass them to NewDefaultConverterRegistry:
+//
+//	converters := NewDefaultConverterRegistry(UUIDConverters())
+//
+// For named UUID types such as github.com/google/uuid.UUID use NewUUIDConverter.
+func UUIDConverters() map[reflect.Type]Converter {
+	return map[reflect.Type]Converter{
+		reflect.TypeOf([uuidBytesCount]byte{}): NewUUIDConverter[[uuidBytesCount]byte](),
+	}
+}
+
+// NewUUIDConverter returns a converter for any UUID type backed by [16]byte:
+//
+//	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+//		reflect.TypeOf(uuid.UUID{}): NewUUIDConverter[uuid.UUID](),
+//	})
+//
+// Strings are parsed with ParseUUID; [16]byte arrays and 16-byte slices are taken as raw bytes.
+// The empty string yields the zero UUID.
+func NewUUIDConverter[T ~[16]byte]() Converter {
+	return func(value any) (reflect.Value, error) {
+		u, err := convertToUUID(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(T(u)), nil
+	}
+}
+
+// ParseUUID parses a UUID in canonical ("xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"),
+// braced ("{xxxxxxxx-...}"), URN ("urn:uuid:xxxxxxxx-...") or 32-digit hex form.
+// Hex digits are case-insensitive.
+func ParseUUID(s string) ([16]byte, error) {
+	var u [uuidBytesCount]byte
+
+	text := s
+	switch {
+	case len(text) == uuidLen+len(uuidURNPrefix) && strings.EqualFold(text[:len(uuidURNPrefix)], uuidURNPrefix):
+		text = text[len(uuidURNPrefix):]
+	case len(text) == uuidLen+2 && text[0] == '{':
+		if text[len(text)-1] != '}' {
+			return u, fmt.Errorf("invalid UUID %q: missing closing brace", s)
+		}
+		text = text[1 : len(text)-1]
+	case len(text) == uuidHexLen:
+		if _, err := hex.Decode(u[:], []byte(text)); err != nil {
+			return u, fmt.Errorf("invalid UUID %q: invalid hex digit", s)
+		}
+
+		return u, nil
+	case len(text) != uuidLen:
+		return u, fmt.Errorf("invalid UUID %q: length %d, want %d", s, len(s), uuidLen)
+	}
+
+	for _, pos := range uuidHyphens {
+		if text[pos] != '-' {
+			return u, fmt.Errorf("invalid UUID %q: expected '-' at position %d", s, pos)
+		}
+	}
+
+	digits := text[0:8] + text[9:13] + text[14:18] + text[19:23] + text[24:]
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return u, fmt.Errorf("invalid UUID %q: invalid hex digit", s)
+	}
+
+	return u, nil
+}
+
+// convertToUUID converts a value to its 16 raw UUID bytes.
+func convertToUUID(value any) ([16]byte, error) {
+	var u [uuidBytesCount]byte
+
+	dataVal := reflect.Indirect(reflect.ValueOf(value))
+
+	//nolint:exhaustive // Only handling convertible types
+	switch dataVal.Kind() {
+	case reflect.String:
+		if dataVal.Len() == 0 {
+			return u, nil
+		}
+
+		return ParseUUID(dataVal.String())
+	case reflect.Array:
+		if dataVal.Len() == uuidBytesCount && dataVal.Type().Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(reflect.ValueOf(&u).Elem(), dataVal)
+
+			return u, nil
+		}
+	case reflect.Slice:
+		if dataVal.Type().Elem().Kind() == reflect.Uint8 {
+			if dataVal.Len() == uuidBytesCount {
+				reflect.Copy(reflect.ValueOf(&u).Elem(), dataVal)
+
+				return u, nil
+			}
+
+			return ParseUUID(string(dataVal.Bytes()))
+		}
+	}
+
+	return u, fmt.Errorf("cannot convert %T to UUID", value)
+}
@@ -0,0 +1,127 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUUID mirrors named UUID types such as github.com/google/uuid.UUID.
+type testUUID [16]byte
+
+func TestParseUUID(t *testing.T) {
+	want := [16]byte{
+		0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3,
+		0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "canonical", input: "123e4567-e89b-12d3-a456-426614174000"},
+		{name: "uppercase", input: "123E4567-E89B-12D3-A456-426614174000"},
+		{name: "braced", input: "{123e4567-e89b-12d3-a456-426614174000}"},
+		{name: "urn", input: "urn:uuid:123e4567-e89b-12d3-a456-426614174000"},
+		{name: "hex only", input: "123e4567e89b12d3a456426614174000"},
+		{name: "too short", input: "123e4567-e89b", wantErr: "length 13, want 36"},
+		{name: "unclosed brace", input: "{123e4567-e89b-12d3-a456-426614174000)", wantErr: "missing closing brace"},
+		{name: "misplaced hyphen", input: "123e4567e-89b-12d3-a456-426614174000", wantErr: "expected '-' at position 8"},
+		{name: "invalid hex", input: "123e4567-e89b-12d3-a456-42661417400g", wantErr: "invalid hex digit"},
+		{name: "invalid hex only", input: "123e4567e89b12d3a45642661417400z", wantErr: "invalid hex digit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUUID(tt.input)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestConverter_NewUUIDConverter(t *testing.T) {
+	raw := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	text := "01020304-0506-0708-090a-0b0c0d0e0f10"
+
+	tests := []struct {
+		name    string
+		input   any
+		want    testUUID
+		wantErr bool
+	}{
+		{name: "string", input: text, want: testUUID(raw)},
+		{name: "string pointer", input: &text, want: testUUID(raw)},
+		{name: "array", input: raw, want: testUUID(raw)},
+		{name: "raw bytes", input: raw[:], want: testUUID(raw)},
+		{name: "text bytes", input: []byte(text), want: testUUID(raw)},
+		{name: "empty string", input: "", want: testUUID{}},
+		{name: "invalid string", input: "not-a-uuid", wantErr: true},
+		{name: "invalid int", input: 42, wantErr: true},
+	}
+
+	conv := NewUUIDConverter[testUUID]()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := conv(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Interface())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_UUID(t *testing.T) {
+	type Resource struct {
+		ID       testUUID   `schema:"id"`
+		OwnerID  [16]byte   `schema:"owner_id"`
+		ParentID *testUUID  `schema:"parent_id"`
+		Tags     []testUUID `schema:"tags"`
+	}
+
+	converters := NewDefaultConverterRegistry(UUIDConverters(), map[reflect.Type]Converter{
+		reflect.TypeOf(testUUID{}): NewUUIDConverter[testUUID](),
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+	data := map[string]any{
+		"id":        "01020304-0506-0708-090a-0b0c0d0e0f10",
+		"owner_id":  "{01020304-0506-0708-090a-0b0c0d0e0f10}",
+		"parent_id": "urn:uuid:01020304-0506-0708-090a-0b0c0d0e0f10",
+		"tags":      []any{"01020304050607080910111213141516"},
+	}
+
+	var result Resource
+	err := u.Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	want := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	assert.Equal(t, testUUID(want), result.ID)
+	assert.Equal(t, want, result.OwnerID)
+	require.NotNil(t, result.ParentID)
+	assert.Equal(t, testUUID(want), *result.ParentID)
+	require.Len(t, result.Tags, 1)
+
+	t.Run("invalid format reports field", func(t *testing.T) {
+		var result Resource
+		err := u.Unmarshal(map[string]any{"id": "01020304-0506"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "id", convErr.FieldPath)
+		assert.Contains(t, err.Error(), "invalid UUID")
+	})
+}
@@ -0,0 +1,107 @@
+package mapstructure
+
+import "reflect"
+
+// WithCopyReferences makes the unmarshaler deep-copy slices, maps and pointers that
+// would otherwise be assigned directly from the input, so later mutations of the input
+// do not leak into the decoded struct (and vice versa).
+func WithCopyReferences() Option {
+	return func(u *Unmarshaler) {
+		u.copyReferences = true
+	}
+}
+
+// assignable returns v, deep-copied when the unmarshaler copies references.
+func (u *Unmarshaler) assignable(v reflect.Value) reflect.Value {
+	if !u.copyReferences {
+		return v
+	}
+
+	return deepCopy(v, make(map[copyKey]reflect.Value))
+}
+
+// copyKey identifies an already copied pointer or map, so shared and cyclic references
+// are copied once and keep their shape.
+type copyKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// deepCopy returns a deep copy of v. Unexported struct fields are copied shallowly.
+func deepCopy(v reflect.Value, seen map[copyKey]reflect.Value) reflect.Value {
+	//nolint:exhaustive // Other kinds hold no references
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		key := copyKey{ptr: v.Pointer(), typ: v.Type()}
+		if c, ok := seen[key]; ok {
+			return c
+		}
+
+		c := reflect.New(v.Type().Elem())
+		seen[key] = c
+		c.Elem().Set(deepCopy(v.Elem(), seen))
+
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		key := copyKey{ptr: v.Pointer(), typ: v.Type()}
+		if c, ok := seen[key]; ok {
+			return c
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		seen[key] = c
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(deepCopy(iter.Key(), seen), deepCopy(iter.Value(), seen))
+		}
+
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			c.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), seen))
+
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i), seen))
+			}
+		}
+
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,112 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepCopy(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+		Tags []string
+	}
+
+	loop := &node{Name: "a", Tags: []string{"x"}}
+	loop.Next = loop
+
+	shared := []int{1}
+	input := map[string]any{
+		"slice":  []any{map[string]any{"k": []int{1, 2}}},
+		"array":  [2][]int{{1}, {2}},
+		"loop":   loop,
+		"shared": []any{shared, shared},
+		"nil":    []int(nil),
+	}
+
+	//nolint:forcetypeassert // Test code
+	c := deepCopy(reflect.ValueOf(input), make(map[copyKey]reflect.Value)).Interface().(map[string]any)
+
+	assert.Equal(t, input, c)
+
+	//nolint:forcetypeassert // Test code
+	c["slice"].([]any)[0].(map[string]any)["k"].([]int)[0] = 99
+	//nolint:forcetypeassert // Test code
+	assert.Equal(t, 1, input["slice"].([]any)[0].(map[string]any)["k"].([]int)[0])
+
+	//nolint:forcetypeassert // Test code
+	c["array"].([2][]int)[0][0] = 99
+	//nolint:forcetypeassert // Test code
+	assert.Equal(t, 1, input["array"].([2][]int)[0][0])
+
+	//nolint:forcetypeassert // Test code
+	copied := c["loop"].(*node)
+	assert.NotSame(t, loop, copied)
+	assert.Same(t, copied, copied.Next, "cycles keep their shape")
+	copied.Tags[0] = "y"
+	assert.Equal(t, "x", loop.Tags[0])
+
+	assert.Nil(t, c["nil"])
+}
+
+func TestUnmarshaler_Unmarshal_CopyReferences(t *testing.T) {
+	type Labels []string
+
+	type Config struct {
+		Tags     []string          `schema:"tags"`
+		Labels   Labels            `schema:"labels"`
+		Meta     map[string]string `schema:"meta"`
+		Limit    *int              `schema:"limit"`
+		Settings any               `schema:"settings"`
+		Items    []any             `schema:"items"`
+	}
+
+	limit := 5
+	data := map[string]any{
+		"tags":     []string{"a"},
+		"labels":   []string{"l"},
+		"meta":     map[string]string{"k": "v"},
+		"limit":    &limit,
+		"settings": map[string]any{"debug": true},
+		"items":    []map[string]any{{"id": 1}},
+	}
+
+	t.Run("default shares input", func(t *testing.T) {
+		var result Config
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(data, &result))
+		assert.Same(t, &limit, result.Limit)
+	})
+
+	t.Run("copies references", func(t *testing.T) {
+		var result Config
+		require.NoError(t, NewDefaultUnmarshaler(WithCopyReferences()).Unmarshal(data, &result))
+
+		assert.Equal(t, []string{"a"}, result.Tags)
+		assert.Equal(t, Labels{"l"}, result.Labels)
+		require.NotNil(t, result.Limit)
+		assert.Equal(t, 5, *result.Limit)
+		assert.NotSame(t, &limit, result.Limit)
+
+		//nolint:forcetypeassert // Test code
+		data["tags"].([]string)[0] = "changed"
+		//nolint:forcetypeassert // Test code
+		data["labels"].([]string)[0] = "changed"
+		//nolint:forcetypeassert // Test code
+		data["meta"].(map[string]string)["k"] = "changed"
+		//nolint:forcetypeassert // Test code
+		data["settings"].(map[string]any)["debug"] = false
+		//nolint:forcetypeassert // Test code
+		data["items"].([]map[string]any)[0]["id"] = 2
+		limit = 6
+
+		assert.Equal(t, []string{"a"}, result.Tags)
+		assert.Equal(t, Labels{"l"}, result.Labels)
+		assert.Equal(t, map[string]string{"k": "v"}, result.Meta)
+		assert.Equal(t, map[string]any{"debug": true}, result.Settings)
+		assert.Equal(t, []any{map[string]any{"id": 1}}, result.Items)
+		assert.Equal(t, 5, *result.Limit)
+	})
+}
@@ -0,0 +1,222 @@
+// Package decimal provides an arbitrary-precision fixed-point Decimal type and
+// mapstructure converters for it, so money and other exact values can be decoded
+// from strings and json.Number without passing through float64.
+//
+// The converters are not part of the default registry; register them explicitly:
+//
+//	converters := mapstructure.NewDefaultConverterRegistry(decimal.Converters())
+package decimal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/talav/mapstructure"
+)
+
+// maxScale bounds the exponent accepted by Parse so hostile input such as "1e999999999"
+// cannot force huge allocations.
+const maxScale = 1 << 16
+
+// Decimal is an exact decimal number represented as coefficient × 10^-scale.
+// A negative scale denotes trailing zeros, as produced by parsing "15e2".
+// The zero value is 0. Decimal values are immutable.
+type Decimal struct {
+	coef  *big.Int // nil means zero
+	scale int32
+}
+
+// New returns the Decimal coef × 10^-scale.
+func New(coef int64, scale int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// Parse parses a decimal string such as "12", "-0.05" or "1.5e3".
+// The exact digits are preserved; "1.50" keeps a scale of 2.
+func Parse(s string) (Decimal, error) {
+	text := s
+	exp := int64(0)
+
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(text[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q: bad exponent", s)
+		}
+		exp = e
+		text = text[:i]
+	}
+
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		exp -= int64(len(text) - i - 1)
+		text = text[:i] + text[i+1:]
+	}
+
+	digits := strings.TrimLeft(text, "+-")
+	if digits == "" || len(text)-len(digits) > 1 || strings.ContainsAny(digits, "+-") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	coef, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	if exp < -maxScale || exp > maxScale {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: exponent out of range", s)
+	}
+
+	return Decimal{coef: coef, scale: int32(-exp)}, nil
+}
+
+// MustParse is like Parse but panics if s cannot be parsed.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// Coefficient returns a copy of the unscaled value.
+func (d Decimal) Coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(d.coef)
+}
+
+// Scale returns the number of digits after the decimal point (negative for trailing zeros).
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// IsZero reports whether d equals 0.
+func (d Decimal) IsZero() bool {
+	return d.coef == nil || d.coef.Sign() == 0
+}
+
+// Cmp compares d and other and returns -1, 0 or +1. Scale is ignored, so 1.5 equals 1.50.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := d.Coefficient(), other.Coefficient()
+
+	switch {
+	case d.scale < other.scale:
+		a.Mul(a, pow10(other.scale-d.scale))
+	case d.scale > other.scale:
+		b.Mul(b, pow10(d.scale-other.scale))
+	}
+
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and other represent the same number.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Float64 returns the nearest float64 to d. The result may lose precision.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+
+	return f
+}
+
+// String returns d in plain decimal notation, keeping its scale ("1.50").
+func (d Decimal) String() string {
+	digits := d.Coefficient().String()
+
+	neg := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	switch {
+	case d.scale > 0:
+		if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	case d.scale < 0:
+		digits += strings.Repeat("0", int(-d.scale))
+	}
+
+	if neg {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+
+	return nil
+}
+
+// Converters returns mapstructure converters for Decimal.
+func Converters() map[reflect.Type]mapstructure.Converter {
+	return map[reflect.Type]mapstructure.Converter{
+		reflect.TypeOf(Decimal{}): convertDecimal,
+	}
+}
+
+// convertDecimal converts a value to Decimal.
+// Handles Decimal, json.Number, strings, and integers exactly. Floats are converted
+// from their shortest decimal representation, so 0.1 decodes as 0.1.
+func convertDecimal(value any) (reflect.Value, error) {
+	switch v := value.(type) {
+	case Decimal:
+		return reflect.ValueOf(v), nil
+	case json.Number:
+		return parseValue(string(v))
+	}
+
+	dataVal := reflect.Indirect(reflect.ValueOf(value))
+
+	//nolint:exhaustive // Only handling convertible types
+	switch dataVal.Kind() {
+	case reflect.String:
+		if dataVal.Len() == 0 {
+			return reflect.ValueOf(Decimal{}), nil
+		}
+
+		return parseValue(dataVal.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.ValueOf(New(dataVal.Int(), 0)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.ValueOf(Decimal{coef: new(big.Int).SetUint64(dataVal.Uint())}), nil
+	case reflect.Float32, reflect.Float64:
+		return parseValue(strconv.FormatFloat(dataVal.Float(), 'g', -1, dataVal.Type().Bits()))
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot convert %T to Decimal", value)
+	}
+}
+
+// parseValue parses s into a Decimal reflect.Value.
+func parseValue(s string) (reflect.Value, error) {
+	d, err := Parse(s)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return reflect.ValueOf(d), nil
+}
+
+// pow10 returns 10^n as a big.Int.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,140 @@
+package decimal
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talav/mapstructure"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantScale int32
+		wantErr   bool
+	}{
+		{name: "integer", input: "42", want: "42"},
+		{name: "fraction keeps trailing zeros", input: "19.90", want: "19.90", wantScale: 2},
+		{name: "negative", input: "-0.05", want: "-0.05", wantScale: 2},
+		{name: "leading dot", input: ".5", want: "0.5", wantScale: 1},
+		{name: "explicit plus", input: "+7.25", want: "7.25", wantScale: 2},
+		{name: "positive exponent", input: "1.5e3", want: "1500", wantScale: -2},
+		{name: "negative exponent", input: "25E-4", want: "0.0025", wantScale: 4},
+		{name: "beyond float64 precision", input: "12345678901234567890.123456789", want: "12345678901234567890.123456789", wantScale: 9},
+		{name: "empty", input: "", wantErr: true},
+		{name: "sign only", input: "-", wantErr: true},
+		{name: "double sign", input: "--1", wantErr: true},
+		{name: "letters", input: "12abc", wantErr: true},
+		{name: "two dots", input: "1.2.3", wantErr: true},
+		{name: "bad exponent", input: "1e", wantErr: true},
+		{name: "exponent out of range", input: "1e999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+			assert.Equal(t, tt.wantScale, got.Scale())
+		})
+	}
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	assert.Equal(t, 0, MustParse("1.5").Cmp(MustParse("1.50")))
+	assert.Equal(t, -1, MustParse("0.1").Cmp(MustParse("0.11")))
+	assert.Equal(t, 1, MustParse("1e2").Cmp(MustParse("99.99")))
+	assert.True(t, Decimal{}.Equal(New(0, 3)))
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.InDelta(t, 19.9, MustParse("19.90").Float64(), 1e-9)
+}
+
+func TestDecimal_Text(t *testing.T) {
+	var d Decimal
+	require.NoError(t, d.UnmarshalText([]byte("3.14")))
+
+	text, err := d.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "3.14", string(text))
+	require.Error(t, d.UnmarshalText([]byte("pi")))
+}
+
+func TestConverters(t *testing.T) {
+	conv := Converters()[reflect.TypeOf(Decimal{})]
+	require.NotNil(t, conv)
+
+	tests := []struct {
+		name    string
+		input   any
+		want    string
+		wantErr bool
+	}{
+		{name: "decimal", input: MustParse("1.10"), want: "1.10"},
+		{name: "string", input: "1234.5678", want: "1234.5678"},
+		{name: "json number", input: json.Number("0.30"), want: "0.30"},
+		{name: "int", input: 42, want: "42"},
+		{name: "uint", input: uint64(18446744073709551615), want: "18446744073709551615"},
+		{name: "float uses shortest representation", input: 0.1, want: "0.1"},
+		{name: "empty string", input: "", want: "0"},
+		{name: "invalid string", input: "ten", wantErr: true},
+		{name: "invalid bool", input: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := conv(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			//nolint:forcetypeassert // Test code
+			assert.Equal(t, tt.want, result.Interface().(Decimal).String())
+		})
+	}
+}
+
+func TestUnmarshal_Decimal(t *testing.T) {
+	type Invoice struct {
+		Total    Decimal   `schema:"total"`
+		Discount *Decimal  `schema:"discount"`
+		Lines    []Decimal `schema:"lines"`
+	}
+
+	converters := mapstructure.NewDefaultConverterRegistry(Converters())
+	u := mapstructure.NewUnmarshaler(mapstructure.NewDefaultStructMetadataCache(), converters)
+
+	data := map[string]any{
+		"total":    json.Number("9007199254740993.01"),
+		"discount": "0.10",
+		"lines":    []any{"4503599627370496.50", json.Number("4503599627370496.51")},
+	}
+
+	var result Invoice
+	err := u.Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, "9007199254740993.01", result.Total.String())
+	require.NotNil(t, result.Discount)
+	assert.Equal(t, "0.10", result.Discount.String())
+	require.Len(t, result.Lines, 2)
+	assert.Equal(t, "4503599627370496.51", result.Lines[1].String())
+
+	err = u.Unmarshal(map[string]any{"total": "12,50"}, &result)
+	var convErr *mapstructure.ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, "total", convErr.FieldPath)
+}
@@ -0,0 +1,87 @@
+package mapstructure
+
+import "slices"
+
+// Metadata reports how a decode call used its input, see UnmarshalWithMetadata.
+// Paths are given as in errors, e.g. "address.city" or "lines[0].qty"; keys of embedded
+// structs decoded from a nested map are reported under the path of the outer struct.
+type Metadata struct {
+	Keys   []string // Input keys decoded into a field or collected by a remainder field, sorted
+	Unused []string // Input keys no field decoded, sorted
+	Unset  []string // Fields the input gave no value and that have no default, sorted
+
+	Skipped []SkippedElement // Slice elements dropped by the "skipinvalid" tag option, in decoding order
+}
+
+// UnmarshalWithMetadata decodes data into result like Unmarshal and reports which input
+// keys were decoded, which were unused and which fields were never set.
+// This is a convenience function that uses a shared default unmarshaler.
+func UnmarshalWithMetadata(data map[string]any, result any, opts ...CallOption) (Metadata, error) {
+	return defaultUnmarshaler.UnmarshalWithMetadata(data, result, opts...)
+}
+
+// UnmarshalWithMetadata decodes data into result like Unmarshal and reports which input
+// keys were decoded, which were unused and which fields were never set, e.g. to warn
+// users about ignored configuration, and which slice elements were skipped. Keys of fields outside the groups and version
+// selected for the call, and of func and chan fields, are unused; fields set from
+// default tags are not unset. On error, the metadata covers what was decoded before it.
+func (u *Unmarshaler) UnmarshalWithMetadata(data map[string]any, result any, opts ...CallOption) (Metadata, error) {
+	var metadata Metadata
+	err := u.Unmarshal(data, result, append(slices.Clip(opts), func(o *callOptions) {
+		o.metadata = &metadata
+	})...)
+
+	slices.Sort(metadata.Keys)
+	slices.Sort(metadata.Unused)
+	slices.Sort(metadata.Unset)
+
+	return metadata, err
+}
+
+// recordKeys records in the metadata of the call how the struct described by metadata,
+// at fieldPath, used the keys of dataMap.
+func (u *Unmarshaler) recordKeys(dataMap map[string]any, metadata *StructMetadata, closed []bool, fieldPath string) {
+	md := u.call.metadata
+	if md == nil {
+		return
+	}
+
+	remain := metadata.remain != 0 && isOpen(closed, &metadata.flat[metadata.remain-1]) &&
+		u.fieldSelected(&metadata.flat[metadata.remain-1])
+	for key := range dataMap {
+		// Keys combined with a nested map are recorded when decoding the embedded struct
+		if field, ok := metadata.FieldByKey(key); ok && !isOpen(closed, field) && u.promotedFrom(dataMap, metadata, closed, field) {
+			continue
+		}
+
+		if u.keyDecoded(key, dataMap, metadata, closed) || (remain && !metadata.HasKey(key)) {
+			md.Keys = append(md.Keys, buildFieldPath(fieldPath, key))
+		} else {
+			md.Unused = append(md.Unused, buildFieldPath(fieldPath, key))
+		}
+	}
+
+	for _, field := range u.unsetFields(dataMap, metadata, closed) {
+		md.Unset = append(md.Unset, buildFieldPath(fieldPath, field.MapKey))
+	}
+}
+
+// keyDecoded reports whether key of dataMap is decoded into a field of the struct
+// described by metadata, or into an embedded struct given as a nested map.
+func (u *Unmarshaler) keyDecoded(key string, dataMap map[string]any, metadata *StructMetadata, closed []bool) bool {
+	if field, ok := metadata.FieldByKey(key); ok {
+		return isOpen(closed, field) && u.fieldSelected(field) && !isRemain(field)
+	}
+
+	for i := range metadata.flat {
+		field := &metadata.flat[i]
+		if !field.Embedded || !isOpen(closed, field) || !u.fieldSelected(field) {
+			continue
+		}
+		if _, nested := nestedEmbed(dataMap, field); nested && field.StructFieldName == key {
+			return true
+		}
+	}
+
+	return false
+}
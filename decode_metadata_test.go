@@ -0,0 +1,138 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_UnmarshalWithMetadata(t *testing.T) {
+	type Address struct {
+		City string `schema:"city"`
+		Zip  string `schema:"zip" default:"00000"`
+	}
+	type Base struct {
+		ID string `schema:"id"`
+	}
+	type Server struct {
+		Base
+		Host    string    `schema:"host"`
+		Port    int       `schema:"port"`
+		Cost    int       `schema:"cost,groups=admin"`
+		Address *Address  `schema:"address"`
+		Lines   []Address `schema:"lines"`
+		Hook    func()    `schema:"hook"`
+	}
+
+	tests := []struct {
+		name string
+		data map[string]any
+		opts []CallOption
+		want Metadata
+	}{
+		{
+			name: "used, unused and unset",
+			data: map[string]any{"host": "a", "hots": "b", "id": "x", "hook": 1},
+			want: Metadata{
+				Keys:   []string{"host", "id"},
+				Unused: []string{"hook", "hots"},
+				Unset:  []string{"address", "lines", "port"},
+			},
+		},
+		{
+			name: "nested structs",
+			data: map[string]any{
+				"id": "x", "host": "a", "port": 1, "lines": nil,
+				"address": map[string]any{"city": "c", "state": "s"},
+			},
+			want: Metadata{
+				Keys:   []string{"address", "address.city", "host", "id", "lines", "port"},
+				Unused: []string{"address.state"},
+			},
+		},
+		{
+			name: "slices of structs",
+			data: map[string]any{"lines": []any{map[string]any{"zip": "1"}}},
+			want: Metadata{
+				Keys:  []string{"lines", "lines[0].zip"},
+				Unset: []string{"address", "host", "id", "lines[0].city", "port"},
+			},
+		},
+		{
+			name: "fields outside the selected groups",
+			data: map[string]any{"cost": 1},
+			want: Metadata{
+				Unused: []string{"cost"},
+				Unset:  []string{"address", "host", "id", "lines", "port"},
+			},
+		},
+		{
+			name: "selected groups",
+			data: map[string]any{"cost": 1},
+			opts: []CallOption{WithGroups("admin")},
+			want: Metadata{
+				Keys:  []string{"cost"},
+				Unset: []string{"address", "host", "id", "lines", "port"},
+			},
+		},
+		{
+			name: "embedded struct as nested map",
+			data: map[string]any{"Base": map[string]any{"id": "x", "other": 1}, "id": "y"},
+			want: Metadata{
+				Keys:   []string{"Base", "id"},
+				Unused: []string{"id", "other"},
+				Unset:  []string{"address", "host", "lines", "port"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Server
+			metadata, err := UnmarshalWithMetadata(tt.data, &got, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, metadata)
+		})
+	}
+}
+
+func TestUnmarshaler_UnmarshalWithMetadataDetails(t *testing.T) {
+	t.Run("remainder keys used", func(t *testing.T) {
+		type Event struct {
+			Name  string         `schema:"name"`
+			Extra map[string]any `schema:",remain"`
+		}
+
+		var got Event
+		metadata, err := UnmarshalWithMetadata(map[string]any{"name": "a", "x": 1}, &got)
+		require.NoError(t, err)
+		assert.Equal(t, Metadata{Keys: []string{"name", "x"}}, metadata)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		type Config struct {
+			Port int `schema:"port"`
+		}
+
+		var got Config
+		_, err := UnmarshalWithMetadata(map[string]any{"port": "http"}, &got)
+		assert.ErrorContains(t, err, "port")
+	})
+
+	t.Run("skipped elements", func(t *testing.T) {
+		type Feed struct {
+			Counts []int `schema:"counts,skipinvalid"`
+		}
+
+		var got Feed
+		metadata, err := UnmarshalWithMetadata(map[string]any{"counts": []any{1, "x", 3}}, &got)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, got.Counts)
+		assert.Equal(t, []string{"counts"}, metadata.Keys)
+		require.Len(t, metadata.Skipped, 1)
+		assert.Equal(t, "counts[1]", metadata.Skipped[0].FieldPath)
+		assert.Equal(t, 1, metadata.Skipped[0].Index)
+		assert.Error(t, metadata.Skipped[0].Err)
+	})
+}
@@ -0,0 +1,102 @@
+package mapstructure
+
+import "reflect"
+
+// Decode transforms data into a new value of type T, without declaring a variable to
+// pass a pointer to: user, err := Decode[User](data). This is a convenience function
+// that uses the shared default unmarshaler and its caches.
+// opts configure this call only, e.g. WithGroups("admin").
+func Decode[T any](data map[string]any, opts ...CallOption) (T, error) {
+	var result T
+	err := defaultUnmarshaler.Unmarshal(data, &result, opts...)
+
+	return result, err
+}
+
+// DecodeInto decodes data into the value result points to, like Unmarshal with the target
+// type checked at compile time. result is not reset first, so fields missing from data
+// keep their values, e.g. when layering configuration sources. This is a convenience
+// function that uses the shared default unmarshaler and its caches.
+// opts configure this call only, e.g. WithGroups("admin").
+func DecodeInto[T any](data map[string]any, result *T, opts ...CallOption) error {
+	return defaultUnmarshaler.Unmarshal(data, result, opts...)
+}
+
+// Decoder decodes maps into values of type T with an Unmarshaler. The type is resolved
+// once when the decoder is created, so request handlers bound to one payload type
+// skip the per-call type lookups. A Decoder is safe for concurrent use.
+type Decoder[T any] struct {
+	u        *Unmarshaler
+	metadata *StructMetadata // Metadata of T, nil when T is not decoded as a plain struct
+	err      error           // UnsupportedTargetError returned by every call, see Err
+}
+
+// NewDecoder returns a Decoder for T using u, or the shared default unmarshaler when u is nil.
+// T is validated once, as Unmarshal validates its result; when T cannot be decoded into,
+// such as a func or chan type, Err reports the UnsupportedTargetError every call returns.
+func NewDecoder[T any](u *Unmarshaler) *Decoder[T] {
+	if u == nil {
+		u = defaultUnmarshaler
+	}
+
+	d := &Decoder[T]{u: u}
+
+	typ := reflect.TypeFor[T]()
+	if d.err = u.validateTarget(typ); d.err != nil {
+		return d
+	}
+	if typ.Kind() == reflect.Struct && !u.hasConverter(typ) {
+		d.metadata = u.fieldCache.GetMetadata(typ)
+	}
+
+	return d
+}
+
+// Err returns the UnsupportedTargetError of a T that cannot be decoded into, or nil.
+// Every call of the decoder fails with this error.
+func (d *Decoder[T]) Err() error {
+	return d.err
+}
+
+// Decode transforms data into a new value of type T.
+// opts configure this call only, e.g. WithGroups("admin").
+func (d *Decoder[T]) Decode(data map[string]any, opts ...CallOption) (T, error) {
+	var result T
+	err := d.DecodeTo(&result, data, opts...)
+
+	return result, err
+}
+
+// DecodeBatch decodes every item into a new value of type T, isolating failures: a bad
+// item does not stop the others. It returns the decoded values and, when any item failed,
+// errors aligned with items (nil for items that decoded); errs is nil when all succeeded.
+// The value of a failed item is what was decoded before the error. Every item is decoded
+// as a separate call configured with opts.
+func (d *Decoder[T]) DecodeBatch(items []map[string]any, opts ...CallOption) (results []T, errs []error) {
+	results = make([]T, len(items))
+	for i, item := range items {
+		var err error
+		if results[i], err = d.Decode(item, opts...); err != nil {
+			if errs == nil {
+				errs = make([]error, len(items))
+			}
+			errs[i] = err
+		}
+	}
+
+	return results, errs
+}
+
+// hasConverter reports whether a converter is registered for typ, taking precedence
+// over decoding it as a struct.
+func (u *Unmarshaler) hasConverter(typ reflect.Type) bool {
+	if _, ok := u.converters.FindDelegating(typ); ok {
+		return true
+	}
+	if _, ok := u.converters.FindField(typ); ok {
+		return true
+	}
+	_, ok := u.converters.Find(typ)
+
+	return ok
+}
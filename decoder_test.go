@@ -0,0 +1,218 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	type Request struct {
+		Name  string `schema:"name"`
+		Limit int    `schema:"limit" default:"10"`
+		Admin bool   `schema:"admin,groups=admin"`
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		got, err := Decode[Request](map[string]any{"name": "list", "admin": true})
+		require.NoError(t, err)
+		assert.Equal(t, Request{Name: "list", Limit: 10}, got)
+	})
+
+	t.Run("call options", func(t *testing.T) {
+		got, err := Decode[Request](map[string]any{"admin": true}, WithGroups("admin"))
+		require.NoError(t, err)
+		assert.True(t, got.Admin)
+	})
+
+	t.Run("pointers and maps", func(t *testing.T) {
+		ptr, err := Decode[*Request](map[string]any{"name": "a"})
+		require.NoError(t, err)
+		assert.Equal(t, &Request{Name: "a", Limit: 10}, ptr)
+
+		m, err := Decode[map[string]any](map[string]any{"a": "1"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": "1"}, m)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		got, err := Decode[Request](map[string]any{"limit": "many"})
+		require.ErrorAs(t, err, new(*ConversionError))
+		assert.Equal(t, "", got.Name)
+
+		_, err = Decode[func()](map[string]any{})
+		require.ErrorAs(t, err, new(*UnsupportedTargetError))
+	})
+}
+
+func TestDecodeInto(t *testing.T) {
+	type Config struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port" default:"80"`
+	}
+
+	t.Run("merged into existing values", func(t *testing.T) {
+		got := Config{Host: "localhost", Port: 8080}
+		require.NoError(t, DecodeInto(map[string]any{"port": 9090}, &got))
+		assert.Equal(t, Config{Host: "localhost", Port: 9090}, got)
+	})
+
+	t.Run("nil pointer", func(t *testing.T) {
+		require.ErrorAs(t, DecodeInto[Config](map[string]any{}, nil), new(*ValidationError))
+	})
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	type Request struct {
+		Name  string `schema:"name"`
+		Limit int    `schema:"limit" default:"10"`
+		Admin bool   `schema:"admin,groups=admin"`
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		d := NewDecoder[Request](nil)
+
+		got, err := d.Decode(map[string]any{"name": "list"})
+		require.NoError(t, err)
+		assert.Equal(t, Request{Name: "list", Limit: 10}, got)
+	})
+
+	t.Run("call options", func(t *testing.T) {
+		d := NewDecoder[Request](NewDefaultUnmarshaler())
+
+		got, err := d.Decode(map[string]any{"admin": true}, WithGroups("admin"))
+		require.NoError(t, err)
+		assert.True(t, got.Admin)
+	})
+
+	t.Run("nil data", func(t *testing.T) {
+		got, err := NewDecoder[Request](nil).Decode(nil)
+		require.NoError(t, err)
+		assert.Equal(t, Request{Limit: 10}, got)
+	})
+
+	t.Run("conversion error", func(t *testing.T) {
+		_, err := NewDecoder[Request](nil).Decode(map[string]any{"limit": "many"})
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "limit", convErr.FieldPath)
+	})
+
+	t.Run("validators", func(t *testing.T) {
+		u := NewDefaultUnmarshaler().WithValidators(map[reflect.Type]Validator{
+			reflect.TypeFor[Request](): ValidatorFor(func(r *Request) error {
+				if r.Name == "" {
+					return errors.New("name required")
+				}
+
+				return nil
+			}),
+		})
+
+		_, err := NewDecoder[Request](u).Decode(map[string]any{})
+
+		var valErr *StructValidationError
+		require.ErrorAs(t, err, &valErr)
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		got, err := NewDecoder[*Request](nil).Decode(map[string]any{"name": "ptr"})
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "ptr", got.Name)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		got, err := NewDecoder[map[string]any](nil).Decode(map[string]any{"a": 1})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": 1}, got)
+	})
+
+	t.Run("converter takes precedence", func(t *testing.T) {
+		type Token struct {
+			Value string
+		}
+
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry(map[reflect.Type]Converter{
+			reflect.TypeFor[Token](): func(any) (reflect.Value, error) {
+				return reflect.ValueOf(Token{Value: "converted"}), nil
+			},
+		}))
+
+		got, err := NewDecoder[Token](u).Decode(map[string]any{"Value": "raw"})
+		require.NoError(t, err)
+		assert.Equal(t, "converted", got.Value)
+
+		var viaUnmarshal Token
+		require.NoError(t, u.Unmarshal(map[string]any{"Value": "raw"}, &viaUnmarshal))
+		assert.Equal(t, viaUnmarshal, got)
+	})
+
+	t.Run("unsupported target", func(t *testing.T) {
+		d := NewDecoder[func()](nil)
+
+		var targetErr *UnsupportedTargetError
+		require.ErrorAs(t, d.Err(), &targetErr)
+
+		_, err := d.Decode(map[string]any{})
+		require.ErrorAs(t, err, &targetErr)
+
+		var viaUnmarshal func()
+		assert.Equal(t, Unmarshal(map[string]any{}, &viaUnmarshal), err)
+
+		require.NoError(t, NewDecoder[*struct{}](nil).Err())
+	})
+}
+
+func TestDecoder_DecodeBatch(t *testing.T) {
+	type Record struct {
+		ID   int    `schema:"id"`
+		Name string `schema:"name" default:"unnamed"`
+	}
+
+	decoder := NewDecoder[Record](nil)
+
+	t.Run("all items decode", func(t *testing.T) {
+		results, errs := decoder.DecodeBatch([]map[string]any{{"id": 1}, {"id": "2", "name": "b"}})
+		assert.Nil(t, errs)
+		assert.Equal(t, []Record{{ID: 1, Name: "unnamed"}, {ID: 2, Name: "b"}}, results)
+	})
+
+	t.Run("failures are isolated and index-aligned", func(t *testing.T) {
+		items := []map[string]any{{"id": 1}, {"id": "bad"}, {"id": 3}, {"id": []any{}}}
+
+		results, errs := decoder.DecodeBatch(items)
+		require.Len(t, errs, len(items))
+		require.Len(t, results, len(items))
+
+		assert.NoError(t, errs[0])
+		assert.NoError(t, errs[2])
+		var convErr *ConversionError
+		require.ErrorAs(t, errs[1], &convErr)
+		assert.Equal(t, "id", convErr.FieldPath)
+		assert.Error(t, errs[3])
+
+		assert.Equal(t, Record{ID: 1, Name: "unnamed"}, results[0])
+		assert.Equal(t, Record{ID: 3, Name: "unnamed"}, results[2])
+	})
+
+	t.Run("options apply to every item", func(t *testing.T) {
+		type Versioned struct {
+			Email string `schema:"email,since=2"`
+		}
+
+		results, errs := NewDecoder[Versioned](nil).DecodeBatch([]map[string]any{{"email": "a"}, {"email": "b"}}, WithVersion(1))
+		assert.Nil(t, errs)
+		assert.Equal(t, []Versioned{{}, {}}, results)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		results, errs := decoder.DecodeBatch(nil)
+		assert.Empty(t, results)
+		assert.Nil(t, errs)
+	})
+}
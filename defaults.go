@@ -0,0 +1,142 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// DefaultFunc derives default values of a struct from its other fields.
+// It receives a pointer to the struct after all of its fields were decoded and
+// should only fill fields that are still zero.
+type DefaultFunc func(structPtr any) error
+
+// defaultTemplate is a parsed template default such as `default:"{{.Host}}:5432"`.
+type defaultTemplate struct {
+	tmpl *template.Template
+	err  error // Parse error, reported when the default is used
+}
+
+// parseDefaultTemplate parses a default value referencing sibling fields.
+// It returns nil for plain defaults that contain no template action.
+func parseDefaultTemplate(fieldName, value string) *defaultTemplate {
+	if !strings.Contains(value, "{{") {
+		return nil
+	}
+
+	tmpl, err := template.New(fieldName).Option("missingkey=error").Parse(value)
+
+	return &defaultTemplate{tmpl: tmpl, err: err}
+}
+
+// parseEmbeddedDefault parses the default of an embedded struct field, a JSON object
+// holding default values of the promoted fields by key, e.g. `default:"{\"port\": 80}"`.
+func parseEmbeddedDefault(value string) (map[string]any, error) {
+	var values map[string]any
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil, fmt.Errorf("embedded struct default must be a JSON object: %w", err)
+	}
+
+	return values, nil
+}
+
+// execute renders the template with the decoded struct value as dot.
+func (d *defaultTemplate) execute(structValue reflect.Value) (string, error) {
+	if d.err != nil {
+		return "", d.err
+	}
+
+	var b strings.Builder
+	if err := d.tmpl.Execute(&b, structValue.Interface()); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// WithDefaultFuncs returns a new unmarshaler extending u with the given default functions,
+// keyed by the struct type whose defaults they derive. A default function runs after the
+// struct's fields, including template defaults, are decoded.
+// Functions replace any previously registered for the same type. u is left unchanged.
+func (u *Unmarshaler) WithDefaultFuncs(funcs map[reflect.Type]DefaultFunc) *Unmarshaler {
+	merged := maps.Clone(u.defaultFuncs)
+	if merged == nil {
+		merged = make(map[reflect.Type]DefaultFunc, len(funcs))
+	}
+	maps.Copy(merged, funcs)
+
+	configured := *u
+	configured.defaultFuncs = merged
+
+	return &configured
+}
+
+// WithoutDefaults makes the unmarshaler ignore default tags, including template defaults
+// and the defaults of embedded structs, and skip registered default funcs, so decoders of
+// external payloads never invent values from tags meant for internal configuration
+// structs sharing the same types. Fields missing from the input keep their values.
+func WithoutDefaults() Option {
+	return func(u *Unmarshaler) {
+		u.noDefaults = true
+	}
+}
+
+// hasDefault reports whether the unmarshaler gives field a value when its key is
+// missing from the input.
+func (u *Unmarshaler) hasDefault(field *FieldMetadata) bool {
+	return !u.noDefaults && (field.Default != nil || field.embeddedDefault != nil)
+}
+
+// applyTemplateDefaults decodes template defaults of fields missing from the input,
+// in field order, so later templates can reference earlier derived values.
+// Templates of promoted fields are executed with their embedded struct as dot, or the
+// outer struct when the embedded struct is unexported.
+func (u *Unmarshaler) applyTemplateDefaults(rv reflect.Value, metadata *StructMetadata, fields []*FieldMetadata, fieldPath string) error {
+	for _, field := range fields {
+		fullPath := buildFieldPath(fieldPath, field.MapKey)
+
+		dot := rv
+		if field.owner != 0 {
+			if embedded := fieldByIndex(rv, metadata.flat[field.owner-1].Index); embedded.CanInterface() {
+				dot = embedded
+			}
+		}
+
+		value, err := field.defaultTemplate.execute(dot)
+		if err != nil {
+			return NewConversionError(fullPath, *field.Default, field.Type, fmt.Errorf("default template: %w", err))
+		}
+
+		fieldValue := fieldByIndex(rv, field.Index)
+		if err := u.unmarshalValue(value, fieldValue, fullPath, field); err != nil {
+			return fmt.Errorf("%s: %w", fullPath, err)
+		}
+		u.countField(true)
+
+		if err := u.normalizeStrings(fieldValue, fullPath, field); err != nil {
+			return err
+		}
+		if err := checkLength(fieldValue, fullPath, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyDefaultFunc runs the default function registered for the struct type of rv, if any.
+func (u *Unmarshaler) applyDefaultFunc(rv reflect.Value, fieldPath string) error {
+	fn, ok := u.defaultFuncs[rv.Type()]
+	if !ok || !rv.CanAddr() || u.noDefaults {
+		return nil
+	}
+
+	if err := fn(rv.Addr().Interface()); err != nil {
+		return NewConversionError(fieldPath, nil, rv.Type(), fmt.Errorf("default func: %w", err))
+	}
+
+	return nil
+}
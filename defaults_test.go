@@ -0,0 +1,300 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_TemplateDefaults(t *testing.T) {
+	type Database struct {
+		Host    string `schema:"host" default:"localhost"`
+		Port    int    `schema:"port" default:"5432"`
+		Name    string `schema:"name"`
+		Address string `schema:"address" default:"{{.Host}}:{{.Port}}"`
+		DSN     string `schema:"dsn" default:"postgres://{{.Address}}/{{.Name}}"`
+		Pool    int    `schema:"pool" default:"{{.Port}}"`
+	}
+
+	t.Run("derived from decoded and defaulted fields", func(t *testing.T) {
+		var result Database
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"host": "db", "name": "app"}, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, "db:5432", result.Address)
+		assert.Equal(t, "postgres://db:5432/app", result.DSN, "later templates see earlier derived values")
+		assert.Equal(t, 5432, result.Pool, "rendered default is converted to the field type")
+	})
+
+	t.Run("input value wins over template", func(t *testing.T) {
+		var result Database
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"address": "remote:1"}, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, "remote:1", result.Address)
+		assert.Equal(t, "postgres://remote:1/", result.DSN)
+	})
+
+	t.Run("nested struct path", func(t *testing.T) {
+		type Config struct {
+			DB Database `schema:"db"`
+		}
+
+		var result Config
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"db": map[string]any{"port": 6432}}, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, "localhost:6432", result.DB.Address)
+	})
+
+	t.Run("promoted from an unexported embedded struct", func(t *testing.T) {
+		type endpoint struct {
+			Host    string `schema:"host" default:"localhost"`
+			Address string `schema:"address" default:"{{.Host}}:80"`
+		}
+		type Service struct {
+			endpoint
+		}
+
+		var result Service
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(map[string]any{"host": "web"}, &result))
+		assert.Equal(t, "web:80", result.Address)
+	})
+
+	t.Run("normalized like input", func(t *testing.T) {
+		type Host struct {
+			Name string `schema:"name" default:" Web "`
+			FQDN string `schema:"fqdn,trim,lower" default:" {{.Name}}.Example.COM "`
+		}
+
+		var result Host
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(map[string]any{}, &result))
+		assert.Equal(t, "web .example.com", result.FQDN)
+
+		var fromInput Host
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(map[string]any{"fqdn": " Web .Example.COM "}, &fromInput))
+		assert.Equal(t, fromInput.FQDN, result.FQDN)
+	})
+
+	t.Run("template errors", func(t *testing.T) {
+		type Broken struct {
+			Missing string `schema:"missing" default:"{{.Nope}}"`
+			Syntax  string `schema:"syntax" default:"{{.Host"`
+		}
+
+		var result Broken
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"syntax": "ok"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "missing", convErr.FieldPath)
+		assert.Contains(t, err.Error(), "default template")
+
+		err = NewDefaultUnmarshaler().Unmarshal(map[string]any{"missing": "ok"}, &result)
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "syntax", convErr.FieldPath)
+	})
+}
+
+func TestUnmarshaler_WithDefaultFuncs(t *testing.T) {
+	type Server struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port"`
+		URL  string `schema:"url"`
+	}
+
+	type Config struct {
+		Servers []Server `schema:"servers"`
+	}
+
+	base := NewDefaultUnmarshaler()
+	u := base.WithDefaultFuncs(map[reflect.Type]DefaultFunc{
+		reflect.TypeOf(Server{}): func(ptr any) error {
+			//nolint:forcetypeassert // Test code
+			s := ptr.(*Server)
+			if s.Port == 0 {
+				return errors.New("port required to derive url")
+			}
+			if s.URL == "" {
+				s.URL = fmt.Sprintf("http://%s:%d", s.Host, s.Port)
+			}
+
+			return nil
+		},
+	})
+
+	data := map[string]any{
+		"servers": []any{
+			map[string]any{"host": "a", "port": 80},
+			map[string]any{"host": "b", "port": 81, "url": "https://b"},
+		},
+	}
+
+	var result Config
+	err := u.Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, "http://a:80", result.Servers[0].URL)
+	assert.Equal(t, "https://b", result.Servers[1].URL)
+
+	t.Run("error reports struct path", func(t *testing.T) {
+		var result Config
+		err := u.Unmarshal(map[string]any{"servers": []any{map[string]any{"host": "a"}}}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "servers[0]", convErr.FieldPath)
+		assert.Contains(t, err.Error(), "port required")
+	})
+
+	t.Run("receiver is unchanged", func(t *testing.T) {
+		var result Server
+		require.NoError(t, base.Unmarshal(map[string]any{"host": "a"}, &result))
+		assert.Empty(t, result.URL)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_EmbeddedDefaults(t *testing.T) {
+	type Server struct {
+		Host string `schema:"host" default:"localhost"`
+		Port int    `schema:"port" default:"8080"`
+		TLS  bool   `schema:"tls"`
+	}
+	type Inner struct {
+		Server `default:"{\"port\": 9000, \"tls\": true}"`
+	}
+	type App struct {
+		Inner `default:"{\"port\": 443}"`
+		Name  string `schema:"name"`
+	}
+	type Plain struct {
+		Server
+		Name string `schema:"name"`
+	}
+
+	tests := []struct {
+		name   string
+		data   map[string]any
+		target any
+		want   any
+	}{
+		{
+			name:   "defaults of promoted fields",
+			data:   map[string]any{"name": "api"},
+			target: &Plain{},
+			want:   &Plain{Server: Server{Host: "localhost", Port: 8080}, Name: "api"},
+		},
+		{
+			name:   "embedded default overrides field defaults",
+			data:   map[string]any{},
+			target: &Inner{},
+			want:   &Inner{Server: Server{Host: "localhost", Port: 9000, TLS: true}},
+		},
+		{
+			name:   "outer embedded default wins",
+			data:   map[string]any{"name": "api"},
+			target: &App{},
+			want:   &App{Inner: Inner{Server: Server{Host: "localhost", Port: 443, TLS: true}}, Name: "api"},
+		},
+		{
+			name:   "input wins over embedded default",
+			data:   map[string]any{"port": 80, "tls": false},
+			target: &App{},
+			want:   &App{Inner: Inner{Server: Server{Host: "localhost", Port: 80}}},
+		},
+		{
+			name:   "nested map ignores embedded default",
+			data:   map[string]any{"Server": map[string]any{"host": "example.com"}},
+			target: &Inner{},
+			want:   &Inner{Server: Server{Host: "example.com", Port: 8080}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, strategy := range []IterationStrategy{IterateFields, IterateKeys} {
+				target := reflect.New(reflect.TypeOf(tt.target).Elem()).Interface()
+				require.NoError(t, NewDefaultUnmarshaler(WithIterationStrategy(strategy)).Unmarshal(tt.data, target))
+				assert.Equal(t, tt.want, target)
+			}
+		})
+	}
+
+	t.Run("invalid embedded default", func(t *testing.T) {
+		type Bad struct {
+			Server `default:"port=80"`
+		}
+
+		var result Bad
+		err := Unmarshal(map[string]any{}, &result)
+
+		var tagErr *TagError
+		require.ErrorAs(t, err, &tagErr)
+		assert.Equal(t, "Server", tagErr.Field)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_WithoutDefaults(t *testing.T) {
+	type Limits struct {
+		Max int `schema:"max" default:"100"`
+	}
+	type Account struct {
+		Limits `default:"{\"max\": 5}"`
+		Role   string `schema:"role" default:"admin"`
+		Host   string `schema:"host"`
+		URL    string `schema:"url" default:"https://{{.Host}}"`
+		Quota  int    `schema:"quota"`
+	}
+
+	u := NewDefaultUnmarshaler(WithoutDefaults()).WithDefaultFuncs(map[reflect.Type]DefaultFunc{
+		reflect.TypeFor[Account](): func(ptr any) error {
+			ptr.(*Account).Quota = 10
+
+			return nil
+		},
+	})
+
+	t.Run("no defaults applied", func(t *testing.T) {
+		var got Account
+		require.NoError(t, u.Unmarshal(map[string]any{"host": "a"}, &got))
+		assert.Equal(t, Account{Host: "a"}, got)
+	})
+
+	t.Run("input still decoded", func(t *testing.T) {
+		var got Account
+		require.NoError(t, u.Unmarshal(map[string]any{"role": "user", "max": "7", "url": "u"}, &got))
+		assert.Equal(t, Account{Limits: Limits{Max: 7}, Role: "user", URL: "u"}, got)
+	})
+
+	t.Run("missing fields keep their values", func(t *testing.T) {
+		got := Account{Role: "owner"}
+		require.NoError(t, u.Unmarshal(map[string]any{}, &got))
+		assert.Equal(t, "owner", got.Role)
+	})
+
+	t.Run("string maps", func(t *testing.T) {
+		type Flat struct {
+			Role string `schema:"role" default:"admin"`
+		}
+
+		var got Flat
+		require.NoError(t, u.UnmarshalStrings(map[string]string{}, &got))
+		assert.Empty(t, got.Role)
+	})
+
+	t.Run("fields with defaults unset", func(t *testing.T) {
+		var got Account
+		err := u.With(WithErrorUnset()).Unmarshal(map[string]any{"host": "a", "url": "u", "quota": 1, "max": 1}, &got)
+		assert.EqualError(t, err, `role: missing key "role"`)
+	})
+
+	t.Run("shared types decode with defaults elsewhere", func(t *testing.T) {
+		var got Account
+		require.NoError(t, Unmarshal(map[string]any{"host": "a"}, &got))
+		assert.Equal(t, Account{Limits: Limits{Max: 5}, Role: "admin", Host: "a", URL: "https://a"}, got)
+	})
+}
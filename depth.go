@@ -0,0 +1,40 @@
+package mapstructure
+
+import "fmt"
+
+// OptionMaxDepth is the Constraint of the *ConstraintError reported when input nests
+// deeper than the limit set with WithMaxDepth.
+const OptionMaxDepth = "maxdepth"
+
+// WithMaxDepth limits how deeply the input may nest: every struct decoded from a map
+// and every slice decoded element by element counts as one level. Input nesting deeper
+// fails with a *ConstraintError instead of recursing further, which bounds the work
+// spent on untrusted input decoded into self-referential types such as trees.
+// A limit of 0, the default, disables the check.
+func WithMaxDepth(depth int) Option {
+	return func(u *Unmarshaler) {
+		u.maxDepth = max(depth, 0)
+	}
+}
+
+// enter records the start of a nested struct or slice at fieldPath and fails when it
+// exceeds the depth limit. Every successful enter must be paired with leave.
+func (u *Unmarshaler) enter(fieldPath string) error {
+	if u.maxDepth == 0 || u.state == nil {
+		return nil
+	}
+
+	if u.state.depth >= u.maxDepth {
+		return NewConstraintError(fieldPath, OptionMaxDepth, fmt.Sprintf("input nests deeper than the maximum depth %d", u.maxDepth))
+	}
+	u.state.depth++
+
+	return nil
+}
+
+// leave records the end of a nested struct or slice started with enter.
+func (u *Unmarshaler) leave() {
+	if u.maxDepth != 0 && u.state != nil {
+		u.state.depth--
+	}
+}
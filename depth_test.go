@@ -0,0 +1,131 @@
+package mapstructure
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type treeNode struct {
+	Name     string      `schema:"name"`
+	Children []*treeNode `schema:"children"`
+	Parent   *treeNode   `schema:"-"`
+	Next     *treeNode   `schema:"next"`
+}
+
+// nestedTree returns the input of a chain of levels nodes linked through "children".
+func nestedTree(levels int) map[string]any {
+	node := map[string]any{"name": "leaf"}
+	for range levels - 1 {
+		node = map[string]any{"name": "node", "children": []any{node}}
+	}
+
+	return node
+}
+
+func TestUnmarshaler_Unmarshal_SelfReferential(t *testing.T) {
+	t.Run("deep nesting", func(t *testing.T) {
+		var root treeNode
+		require.NoError(t, Unmarshal(nestedTree(500), &root))
+
+		depth := 1
+		for node := &root; len(node.Children) > 0; node = node.Children[0] {
+			depth++
+		}
+		assert.Equal(t, 500, depth)
+	})
+
+	t.Run("pointer chain", func(t *testing.T) {
+		data := map[string]any{"name": "a", "next": map[string]any{"name": "b", "next": map[string]any{"name": "c"}}}
+
+		var root treeNode
+		require.NoError(t, Unmarshal(data, &root))
+		assert.Equal(t, "c", root.Next.Next.Name)
+		assert.Nil(t, root.Next.Next.Next)
+	})
+
+	t.Run("metadata of self-referencing types", func(t *testing.T) {
+		cache := NewDefaultStructMetadataCache()
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				metadata := cache.GetMetadata(reflect.TypeFor[treeNode]())
+				assert.True(t, metadata.HasKey("children"))
+			})
+		}
+		wg.Wait()
+
+		assert.Empty(t, NewDefaultUnmarshaler().UnsupportedFields(reflect.TypeFor[treeNode]()))
+	})
+
+	t.Run("marshal round trip", func(t *testing.T) {
+		data := nestedTree(5)
+
+		_, err := RoundTrip[treeNode](nil, nil, data)
+		require.NoError(t, err)
+	})
+
+	t.Run("diff stops at cycles", func(t *testing.T) {
+		root := &treeNode{Name: "root"}
+		root.Next = &treeNode{Name: "child", Next: root}
+
+		changes, err := Diff(root, map[string]any{"name": "renamed"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []FieldChange{{Path: "name", Key: "name", Old: "root", New: "renamed"}}, changes)
+	})
+}
+
+func TestUnmarshaler_WithMaxDepth(t *testing.T) {
+	// A chain of levels nodes nests 2*levels-1 deep: a struct per node, a slice between nodes
+	tests := []struct {
+		name     string
+		maxDepth int
+		levels   int
+		wantPath string
+	}{
+		{name: "no limit", maxDepth: 0, levels: 100},
+		{name: "within limit", maxDepth: 9, levels: 5},
+		{name: "exceeds limit", maxDepth: 8, levels: 5, wantPath: "children[0].children[0].children[0].children[0]"},
+		{name: "slice exceeds limit", maxDepth: 7, levels: 5, wantPath: "children[0].children[0].children[0].children"},
+		{name: "negative disables", maxDepth: -1, levels: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewDefaultUnmarshaler(WithMaxDepth(tt.maxDepth))
+
+			var root treeNode
+			err := u.Unmarshal(nestedTree(tt.levels), &root)
+			if tt.wantPath == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var constraintErr *ConstraintError
+			require.ErrorAs(t, err, &constraintErr)
+			assert.Equal(t, OptionMaxDepth, constraintErr.Constraint)
+			assert.Equal(t, tt.wantPath, constraintErr.FieldPath)
+		})
+	}
+
+	t.Run("siblings do not accumulate", func(t *testing.T) {
+		data := map[string]any{"children": []any{nestedTree(1), nestedTree(1), nestedTree(1)}}
+
+		var root treeNode
+		require.NoError(t, NewDefaultUnmarshaler(WithMaxDepth(3)).Unmarshal(data, &root))
+		assert.Len(t, root.Children, 3)
+	})
+
+	t.Run("typed decoder", func(t *testing.T) {
+		_, err := NewDecoder[treeNode](NewDefaultUnmarshaler(WithMaxDepth(2))).Decode(nestedTree(3))
+
+		var constraintErr *ConstraintError
+		require.ErrorAs(t, err, &constraintErr)
+	})
+}
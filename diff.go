@@ -0,0 +1,115 @@
+package mapstructure
+
+import "reflect"
+
+// FieldChange describes a field whose value changes when a map is decoded into a struct.
+type FieldChange struct {
+	Path string // Path of the field, as in decoding errors (e.g. "db.port")
+	Key  string // Map key of the field
+	Old  any    // Value before decoding
+	New  any    // Value after decoding
+}
+
+// Diff reports the fields of current that would change if incoming were decoded into it.
+// This is a convenience function that uses a shared default unmarshaler.
+func Diff(current any, incoming map[string]any, opts ...CallOption) ([]FieldChange, error) {
+	return defaultUnmarshaler.Diff(current, incoming, opts...)
+}
+
+// Diff reports the fields of current, a struct or a pointer to one, that would change if
+// incoming were decoded into it. current is not modified.
+//
+// incoming is decoded into a deep copy of current with the same rules as Unmarshal,
+// so fields absent from incoming but carrying defaults are reported when their default
+// differs from the current value. Nested structs are compared field by field, other
+// values as a whole with reflect.DeepEqual. Changes are listed in field order.
+func (u *Unmarshaler) Diff(current any, incoming map[string]any, opts ...CallOption) ([]FieldChange, error) {
+	rv := reflect.ValueOf(current)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, NewValidationError("current must be a struct or a non-nil pointer to a struct")
+	}
+
+	updated := reflect.New(rv.Type())
+	updated.Elem().Set(deepCopy(rv, make(map[copyKey]reflect.Value)))
+	if err := u.Unmarshal(incoming, updated.Interface(), opts...); err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	u.diffStruct(rv, updated.Elem(), "", &changes, make(map[copyKey]struct{}))
+
+	return changes, nil
+}
+
+// diffStruct appends the changes between the structs old and updated to changes.
+// visiting holds the pointers of old being compared, to stop at cycles.
+func (u *Unmarshaler) diffStruct(old, updated reflect.Value, fieldPath string, changes *[]FieldChange, visiting map[copyKey]struct{}) {
+	metadata := u.fieldCache.GetMetadata(old.Type())
+
+	for i := range metadata.flat {
+		field := &metadata.flat[i]
+		if field.Embedded {
+			continue // Promoted fields follow in metadata.flat
+		}
+
+		oldValue, ok := fieldByIndexNoAlloc(old, field.Index)
+		newValue, newOK := fieldByIndexNoAlloc(updated, field.Index)
+		if !ok && !newOK {
+			continue
+		}
+		if !ok {
+			oldValue = reflect.Zero(field.Type)
+		}
+		if !newOK {
+			newValue = reflect.Zero(field.Type)
+		}
+
+		u.diffValue(oldValue, newValue, field.MapKey, buildFieldPath(fieldPath, field.MapKey), changes, visiting)
+	}
+}
+
+// diffValue appends the changes between old and updated, values of the field decoded
+// from key, to changes. Pointers already being compared are compared as a whole.
+func (u *Unmarshaler) diffValue(old, updated reflect.Value, key, fieldPath string, changes *[]FieldChange, visiting map[copyKey]struct{}) {
+	if u.diffable(old.Type()) {
+		if old.Kind() != reflect.Ptr {
+			u.diffStruct(old, updated, fieldPath, changes, visiting)
+
+			return
+		}
+
+		ptr := copyKey{ptr: old.Pointer(), typ: old.Type()}
+		if _, cycle := visiting[ptr]; !cycle && !old.IsNil() && !updated.IsNil() {
+			visiting[ptr] = struct{}{}
+			defer delete(visiting, ptr)
+			u.diffStruct(old.Elem(), updated.Elem(), fieldPath, changes, visiting)
+
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(old.Interface(), updated.Interface()) {
+		*changes = append(*changes, FieldChange{Path: fieldPath, Key: key, Old: old.Interface(), New: updated.Interface()})
+	}
+}
+
+// diffable reports whether values of typ are compared field by field: structs, and
+// pointers to structs, decoded from nested maps rather than by a converter.
+func (u *Unmarshaler) diffable(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Struct && !u.hasConverter(typ)
+}
+
+// fieldByIndexNoAlloc returns the nested field of the struct rv at the index path and
+// true, or false when the path goes through a nil embedded struct pointer.
+func fieldByIndexNoAlloc(rv reflect.Value, index []int) (reflect.Value, bool) {
+	v, err := rv.FieldByIndexErr(index)
+
+	return v, err == nil
+}
@@ -0,0 +1,108 @@
+package mapstructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Diff(t *testing.T) {
+	type Database struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port"`
+	}
+	type Meta struct {
+		Owner string `schema:"owner"`
+	}
+	type Config struct {
+		Meta
+		Name     string        `schema:"name"`
+		Timeout  time.Duration `schema:"timeout"`
+		Tags     []string      `schema:"tags"`
+		DB       Database      `schema:"db"`
+		Replica  *Database     `schema:"replica"`
+		Mode     string        `schema:"mode" default:"safe"`
+		Started  Date          `schema:"started"`
+		Disabled bool          `schema:"disabled"`
+	}
+
+	current := Config{
+		Meta:    Meta{Owner: "ops"},
+		Name:    "api",
+		Timeout: time.Second,
+		Tags:    []string{"a"},
+		DB:      Database{Host: "localhost", Port: 5432},
+		Replica: &Database{Host: "replica", Port: 5432},
+		Mode:    "safe",
+		Started: Date{Year: 2024, Month: time.January, Day: 1},
+	}
+
+	tests := []struct {
+		name     string
+		incoming map[string]any
+		want     []FieldChange
+	}{
+		{
+			name:     "no changes",
+			incoming: map[string]any{"name": "api", "db": map[string]any{"port": "5432"}, "tags": []any{"a"}},
+		},
+		{
+			name: "scalars and nested fields",
+			incoming: map[string]any{
+				"owner":   "dev",
+				"name":    "web",
+				"db":      map[string]any{"port": 6432},
+				"replica": map[string]any{"host": "other"},
+				"started": "2024-02-01",
+			},
+			want: []FieldChange{
+				{Path: "owner", Key: "owner", Old: "ops", New: "dev"},
+				{Path: "name", Key: "name", Old: "api", New: "web"},
+				{Path: "db.port", Key: "port", Old: 5432, New: 6432},
+				{Path: "replica.host", Key: "host", Old: "replica", New: "other"},
+				{Path: "started", Key: "started", Old: current.Started, New: Date{Year: 2024, Month: time.February, Day: 1}},
+			},
+		},
+		{
+			name:     "slices compared as a whole",
+			incoming: map[string]any{"tags": []any{"a", "b"}, "replica": nil},
+			want: []FieldChange{
+				{Path: "tags", Key: "tags", Old: []string{"a"}, New: []string{"a", "b"}},
+				{Path: "replica", Key: "replica", Old: current.Replica, New: (*Database)(nil)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff(&current, tt.incoming)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, changes)
+			assert.Equal(t, "localhost", current.DB.Host, "current is not modified")
+			assert.Equal(t, "replica", current.Replica.Host, "current is not modified")
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		changes, err := Diff(Config{Mode: "fast"}, map[string]any{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []FieldChange{{Path: "mode", Key: "mode", Old: "fast", New: "safe"}}, changes)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Diff(&current, map[string]any{"db": map[string]any{"port": "x"}})
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "db.port", convErr.FieldPath)
+
+		_, err = Diff(map[string]any{}, nil)
+
+		var valErr *ValidationError
+		require.ErrorAs(t, err, &valErr)
+	})
+}
@@ -0,0 +1,134 @@
+package mapstructure
+
+import (
+	"cmp"
+	"maps"
+	"reflect"
+)
+
+// OptionDiscriminator is the tag option of an interface field making marshaled maps of its
+// value name the implementation under the given key, e.g. `schema:"db,discriminator=driver"`,
+// so the map decodes back through the Factories registered for the interface with the same
+// key. Without a value the key is the one given to WithTypeNames, or else
+// DefaultDiscriminatorKey. The option applies to the elements of slices, arrays and
+// string-keyed maps of interfaces too, and is ignored when decoding.
+const OptionDiscriminator = "discriminator"
+
+// DefaultDiscriminatorKey is the key of the implementation name for discriminator options without a value.
+const DefaultDiscriminatorKey = "type"
+
+// TypeNamer is implemented by implementations of interfaces that name themselves in
+// marshaled maps, see OptionDiscriminator. Implementations without the method, nor a
+// name given to WithTypeNames, are named by their Go type name.
+type TypeNamer interface {
+	TypeName() string
+}
+
+// WithTypeNames makes the marshaler name the implementations held by interface fields
+// under key, or DefaultDiscriminatorKey when empty, with names by type, e.g.
+//
+//	WithTypeNames("driver", map[reflect.Type]string{
+//		reflect.TypeFor[Postgres](): "postgres",
+//		reflect.TypeFor[Memory]():   "memory",
+//	})
+//
+// Implementations are looked up by their dynamic type and, for pointers, by the type they
+// point to. Registered implementations are named in every interface field, and in slices,
+// arrays and maps of interfaces, without the discriminator tag option; the key of a field's
+// option takes precedence over key. Names given here take precedence over TypeName methods.
+func WithTypeNames(key string, names map[reflect.Type]string) MarshalOption {
+	return func(m *Marshaler) {
+		if key == "" {
+			key = DefaultDiscriminatorKey
+		}
+		m.typeKey = key
+		m.typeNames = maps.Clone(names)
+	}
+}
+
+// discriminate adds the implementation names of the interface values of field, held by
+// rv and marshaled to value, under the key of its discriminator option, or else under the
+// key given to WithTypeNames for registered implementations.
+func (m *Marshaler) discriminate(field *FieldMetadata, rv reflect.Value, value any) {
+	if key, ok := field.Option(OptionDiscriminator); ok {
+		if key == "" {
+			key = cmp.Or(m.typeKey, DefaultDiscriminatorKey)
+		}
+		m.addDiscriminator(rv, value, key, false)
+	} else if len(m.typeNames) > 0 {
+		m.addDiscriminator(rv, value, m.typeKey, true)
+	}
+}
+
+// addDiscriminator adds the implementation name of the interface value rv under key to
+// value, its marshaled map, and likewise for the elements of slices, arrays and maps of
+// interfaces. Maps already holding key and implementations that are not structs are left
+// as they are, as are implementations not given to WithTypeNames when registeredOnly.
+func (m *Marshaler) addDiscriminator(rv reflect.Value, value any, key string, registeredOnly bool) {
+	//nolint:exhaustive // Only containers of interfaces are named
+	switch rv.Kind() {
+	case reflect.Interface:
+		fields, ok := value.(map[string]any)
+		if !ok || rv.IsNil() {
+			return
+		}
+		if _, exists := fields[key]; exists {
+			return
+		}
+		if _, isStruct := embeddedStruct(rv.Elem().Type()); !isStruct {
+			return
+		}
+		if name, ok := m.typeName(rv.Elem(), registeredOnly); ok {
+			fields[key] = name
+		}
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			m.addDiscriminator(rv.Elem(), value, key, registeredOnly)
+		}
+	case reflect.Slice, reflect.Array:
+		elems, ok := value.([]any)
+		if !ok || len(elems) != rv.Len() {
+			return
+		}
+		for i := range elems {
+			m.addDiscriminator(rv.Index(i), elems[i], key, registeredOnly)
+		}
+	case reflect.Map:
+		entries, ok := value.(map[string]any)
+		if !ok || rv.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			m.addDiscriminator(iter.Value(), entries[k], key, registeredOnly)
+		}
+	}
+}
+
+// typeName returns the name of the implementation rv: the name given to WithTypeNames,
+// its TypeName, or else the name of its type, through pointers. It reports false for
+// implementations not given to WithTypeNames when registeredOnly.
+func (m *Marshaler) typeName(rv reflect.Value, registeredOnly bool) (string, bool) {
+	typ := rv.Type()
+	if name, ok := m.typeNames[typ]; ok {
+		return name, true
+	}
+	if typ.Kind() == reflect.Ptr {
+		if name, ok := m.typeNames[typ.Elem()]; ok {
+			return name, true
+		}
+	}
+	if registeredOnly {
+		return "", false
+	}
+
+	if namer, ok := rv.Interface().(TypeNamer); ok {
+		return namer.TypeName(), true
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ.Name(), true
+}
@@ -0,0 +1,115 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (*testPostgres) TypeName() string { return "postgres" }
+
+func (testMemory) TypeName() string { return "memory" }
+
+type testShape interface {
+	Area() float64
+}
+
+type testSquare struct {
+	Side float64 `schema:"side"`
+}
+
+func (s testSquare) Area() float64 { return s.Side * s.Side }
+
+type testLabel string
+
+func (testLabel) Area() float64 { return 0 }
+
+func TestMarshal_Discriminator(t *testing.T) {
+	type Canvas struct {
+		Main    testShape            `schema:"main,discriminator=kind"`
+		Shapes  []testShape          `schema:"shapes,discriminator"`
+		ByName  map[string]testShape `schema:"by_name,discriminator=kind"`
+		Plain   testShape            `schema:"plain"`
+		Label   testShape            `schema:"label,discriminator=kind"`
+		Missing testShape            `schema:"missing,discriminator=kind"`
+	}
+
+	got, err := Marshal(Canvas{
+		Main:   &testSquare{Side: 2},
+		Shapes: []testShape{testSquare{Side: 1}, nil},
+		ByName: map[string]testShape{"a": testSquare{Side: 3}},
+		Plain:  testSquare{Side: 4},
+		Label:  testLabel("x"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"main":    map[string]any{"kind": "testSquare", "side": 2.0},
+		"shapes":  []any{map[string]any{"type": "testSquare", "side": 1.0}, nil},
+		"by_name": map[string]any{"a": map[string]any{"kind": "testSquare", "side": 3.0}},
+		"plain":   map[string]any{"side": 4.0},
+		"label":   testLabel("x"),
+		"missing": nil,
+	}, got)
+}
+
+func TestMarshal_DiscriminatorRoundTrip(t *testing.T) {
+	type Config struct {
+		DB       testDriver   `schema:"db,discriminator=driver"`
+		Replicas []testDriver `schema:"replicas,discriminator=driver"`
+	}
+
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(),
+		NewDefaultConverterRegistry().WithFactories(newTestDriverFactories(t)))
+
+	config := Config{DB: &testPostgres{DSN: "postgres://db"}, Replicas: []testDriver{testMemory{}}}
+	got, err := Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"db":       map[string]any{"driver": "postgres", "dsn": "postgres://db"},
+		"replicas": []any{map[string]any{"driver": "memory"}},
+	}, got)
+
+	var decoded Config
+	require.NoError(t, u.Unmarshal(got, &decoded))
+	assert.Equal(t, config, decoded)
+}
+
+func TestMarshaler_WithTypeNames(t *testing.T) {
+	type Canvas struct {
+		Main   testShape   `schema:"main"`
+		Shapes []testShape `schema:"shapes"`
+		Tagged testShape   `schema:"tagged,discriminator"`
+		Keyed  testShape   `schema:"keyed,discriminator=shape"`
+		Driver testDriver  `schema:"driver"`
+	}
+
+	m := NewDefaultMarshaler(WithTypeNames("kind", map[reflect.Type]string{
+		reflect.TypeFor[testSquare](): "square",
+	}))
+
+	got, err := m.Marshal(Canvas{
+		Main:   &testSquare{Side: 1},
+		Shapes: []testShape{testSquare{Side: 2}},
+		Tagged: testSquare{Side: 3},
+		Keyed:  testSquare{Side: 4},
+		Driver: &testPostgres{DSN: "x"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"main":   map[string]any{"kind": "square", "side": 1.0},
+		"shapes": []any{map[string]any{"kind": "square", "side": 2.0}},
+		"tagged": map[string]any{"kind": "square", "side": 3.0},
+		"keyed":  map[string]any{"shape": "square", "side": 4.0},
+		"driver": map[string]any{"dsn": "x"}, // Not registered
+	}, got)
+
+	// Registered names take precedence over TypeName methods
+	m = NewDefaultMarshaler(WithTypeNames("", map[reflect.Type]string{reflect.TypeFor[*testPostgres](): "pg"}))
+	got, err = m.Marshal(struct {
+		Driver testDriver `schema:"driver"`
+	}{Driver: &testPostgres{DSN: "x"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"driver": map[string]any{"type": "pg", "dsn": "x"}}, got)
+}
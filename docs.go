@@ -0,0 +1,138 @@
+package mapstructure
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// FieldDocs holds doc comments of struct fields, by qualified type name (import path,
+// a dot and the type name, e.g. "example.com/app/config.Server") and Go field name.
+// It can be parsed from source with ParseFieldDocs, or generated ahead of time for
+// binaries that ship without their sources.
+type FieldDocs map[string]map[string]string
+
+// WithFieldDocs makes the cache fill FieldMetadata.Doc from docs, so generated schemas
+// and help output can describe fields. Types missing from docs get empty docs.
+func WithFieldDocs(docs FieldDocs) CacheOption {
+	return func(c *StructMetadataCache) {
+		c.docs = docs
+	}
+}
+
+// ParseFieldDocs parses the non-test Go files in dir, the package with import path
+// pkgPath, and returns the doc comments of the fields of its named struct types.
+// A field's doc is its doc comment or, lacking one, its line comment.
+func ParseFieldDocs(pkgPath, dir string) (FieldDocs, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read package directory: %w", err)
+	}
+
+	docs := make(FieldDocs)
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+
+		collectFieldDocs(file, pkgPath, docs)
+	}
+
+	return docs, nil
+}
+
+// collectFieldDocs adds the field docs of the struct types declared at the top level
+// of file to docs.
+func collectFieldDocs(file *ast.File, pkgPath string, docs FieldDocs) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			fields := make(map[string]string)
+			for _, field := range structType.Fields.List {
+				doc := strings.TrimSpace(field.Doc.Text())
+				if doc == "" {
+					doc = strings.TrimSpace(field.Comment.Text())
+				}
+				if doc == "" {
+					continue
+				}
+
+				for _, name := range fieldNames(field) {
+					fields[name] = doc
+				}
+			}
+
+			if len(fields) > 0 {
+				docs[pkgPath+"."+typeSpec.Name.Name] = fields
+			}
+		}
+	}
+}
+
+// fieldNames returns the Go names of the fields declared by field: its names, or the
+// type name for an embedded field.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, name := range field.Names {
+			names[i] = name.Name
+		}
+
+		return names
+	}
+
+	typ := field.Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.SelectorExpr:
+			return []string{t.Sel.Name}
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return []string{t.Name}
+		default:
+			return nil
+		}
+	}
+}
+
+// fieldDocs returns the docs of the fields of the struct type typ, nil if none.
+func (d FieldDocs) fieldDocs(typ reflect.Type) map[string]string {
+	if d == nil || typ.Name() == "" {
+		return nil
+	}
+
+	// Instances of generic types share the docs of the generic type
+	name, _, _ := strings.Cut(typ.Name(), "[")
+
+	return d[typ.PkgPath()+"."+name]
+}
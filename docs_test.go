@@ -0,0 +1,135 @@
+package mapstructure
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const docsSource = `package config
+
+import "time"
+
+// Server is not a field doc.
+type Server struct {
+	// Host is the address to bind.
+	Host string ` + "`schema:\"host\"`" + `
+	Port int // Listening port.
+
+	// Timeouts apply to both.
+	Read, Write time.Duration
+
+	*Base // Shared settings.
+	Undocumented string
+}
+
+type Base struct {
+	// Name identifies the server.
+	Name string
+}
+
+type Box[T any] struct {
+	Value T // Boxed value.
+}
+
+type ID string
+
+func helper() {
+	type Local struct {
+		// Ignored, declared in a function.
+		Field string
+	}
+}
+`
+
+func TestParseFieldDocs(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.go"), []byte(docsSource), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config_test.go"), []byte("package config\n\ntype Fixture struct {\n\t// Test only.\n\tA int\n}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not go"), 0o600))
+
+	docs, err := ParseFieldDocs("example.com/app/config", dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, FieldDocs{
+		"example.com/app/config.Server": {
+			"Host":  "Host is the address to bind.",
+			"Port":  "Listening port.",
+			"Read":  "Timeouts apply to both.",
+			"Write": "Timeouts apply to both.",
+			"Base":  "Shared settings.",
+		},
+		"example.com/app/config.Base": {"Name": "Name identifies the server."},
+		"example.com/app/config.Box":  {"Value": "Boxed value."},
+	}, docs)
+}
+
+func TestParseFieldDocs_Errors(t *testing.T) {
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := ParseFieldDocs("example.com/app", filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.go"), []byte("package bad\n\ntype T struct {"), 0o600))
+
+		_, err := ParseFieldDocs("example.com/bad", dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse bad.go")
+	})
+}
+
+type docsBase struct {
+	Name string `schema:"name"`
+}
+
+type docsTarget struct {
+	docsBase
+	Port  int    `schema:"port"`
+	Plain string `schema:"plain"`
+}
+
+type docsBox[T any] struct {
+	Value T `schema:"value"`
+}
+
+func TestStructMetadataCache_WithFieldDocs(t *testing.T) {
+	pkg := reflect.TypeFor[docsTarget]().PkgPath()
+	docs := FieldDocs{
+		pkg + ".docsTarget": {"Port": "Listening port."},
+		pkg + ".docsBase":   {"Name": "Server name."},
+		pkg + ".docsBox":    {"Value": "Boxed value."},
+	}
+	cache := NewStructMetadataCache(DefaultTagName, DefaultValueTagName, WithFieldDocs(docs))
+
+	t.Run("own and promoted fields", func(t *testing.T) {
+		port, ok := cache.ResolveField(reflect.TypeFor[docsTarget](), "port")
+		require.True(t, ok)
+		assert.Equal(t, "Listening port.", port.Doc)
+
+		name, ok := cache.ResolveField(reflect.TypeFor[docsTarget](), "name")
+		require.True(t, ok)
+		assert.Equal(t, "Server name.", name.Doc)
+
+		plain, ok := cache.ResolveField(reflect.TypeFor[docsTarget](), "plain")
+		require.True(t, ok)
+		assert.Empty(t, plain.Doc)
+	})
+
+	t.Run("generic instances", func(t *testing.T) {
+		value, ok := cache.ResolveField(reflect.TypeFor[docsBox[int]](), "value")
+		require.True(t, ok)
+		assert.Equal(t, "Boxed value.", value.Doc)
+	})
+
+	t.Run("no docs by default", func(t *testing.T) {
+		port, ok := NewDefaultStructMetadataCache().ResolveField(reflect.TypeFor[docsTarget](), "port")
+		require.True(t, ok)
+		assert.Empty(t, port.Doc)
+	})
+}
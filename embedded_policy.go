@@ -0,0 +1,92 @@
+package mapstructure
+
+import "maps"
+
+// EmbeddedPolicy selects how the unmarshaler combines the two ways input can give the fields
+// of an embedded struct: promoted keys in the map of the outer struct, and a nested map under
+// the Go name of the embedded struct, e.g. {"Timestamps": {"created_at": ...}}.
+type EmbeddedPolicy int
+
+const (
+	// EmbeddedNamedOnly decodes the embedded struct from the nested map when the input has
+	// one, ignoring the promoted keys of its fields. This is the default.
+	EmbeddedNamedOnly EmbeddedPolicy = iota
+
+	// EmbeddedNamedFirst decodes the embedded struct from the nested map combined with the
+	// promoted keys of its fields, the nested map taking precedence for keys given both ways.
+	EmbeddedNamedFirst
+
+	// EmbeddedPromotedFirst decodes the embedded struct from the nested map combined with the
+	// promoted keys of its fields, the promoted keys taking precedence for keys given both ways.
+	EmbeddedPromotedFirst
+)
+
+// WithEmbeddedPolicy sets how the unmarshaler combines promoted keys and nested maps given for
+// embedded structs, for payloads that mix both styles.
+func WithEmbeddedPolicy(policy EmbeddedPolicy) Option {
+	return func(u *Unmarshaler) {
+		u.embeddedPolicy = policy
+	}
+}
+
+// embeddedInput returns the input of the embedded field of metadata given as a nested map
+// in dataMap, combined with the promoted keys of its fields under the embedded policy,
+// and whether dataMap has a nested map for field. The nested map is not modified.
+func (u *Unmarshaler) embeddedInput(dataMap map[string]any, metadata *StructMetadata, field *FieldMetadata) (map[string]any, bool) {
+	nestedData, ok := nestedEmbed(dataMap, field)
+	if !ok || u.embeddedPolicy == EmbeddedNamedOnly {
+		return nestedData, ok
+	}
+
+	var combined map[string]any
+	for key, value := range dataMap {
+		promoted, ok := metadata.FieldByKey(key)
+		if !ok || !ownedBy(metadata, promoted, field) {
+			continue
+		}
+		if _, named := nestedData[key]; named && u.embeddedPolicy == EmbeddedNamedFirst {
+			continue
+		}
+
+		if combined == nil {
+			combined = maps.Clone(nestedData)
+		}
+		combined[key] = value
+	}
+
+	if combined == nil {
+		return nestedData, true
+	}
+
+	return combined, true
+}
+
+// promotedFrom reports whether field, which closedEmbeds closed, is promoted from an
+// embedded struct given as a nested map in dataMap that embeddedInput combines with the
+// promoted keys under the embedded policy.
+func (u *Unmarshaler) promotedFrom(dataMap map[string]any, metadata *StructMetadata, closed []bool, field *FieldMetadata) bool {
+	if u.embeddedPolicy == EmbeddedNamedOnly {
+		return false
+	}
+
+	for owner := field.owner; owner != 0; owner = metadata.flat[owner-1].owner {
+		embedded := &metadata.flat[owner-1]
+		if _, nested := nestedEmbed(dataMap, embedded); nested && isOpen(closed, embedded) && u.fieldSelected(embedded) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ownedBy reports whether field of metadata is promoted from the embedded field, directly
+// or through the structs embedded in it.
+func ownedBy(metadata *StructMetadata, field, embedded *FieldMetadata) bool {
+	for owner := field.owner; owner != 0; owner = metadata.flat[owner-1].owner {
+		if owner-1 == embedded.pos {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type policyAudit struct {
+	By string `schema:"by"`
+}
+
+type policyTimestamps struct {
+	policyAudit
+	CreatedAt string `schema:"created_at"`
+	UpdatedAt string `schema:"updated_at" default:"never"`
+}
+
+type PolicyTimestamps = policyTimestamps
+
+type policyUser struct {
+	PolicyTimestamps
+	Name string `schema:"name"`
+}
+
+func TestUnmarshaler_Unmarshal_EmbeddedPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy EmbeddedPolicy
+		data   map[string]any
+		want   policyUser
+	}{
+		{
+			name:   "named only ignores promoted keys",
+			policy: EmbeddedNamedOnly,
+			data:   map[string]any{"PolicyTimestamps": map[string]any{"created_at": "a"}, "updated_at": "b", "name": "n"},
+			want:   policyUser{PolicyTimestamps: policyTimestamps{CreatedAt: "a", UpdatedAt: "never"}, Name: "n"},
+		},
+		{
+			name:   "named first combines both",
+			policy: EmbeddedNamedFirst,
+			data:   map[string]any{"PolicyTimestamps": map[string]any{"created_at": "a"}, "updated_at": "b", "by": "c"},
+			want:   policyUser{PolicyTimestamps: policyTimestamps{policyAudit: policyAudit{By: "c"}, CreatedAt: "a", UpdatedAt: "b"}},
+		},
+		{
+			name:   "named first precedence",
+			policy: EmbeddedNamedFirst,
+			data:   map[string]any{"PolicyTimestamps": map[string]any{"created_at": "a"}, "created_at": "b"},
+			want:   policyUser{PolicyTimestamps: policyTimestamps{CreatedAt: "a", UpdatedAt: "never"}},
+		},
+		{
+			name:   "promoted first precedence",
+			policy: EmbeddedPromotedFirst,
+			data:   map[string]any{"PolicyTimestamps": map[string]any{"created_at": "a", "updated_at": "x"}, "created_at": "b"},
+			want:   policyUser{PolicyTimestamps: policyTimestamps{CreatedAt: "b", UpdatedAt: "x"}},
+		},
+		{
+			name:   "promoted keys alone",
+			policy: EmbeddedPromotedFirst,
+			data:   map[string]any{"created_at": "b", "name": "n"},
+			want:   policyUser{PolicyTimestamps: policyTimestamps{CreatedAt: "b", UpdatedAt: "never"}, Name: "n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got policyUser
+			require.NoError(t, NewDefaultUnmarshaler(WithEmbeddedPolicy(tt.policy)).Unmarshal(tt.data, &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_EmbeddedPolicyDetails(t *testing.T) {
+	u := NewDefaultUnmarshaler(WithEmbeddedPolicy(EmbeddedNamedFirst))
+
+	t.Run("input unchanged", func(t *testing.T) {
+		nested := map[string]any{"created_at": "a"}
+		var got policyUser
+		require.NoError(t, u.Unmarshal(map[string]any{"PolicyTimestamps": nested, "updated_at": "b"}, &got))
+		assert.Equal(t, map[string]any{"created_at": "a"}, nested)
+	})
+
+	t.Run("outer fields shadow promoted keys", func(t *testing.T) {
+		type Shadowing struct {
+			PolicyTimestamps
+			CreatedAt int `schema:"created_at"`
+		}
+
+		var got Shadowing
+		require.NoError(t, u.Unmarshal(map[string]any{"PolicyTimestamps": map[string]any{"by": "a"}, "created_at": 1}, &got))
+		assert.Equal(t, Shadowing{PolicyTimestamps: policyTimestamps{policyAudit: policyAudit{By: "a"}, UpdatedAt: "never"}, CreatedAt: 1}, got)
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		var got policyUser
+		metadata, err := u.UnmarshalWithMetadata(map[string]any{"PolicyTimestamps": map[string]any{}, "created_at": "b"}, &got)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PolicyTimestamps", "created_at"}, metadata.Keys)
+		assert.Empty(t, metadata.Unused)
+	})
+
+	t.Run("normalize", func(t *testing.T) {
+		got, err := u.Normalize(map[string]any{"PolicyTimestamps": map[string]any{"by": "a"}, "created_at": "b"}, reflect.TypeFor[policyUser]())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"by": "a", "created_at": "b"}, got)
+	})
+}
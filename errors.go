@@ -54,3 +54,179 @@ func (e *ValidationError) Error() string {
 func NewValidationError(message string) *ValidationError {
 	return &ValidationError{Message: message}
 }
+
+// ConstraintError represents a decoded value that violates a constraint declared
+// by a tag option (for example "minlen" or "maxlen").
+type ConstraintError struct {
+	FieldPath  string
+	Constraint string // Tag option name
+	Message    string
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%s: %s", e.FieldPath, e.Message)
+}
+
+// NewConstraintError creates a new ConstraintError.
+func NewConstraintError(fieldPath, constraint, message string) *ConstraintError {
+	if fieldPath == "" {
+		fieldPath = "root"
+	}
+
+	return &ConstraintError{
+		FieldPath:  fieldPath,
+		Constraint: constraint,
+		Message:    message,
+	}
+}
+
+// StructValidationError represents a failure reported by a struct validator.
+type StructValidationError struct {
+	FieldPath string
+	Type      reflect.Type
+	Cause     error
+}
+
+func (e *StructValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.FieldPath, e.Cause)
+}
+
+func (e *StructValidationError) Unwrap() error {
+	return e.Cause
+}
+
+// NewStructValidationError creates a new StructValidationError.
+func NewStructValidationError(fieldPath string, typ reflect.Type, cause error) *StructValidationError {
+	if fieldPath == "" {
+		fieldPath = "root"
+	}
+
+	return &StructValidationError{
+		FieldPath: fieldPath,
+		Type:      typ,
+		Cause:     cause,
+	}
+}
+
+// VerificationError represents a failure reported by a struct verifier, see WithVerifiers.
+type VerificationError struct {
+	FieldPath string
+	Type      reflect.Type
+	Cause     error
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("%s: verification failed: %v", e.FieldPath, e.Cause)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Cause
+}
+
+// NewVerificationError creates a new VerificationError.
+func NewVerificationError(fieldPath string, typ reflect.Type, cause error) *VerificationError {
+	if fieldPath == "" {
+		fieldPath = "root"
+	}
+
+	return &VerificationError{
+		FieldPath: fieldPath,
+		Type:      typ,
+		Cause:     cause,
+	}
+}
+
+// UnsupportedTypeError represents a value that cannot be decoded because no converter
+// is registered for the target type and the type cannot be decoded structurally.
+type UnsupportedTypeError struct {
+	FieldPath string
+	Type      reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("%s: no converter registered for type %v", e.FieldPath, e.Type)
+}
+
+// NewUnsupportedTypeError creates a new UnsupportedTypeError.
+func NewUnsupportedTypeError(fieldPath string, typ reflect.Type) *UnsupportedTypeError {
+	if fieldPath == "" {
+		fieldPath = "root"
+	}
+
+	return &UnsupportedTypeError{
+		FieldPath: fieldPath,
+		Type:      typ,
+	}
+}
+
+// UnsupportedTargetError represents an Unmarshal call whose result points to a type that
+// input maps can never be decoded into, such as a channel or a function.
+type UnsupportedTargetError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTargetError) Error() string {
+	return fmt.Sprintf("cannot unmarshal into %v: target kind %v is not supported, "+
+		"use a pointer to a struct, a map or an interface, or register a converter for the type", e.Type, e.Type.Kind())
+}
+
+// NewUnsupportedTargetError creates a new UnsupportedTargetError.
+func NewUnsupportedTargetError(typ reflect.Type) *UnsupportedTargetError {
+	return &UnsupportedTargetError{Type: typ}
+}
+
+// TagError represents a malformed struct tag. It is reported whenever the struct is
+// decoded, as the field's key and options cannot be determined.
+type TagError struct {
+	Type  reflect.Type // Struct type declaring the field
+	Field string       // Go field name
+	Tag   string       // Raw tag value
+	Cause error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("%v.%s: invalid tag %q: %v", e.Type, e.Field, e.Tag, e.Cause)
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Cause
+}
+
+// NewTagError creates a new TagError.
+func NewTagError(typ reflect.Type, field, tag string, cause error) *TagError {
+	return &TagError{
+		Type:  typ,
+		Field: field,
+		Tag:   tag,
+		Cause: cause,
+	}
+}
+
+// ConverterContractError represents a registered converter returning a value that cannot
+// be stored in its target type, which would otherwise panic inside reflect.
+type ConverterContractError struct {
+	FieldPath  string
+	TargetType reflect.Type // Type the converter is registered for
+	Returned   reflect.Type // Type of the returned value, nil for an invalid reflect.Value
+}
+
+func (e *ConverterContractError) Error() string {
+	if e.Returned == nil {
+		return fmt.Sprintf("%s: converter for %v returned no value", e.FieldPath, e.TargetType)
+	}
+
+	return fmt.Sprintf("%s: converter for %v returned %v", e.FieldPath, e.TargetType, e.Returned)
+}
+
+// NewConverterContractError creates a new ConverterContractError.
+func NewConverterContractError(fieldPath string, targetType, returned reflect.Type) *ConverterContractError {
+	if fieldPath == "" {
+		fieldPath = "root"
+	}
+
+	return &ConverterContractError{
+		FieldPath:  fieldPath,
+		TargetType: targetType,
+		Returned:   returned,
+	}
+}
@@ -50,3 +50,42 @@ var (
 	_ error = (*ConversionError)(nil)
 	_ error = (*ValidationError)(nil)
 )
+
+func TestConstraintError(t *testing.T) {
+	t.Run("error message", func(t *testing.T) {
+		err := NewConstraintError("user.name", "maxlen", "length 6 exceeds maximum 5")
+		assert.Equal(t, "user.name: length 6 exceeds maximum 5", err.Error())
+		assert.Equal(t, "maxlen", err.Constraint)
+	})
+
+	t.Run("empty field path defaults to root", func(t *testing.T) {
+		err := NewConstraintError("", "minlen", "too short")
+		assert.Equal(t, "root", err.FieldPath)
+	})
+}
+
+func TestStructValidationError(t *testing.T) {
+	cause := errors.New("start must be before end")
+	err := NewStructValidationError("", reflect.TypeOf(0), cause)
+
+	assert.Equal(t, "root", err.FieldPath)
+	assert.Equal(t, "root: start must be before end", err.Error())
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestVerificationError(t *testing.T) {
+	cause := errors.New("signature mismatch")
+	err := NewVerificationError("", reflect.TypeOf(0), cause)
+
+	assert.Equal(t, "root", err.FieldPath)
+	assert.Equal(t, "root: verification failed: signature mismatch", err.Error())
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestConverterContractError(t *testing.T) {
+	err := NewConverterContractError("price", reflect.TypeOf(0), reflect.TypeOf(""))
+	assert.Equal(t, "price: converter for int returned string", err.Error())
+
+	err = NewConverterContractError("", reflect.TypeOf(0), nil)
+	assert.Equal(t, "root: converter for int returned no value", err.Error())
+}
@@ -0,0 +1,105 @@
+package mapstructure
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// EventMeta identifies the payload of an event: its name and wire version.
+type EventMeta struct {
+	Name    string // Event name, e.g. "order.placed"
+	Version int    // Payload version, 0 if the payload is not versioned
+}
+
+// EventType registers the struct type decoding the payloads of an event, from a version on.
+type EventType struct {
+	Name  string       // Event name
+	Since int          // First payload version decoded into Type, 0 for all versions
+	Type  reflect.Type // Struct type, or pointer to one, decoding the payloads
+}
+
+// EventRegistry decodes event payloads into the struct type registered for their event name
+// and version, for event-sourced services reading stored or streamed events. Create it with
+// NewEventRegistry. It is safe for concurrent use.
+type EventRegistry struct {
+	unmarshaler *Unmarshaler
+	events      map[string][]EventType // By name, sorted by Since
+}
+
+// NewEventRegistry returns a registry decoding payloads with u, or the shared default
+// unmarshaler when nil, into the given event types. An event may be registered several
+// times with different Since versions when its payload type changed, e.g. OrderPlacedV1
+// from version 0 and OrderPlaced from version 2; within a type, fields tagged with "since"
+// and "until" follow the payload version as with WithVersion.
+func NewEventRegistry(u *Unmarshaler, events ...EventType) (*EventRegistry, error) {
+	if u == nil {
+		u = defaultUnmarshaler
+	}
+
+	byName := make(map[string][]EventType)
+	for _, event := range events {
+		if event.Name == "" {
+			return nil, NewValidationError("event types need a non-empty name")
+		}
+		if event.Type == nil || structType(event.Type).Kind() != reflect.Struct {
+			return nil, NewValidationError(fmt.Sprintf("event %q must decode into a struct type, got %v", event.Name, event.Type))
+		}
+		if event.Since < 0 {
+			return nil, NewValidationError(fmt.Sprintf("event %q has negative version %d", event.Name, event.Since))
+		}
+		if slices.ContainsFunc(byName[event.Name], func(e EventType) bool { return e.Since == event.Since }) {
+			return nil, NewValidationError(fmt.Sprintf("event %q is registered twice since version %d", event.Name, event.Since))
+		}
+
+		event.Type = structType(event.Type)
+		byName[event.Name] = append(byName[event.Name], event)
+	}
+	for _, versions := range byName {
+		slices.SortFunc(versions, func(a, b EventType) int { return cmp.Compare(a.Since, b.Since) })
+	}
+
+	return &EventRegistry{unmarshaler: u, events: byName}, nil
+}
+
+// Type returns the struct type registered for the event meta identifies: the one with the
+// latest Since not after meta.Version. It reports false for unknown events and versions
+// before the first registered one.
+func (r *EventRegistry) Type(meta EventMeta) (reflect.Type, bool) {
+	versions := r.events[meta.Name]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].Since <= meta.Version {
+			return versions[i].Type, true
+		}
+	}
+
+	return nil, false
+}
+
+// DecodeEvent decodes payload into a new value of the struct type registered for meta and
+// returns a pointer to it, for a type switch over the known events. Payloads of versioned
+// events (meta.Version > 0) are decoded with WithVersion(meta.Version). Unknown events and
+// versions fail with a *ConstraintError for the "event" constraint.
+func (r *EventRegistry) DecodeEvent(meta EventMeta, payload map[string]any) (any, error) {
+	typ, ok := r.Type(meta)
+	if !ok {
+		if _, known := r.events[meta.Name]; known {
+			return nil, NewConstraintError("", "event", fmt.Sprintf("event %q has no type for version %d", meta.Name, meta.Version))
+		}
+
+		return nil, NewConstraintError("", "event", fmt.Sprintf("unknown event %q", meta.Name))
+	}
+
+	var opts []CallOption
+	if meta.Version > 0 {
+		opts = append(opts, WithVersion(meta.Version))
+	}
+
+	event := reflect.New(typ)
+	if err := r.unmarshaler.Unmarshal(payload, event.Interface(), opts...); err != nil {
+		return nil, err
+	}
+
+	return event.Interface(), nil
+}
@@ -0,0 +1,151 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderPlacedV1 struct {
+	OrderID string `schema:"order_id"`
+	Amount  int    `schema:"amount"`
+}
+
+type orderPlaced struct {
+	OrderID  string `schema:"order_id"`
+	Amount   int    `schema:"amount,until=2"`
+	Cents    int    `schema:"cents,since=3"`
+	Currency string `schema:"currency" default:"EUR"`
+}
+
+type orderCancelled struct {
+	OrderID string `schema:"order_id"`
+}
+
+func newTestEventRegistry(t *testing.T) *EventRegistry {
+	t.Helper()
+
+	registry, err := NewEventRegistry(nil,
+		EventType{Name: "order.placed", Since: 2, Type: reflect.TypeFor[orderPlaced]()},
+		EventType{Name: "order.placed", Type: reflect.TypeFor[orderPlacedV1]()},
+		EventType{Name: "order.cancelled", Type: reflect.TypeFor[*orderCancelled]()},
+	)
+	require.NoError(t, err)
+
+	return registry
+}
+
+func TestEventRegistry_DecodeEvent(t *testing.T) {
+	registry := newTestEventRegistry(t)
+
+	tests := []struct {
+		name    string
+		meta    EventMeta
+		payload map[string]any
+		want    any
+		wantErr string
+	}{
+		{
+			name:    "first version",
+			meta:    EventMeta{Name: "order.placed", Version: 1},
+			payload: map[string]any{"order_id": "o1", "amount": "10"},
+			want:    &orderPlacedV1{OrderID: "o1", Amount: 10},
+		},
+		{
+			name:    "unversioned payload",
+			meta:    EventMeta{Name: "order.placed"},
+			payload: map[string]any{"order_id": "o1"},
+			want:    &orderPlacedV1{OrderID: "o1"},
+		},
+		{
+			name:    "type changed",
+			meta:    EventMeta{Name: "order.placed", Version: 2},
+			payload: map[string]any{"order_id": "o2", "amount": 10, "cents": 1000},
+			want:    &orderPlaced{OrderID: "o2", Amount: 10, Currency: "EUR"},
+		},
+		{
+			name:    "version tags within a type",
+			meta:    EventMeta{Name: "order.placed", Version: 5},
+			payload: map[string]any{"order_id": "o3", "amount": 10, "cents": 1000},
+			want:    &orderPlaced{OrderID: "o3", Cents: 1000, Currency: "EUR"},
+		},
+		{
+			name:    "pointer type registered",
+			meta:    EventMeta{Name: "order.cancelled", Version: 7},
+			payload: map[string]any{"order_id": "o4"},
+			want:    &orderCancelled{OrderID: "o4"},
+		},
+		{
+			name:    "unknown event",
+			meta:    EventMeta{Name: "order.shipped"},
+			wantErr: `root: unknown event "order.shipped"`,
+		},
+		{
+			name:    "decoding errors",
+			meta:    EventMeta{Name: "order.placed", Version: 1},
+			payload: map[string]any{"amount": "ten"},
+			wantErr: "amount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registry.DecodeEvent(tt.meta, tt.payload)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventRegistry_VersionBeforeFirst(t *testing.T) {
+	registry, err := NewEventRegistry(NewDefaultUnmarshaler(),
+		EventType{Name: "user.renamed", Since: 3, Type: reflect.TypeFor[orderCancelled]()})
+	require.NoError(t, err)
+
+	_, err = registry.DecodeEvent(EventMeta{Name: "user.renamed", Version: 2}, nil)
+	var constraintErr *ConstraintError
+	require.ErrorAs(t, err, &constraintErr)
+	assert.Equal(t, "event", constraintErr.Constraint)
+	assert.EqualError(t, err, `root: event "user.renamed" has no type for version 2`)
+
+	typ, ok := registry.Type(EventMeta{Name: "user.renamed", Version: 3})
+	require.True(t, ok)
+	assert.Equal(t, reflect.TypeFor[orderCancelled](), typ)
+}
+
+func TestNewEventRegistry_Invalid(t *testing.T) {
+	typ := reflect.TypeFor[orderCancelled]()
+
+	tests := []struct {
+		name    string
+		events  []EventType
+		wantErr string
+	}{
+		{name: "empty name", events: []EventType{{Type: typ}}, wantErr: "non-empty name"},
+		{name: "nil type", events: []EventType{{Name: "a"}}, wantErr: "struct type, got <nil>"},
+		{name: "not a struct", events: []EventType{{Name: "a", Type: reflect.TypeFor[int]()}}, wantErr: "struct type, got int"},
+		{name: "negative version", events: []EventType{{Name: "a", Since: -1, Type: typ}}, wantErr: "negative version"},
+		{
+			name:    "duplicate version",
+			events:  []EventType{{Name: "a", Since: 1, Type: typ}, {Name: "a", Since: 1, Type: typ}},
+			wantErr: `event "a" is registered twice since version 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEventRegistry(nil, tt.events...)
+			require.ErrorAs(t, err, new(*ValidationError))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package mapstructure_test
+
+import (
+	"fmt"
+
+	"github.com/talav/mapstructure"
+)
+
+// Page is a generic API envelope holding one page of results.
+type Page[T any] struct {
+	Items []T `schema:"items"`
+	Total int `schema:"total"`
+}
+
+// Product is an item of a paged API response.
+type Product struct {
+	SKU   string  `schema:"sku"`
+	Price float64 `schema:"price"`
+	Stock int     `schema:"stock" default:"0"`
+}
+
+// Generic envelopes decode like any other struct; each instantiation is cached separately
+// and elements use the converters registered for the type parameter.
+func ExampleDecoder_genericEnvelope() {
+	decodeProducts := mapstructure.NewDecoder[Page[Product]](nil)
+
+	page, err := decodeProducts.Decode(map[string]any{
+		"items": []any{
+			map[string]any{"sku": "A-1", "price": "9.99", "stock": 3},
+			map[string]any{"sku": "B-2", "price": 20},
+		},
+		"total": 2,
+	})
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	for _, p := range page.Items {
+		fmt.Printf("%s %.2f %d\n", p.SKU, p.Price, p.Stock)
+	}
+	fmt.Println("total:", page.Total)
+	// Output:
+	// A-1 9.99 3
+	// B-2 20.00 0
+	// total: 2
+}
@@ -0,0 +1,431 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OptionExpr is the tag option transforming numeric fields with an arithmetic expression
+// over the decoded input, for simple unit conversions without a converter per field, e.g.
+// `schema:"price,expr=value*100"` stores 19.99 as 1999 in an int field holding cents.
+//
+// The input is first converted to a float64, then the expression is evaluated and its
+// result stored in the field, which may have any integer or float kind, behind pointers
+// and in slices and arrays. Expressions use the input as value, numbers, + - * /,
+// parentheses and calls of ExprFunc by name, such as round(value*100); a bare name is
+// short for calling it on value (expr=cents). Results stored in integer fields must be
+// integral and in range. Defaults of the field are transformed too. Malformed expressions
+// are reported as a *TagError when decoding the struct, as are other invalid tags, while
+// functions are looked up when the expression is evaluated, as WithExprFuncs may add them.
+const OptionExpr = "expr"
+
+// Names of the built-in expression functions.
+const (
+	ExprRound = "round" // Rounds half away from zero
+	ExprFloor = "floor"
+	ExprCeil  = "ceil"
+	ExprTrunc = "trunc"
+	ExprAbs   = "abs"
+)
+
+// ExprFunc is a function called by name from "expr" tag option expressions, see OptionExpr.
+// Errors are reported as *ConversionError for the field.
+type ExprFunc func(value float64) (float64, error)
+
+// builtinExprFuncs are the expression functions available to every unmarshaler.
+var builtinExprFuncs = map[string]ExprFunc{
+	ExprRound: func(v float64) (float64, error) { return math.Round(v), nil },
+	ExprFloor: func(v float64) (float64, error) { return math.Floor(v), nil },
+	ExprCeil:  func(v float64) (float64, error) { return math.Ceil(v), nil },
+	ExprTrunc: func(v float64) (float64, error) { return math.Trunc(v), nil },
+	ExprAbs:   func(v float64) (float64, error) { return math.Abs(v), nil },
+}
+
+// WithExprFuncs returns a new unmarshaler extending u with the given expression functions,
+// keyed by the name "expr" tag options call them by, e.g. a "cents" function shared by
+// all price fields. Functions replace any previously registered, or built in, under the
+// same name. u is left unchanged.
+func (u *Unmarshaler) WithExprFuncs(funcs map[string]ExprFunc) *Unmarshaler {
+	merged := maps.Clone(u.exprFuncs)
+	if merged == nil {
+		merged = make(map[string]ExprFunc, len(funcs))
+	}
+	maps.Copy(merged, funcs)
+
+	configured := *u
+	configured.exprFuncs = merged
+
+	return &configured
+}
+
+// exprFunc returns the expression function n calls: the one registered under its name,
+// falling back to the built-in one resolved when parsing.
+func (u *Unmarshaler) exprFunc(n *exprNode) (ExprFunc, bool) {
+	if fn, ok := u.exprFuncs[n.name]; ok {
+		return fn, true
+	}
+
+	return n.fn, n.fn != nil
+}
+
+var errExprNotNumeric = errors.New(`"expr" requires an integer or float field`)
+
+// unmarshalExpr decodes value into rv, a field with the "expr" tag option source.
+func (u *Unmarshaler) unmarshalExpr(value any, rv reflect.Value, fieldPath, source string) error {
+	if !rv.CanSet() {
+		return nil
+	}
+	if value == nil {
+		return u.unmarshalValue(nil, rv, fieldPath, nil) // Null handling as without the option
+	}
+
+	expr, err := parseExpr(source)
+	if err != nil {
+		return NewConversionError(fieldPath, value, rv.Type(), err)
+	}
+
+	//nolint:exhaustive // Numbers and their containers are transformed
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		return u.unmarshalExpr(value, rv.Elem(), fieldPath, source)
+	case reflect.Slice, reflect.Array:
+		elems := reflect.ValueOf(value)
+		if elems.Kind() != reflect.Slice && elems.Kind() != reflect.Array {
+			return NewConversionError(fieldPath, value, rv.Type(), nil)
+		}
+
+		target := rv
+		if rv.Kind() == reflect.Slice {
+			target = reflect.MakeSlice(rv.Type(), elems.Len(), elems.Len())
+		} else if elems.Len() != rv.Len() {
+			return NewConversionError(fieldPath, value, rv.Type(),
+				fmt.Errorf("expected %d elements, got %d", rv.Len(), elems.Len()))
+		}
+
+		for i := range elems.Len() {
+			elemPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+			if err := u.unmarshalExpr(elems.Index(i).Interface(), target.Index(i), elemPath, source); err != nil {
+				return err
+			}
+		}
+		rv.Set(target)
+
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+	default:
+		return NewConversionError(fieldPath, value, rv.Type(), errExprNotNumeric)
+	}
+
+	var input float64
+	if err := u.unmarshalValue(value, reflect.ValueOf(&input).Elem(), fieldPath, nil); err != nil {
+		return err
+	}
+
+	result, err := expr.eval(u, input)
+	if err != nil {
+		return NewConversionError(fieldPath, value, rv.Type(), err)
+	}
+	if err := setNumber(rv, result); err != nil {
+		return NewConversionError(fieldPath, value, rv.Type(), err)
+	}
+
+	return nil
+}
+
+// setNumber stores the expression result f in rv, a number of any integer or float kind.
+func setNumber(rv reflect.Value, f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("expression result %v is not a finite number", f)
+	}
+
+	//nolint:exhaustive // Callers pass numbers only
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if rv.OverflowFloat(f) {
+			return fmt.Errorf("expression result %v overflows %s", f, rv.Type())
+		}
+		rv.SetFloat(f)
+
+		return nil
+	}
+
+	// Absorb float error such as 19.99*100 = 1998.9999999999998
+	if rounded := math.Round(f); math.Abs(f-rounded) <= 1e-9*math.Max(1, math.Abs(f)) {
+		f = rounded
+	} else {
+		return fmt.Errorf("expression result %v is not an integer", f)
+	}
+
+	//nolint:exhaustive // Callers pass numbers only
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if f < 0 || f >= math.Exp2(64) || rv.OverflowUint(uint64(f)) {
+			return fmt.Errorf("expression result %v overflows %s", f, rv.Type())
+		}
+		rv.SetUint(uint64(f))
+	default:
+		if f < math.MinInt64 || f >= math.MaxInt64 || rv.OverflowInt(int64(f)) {
+			return fmt.Errorf("expression result %v overflows %s", f, rv.Type())
+		}
+		rv.SetInt(int64(f))
+	}
+
+	return nil
+}
+
+// exprNode is a node of a parsed "expr" tag option expression.
+type exprNode struct {
+	op          byte // '+', '-', '*', '/', 'n' (number), 'v' (value), 'f' (call), 'u' (negation)
+	num         float64
+	name        string   // Function called by 'f' nodes
+	fn          ExprFunc // Built-in function named by 'f' nodes, nil for other names
+	left, right *exprNode
+}
+
+// parsedExprs caches parsed expressions by source, as tags are few and reused.
+var parsedExprs sync.Map
+
+// parseExpr parses an "expr" tag option expression, see OptionExpr.
+func parseExpr(source string) (*exprNode, error) {
+	if cached, ok := parsedExprs.Load(source); ok {
+		if node, ok := cached.(*exprNode); ok {
+			return node, nil
+		}
+	}
+
+	p := &exprParser{src: source}
+	node, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+
+	parsedExprs.Store(source, node)
+
+	return node, nil
+}
+
+// exprParser is a recursive descent parser for expressions:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | "value" | name "(" expr ")" | "(" expr ")"
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) parse() (*exprNode, error) {
+	// A bare function name is called on value
+	if name := strings.TrimSpace(p.src); isExprName(name) && name != "value" {
+		return &exprNode{op: 'f', name: name, fn: builtinExprFuncs[name], left: &exprNode{op: 'v'}}, nil
+	}
+
+	node, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.src[p.pos], p.pos)
+	}
+
+	return node, nil
+}
+
+func (p *exprParser) expr() (*exprNode, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.next('+', '-') {
+		op := p.src[p.pos-1]
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprNode{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) term() (*exprNode, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.next('*', '/') {
+		op := p.src[p.pos-1]
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprNode{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) unary() (*exprNode, error) {
+	if p.next('-') {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &exprNode{op: 'u', left: operand}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *exprParser) primary() (*exprNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, errors.New("unexpected end")
+	}
+
+	start := p.pos
+	switch c := p.src[p.pos]; {
+	case c == '(':
+		p.pos++
+		node, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(')') {
+			return nil, fmt.Errorf("missing ) for ( at offset %d", start)
+		}
+
+		return node, nil
+	case c == '.' || c >= '0' && c <= '9':
+		for p.pos < len(p.src) && (p.src[p.pos] == '.' || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+			p.pos++
+		}
+		num, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.src[start:p.pos])
+		}
+
+		return &exprNode{op: 'n', num: num}, nil
+	case isExprNameByte(c):
+		for p.pos < len(p.src) && isExprNameByte(p.src[p.pos]) {
+			p.pos++
+		}
+		name := p.src[start:p.pos]
+		if name == "value" {
+			return &exprNode{op: 'v'}, nil
+		}
+		if !p.next('(') {
+			return nil, fmt.Errorf("unknown name %q, expected value or a function call", name)
+		}
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(')') {
+			return nil, fmt.Errorf("missing ) for %s( at offset %d", name, start)
+		}
+
+		return &exprNode{op: 'f', name: name, fn: builtinExprFuncs[name], left: arg}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", c, p.pos)
+	}
+}
+
+// next consumes the next non-space byte when it is one of ops.
+func (p *exprParser) next(ops ...byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.src) && strings.IndexByte(string(ops), p.src[p.pos]) >= 0 {
+		p.pos++
+
+		return true
+	}
+
+	return false
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// isExprName reports whether s is a name in expressions.
+func isExprName(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := range len(s) {
+		if !isExprNameByte(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isExprNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// eval evaluates the expression for value, calling the expression functions of u.
+func (n *exprNode) eval(u *Unmarshaler, value float64) (float64, error) {
+	switch n.op {
+	case 'n':
+		return n.num, nil
+	case 'v':
+		return value, nil
+	}
+
+	left, err := n.left.eval(u, value)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case 'u':
+		return -left, nil
+	case 'f':
+		fn, ok := u.exprFunc(n)
+		if !ok {
+			return 0, fmt.Errorf("unknown expression function %q", n.name)
+		}
+
+		return fn(left)
+	}
+
+	right, err := n.right.eval(u, value)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, errors.New("division by zero")
+		}
+
+		return left / right, nil
+	}
+}
@@ -0,0 +1,250 @@
+package mapstructure
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpr(t *testing.T) {
+	u := NewDefaultUnmarshaler()
+
+	tests := []struct {
+		source  string
+		value   float64
+		want    float64
+		wantErr string
+	}{
+		{source: "value*100", value: 19.99, want: 1999},
+		{source: "value / 1000", value: 2500, want: 2.5},
+		{source: "(value - 32) * 5 / 9", value: 212, want: 100},
+		{source: "value + 1 * 2", value: 1, want: 3},
+		{source: "-value", value: 4, want: -4},
+		{source: "--value", value: 4, want: 4},
+		{source: "value*-1", value: 4, want: -4},
+		{source: ".5*value", value: 4, want: 2},
+		{source: "round(value*100)", value: 0.125, want: 13},
+		{source: "floor(value)", value: 1.7, want: 1},
+		{source: "ceil(value)", value: 1.2, want: 2},
+		{source: "trunc(value)", value: -1.7, want: -1},
+		{source: "abs", value: -3, want: 3},
+		{source: "value", value: 7, want: 7},
+		{source: "", wantErr: "unexpected end"},
+		{source: "value*", wantErr: "unexpected end"},
+		{source: "value**2", wantErr: `unexpected '*' at offset 6`},
+		{source: "(value", wantErr: "missing ) for ( at offset 0"},
+		{source: "value)", wantErr: `unexpected ')' at offset 5`},
+		{source: "round(value", wantErr: "missing ) for round( at offset 0"},
+		{source: "value*x", wantErr: `unknown name "x", expected value or a function call`},
+		{source: "1.2.3", wantErr: `invalid number "1.2.3"`},
+		{source: "value/0", value: 1, wantErr: "division by zero"},
+		{source: "nope(value)", value: 1, wantErr: `unknown expression function "nope"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			expr, err := parseExpr(tt.source)
+			if err == nil {
+				var got float64
+				got, err = expr.eval(u, tt.value)
+				if tt.wantErr == "" {
+					require.NoError(t, err)
+					assert.InDelta(t, tt.want, got, 1e-9)
+
+					return
+				}
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_Expr(t *testing.T) {
+	type Product struct {
+		PriceCents int           `schema:"price,expr=value*100"`
+		Discount   *uint8        `schema:"discount,expr=value*100"`
+		Weights    []int64       `schema:"weights,expr=value*1000"`
+		Celsius    float32       `schema:"temp,expr=(value - 32) * 5 / 9"`
+		Timeout    time.Duration `schema:"timeout,expr=value*1000000000"`
+		Rounded    int           `schema:"rounded,expr=round(value)"`
+		Default    int           `schema:"default,expr=value*10" default:"1.5"`
+		Pair       [2]int        `schema:"pair,expr=value*2"`
+	}
+
+	quarter := uint8(25)
+
+	tests := []struct {
+		name    string
+		data    map[string]any
+		want    Product
+		wantErr string
+	}{
+		{
+			name: "scalars",
+			data: map[string]any{"price": 19.99, "temp": 212, "timeout": "1.5", "rounded": 2.5},
+			want: Product{PriceCents: 1999, Celsius: 100, Timeout: 1500 * time.Millisecond, Rounded: 3, Default: 15},
+		},
+		{
+			name: "pointer",
+			data: map[string]any{"discount": "0.25"},
+			want: Product{Discount: &quarter, Default: 15},
+		},
+		{
+			name: "slice and array elements",
+			data: map[string]any{"weights": []any{1.5, "0.002"}, "pair": []any{1, 2}},
+			want: Product{Weights: []int64{1500, 2}, Pair: [2]int{2, 4}, Default: 15},
+		},
+		{
+			name: "default transformed",
+			data: map[string]any{"default": 3},
+			want: Product{Default: 30},
+		},
+		{
+			name:    "not an integer",
+			data:    map[string]any{"price": 0.001},
+			wantErr: "expression result 0.1 is not an integer",
+		},
+		{
+			name:    "overflow",
+			data:    map[string]any{"discount": 3},
+			wantErr: "expression result 300 overflows uint8",
+		},
+		{
+			name:    "negative unsigned",
+			data:    map[string]any{"discount": -0.5},
+			wantErr: "overflows uint8",
+		},
+		{
+			name:    "input not a number",
+			data:    map[string]any{"price": "cheap"},
+			wantErr: "price",
+		},
+		{
+			name:    "element error path",
+			data:    map[string]any{"weights": []any{1, "x"}},
+			wantErr: "weights[1]",
+		},
+		{
+			name:    "array length",
+			data:    map[string]any{"pair": []any{1}},
+			wantErr: "expected 2 elements, got 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Product
+			err := Unmarshal(tt.data, &got)
+			if tt.wantErr != "" {
+				var convErr *ConversionError
+				require.ErrorAs(t, err, &convErr)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_ExprErrors(t *testing.T) {
+	t.Run("non-numeric field", func(t *testing.T) {
+		var got struct {
+			Name string `schema:"name,expr=value*2"`
+		}
+		err := Unmarshal(map[string]any{"name": "a"}, &got)
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.True(t, errors.Is(err, errExprNotNumeric))
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		var got struct {
+			N int `schema:"n,expr=value*"`
+		}
+		err := Unmarshal(map[string]any{"n": 1}, &got)
+		assert.ErrorContains(t, err, `invalid expression "value*": unexpected end`)
+
+		// Reported for the struct, whether or not its key is in the input
+		err = Unmarshal(map[string]any{}, &got)
+		var tagErr *TagError
+		require.ErrorAs(t, err, &tagErr)
+		assert.Equal(t, "N", tagErr.Field)
+		assert.ErrorContains(t, err, `invalid expression "value*"`)
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		var got struct {
+			N int `schema:"n,expr=value*rate"`
+		}
+		err := Unmarshal(map[string]any{}, &got)
+		assert.ErrorAs(t, err, new(*TagError))
+	})
+
+	t.Run("functions resolved when evaluated", func(t *testing.T) {
+		var got struct {
+			N int `schema:"n,expr=rate(value)"`
+		}
+		require.NoError(t, Unmarshal(map[string]any{}, &got), "may be registered with WithExprFuncs")
+
+		err := Unmarshal(map[string]any{"n": 1}, &got)
+		assert.ErrorContains(t, err, `unknown expression function "rate"`)
+	})
+
+	t.Run("not finite", func(t *testing.T) {
+		var got struct {
+			F float64 `schema:"f,expr=value*value"`
+		}
+		err := Unmarshal(map[string]any{"f": math.MaxFloat64}, &got)
+		assert.ErrorContains(t, err, "is not a finite number")
+	})
+
+	t.Run("nil input", func(t *testing.T) {
+		var got struct {
+			P *int `schema:"p,expr=value*2"`
+		}
+		one := 1
+		got.P = &one
+		require.NoError(t, Unmarshal(map[string]any{"p": nil}, &got))
+		assert.Nil(t, got.P)
+	})
+}
+
+func TestUnmarshaler_WithExprFuncs(t *testing.T) {
+	type Order struct {
+		Total  int `schema:"total,expr=cents"`
+		Shares int `schema:"shares,expr=round(value*ratio(value))"`
+	}
+
+	base := NewDefaultUnmarshaler()
+	u := base.WithExprFuncs(map[string]ExprFunc{
+		"cents": func(v float64) (float64, error) { return math.Round(v * 100), nil },
+		"ratio": func(v float64) (float64, error) {
+			if v < 0 {
+				return 0, errors.New("negative")
+			}
+
+			return 2, nil
+		},
+		ExprRound: func(v float64) (float64, error) { return math.Floor(v), nil }, // Overrides the built-in
+	})
+
+	var got Order
+	require.NoError(t, u.Unmarshal(map[string]any{"total": "12.345", "shares": 1.7}, &got))
+	assert.Equal(t, Order{Total: 1235, Shares: 3}, got)
+
+	err := u.Unmarshal(map[string]any{"shares": -1}, &got)
+	assert.ErrorContains(t, err, "negative")
+
+	err = base.Unmarshal(map[string]any{"total": 1}, &got)
+	assert.ErrorContains(t, err, `unknown expression function "cents"`, "receiver unchanged")
+	assert.Equal(t, 3, u.Config().ExprFuncs)
+}
@@ -0,0 +1,106 @@
+package mapstructure
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Factories builds implementations of an interface type by name, for plugin-style
+// configuration sections: input such as {"driver": "postgres", "dsn": "..."} decoded into
+// a Driver field calls the factory registered for "postgres" with the rest of the map.
+// Create it with NewFactories and register it with ConverterRegistry.WithFactories.
+type Factories struct {
+	typ       reflect.Type
+	key       string
+	factories map[string]func(config map[string]any) (reflect.Value, error)
+}
+
+// NewFactories returns the factories building values of the interface type T, selected
+// by the name held by key in the input. Each factory receives the input map without key.
+func NewFactories[T any](key string, factories map[string]func(config map[string]any) (T, error)) (*Factories, error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Interface {
+		return nil, NewValidationError(fmt.Sprintf("factories must build an interface type, got %s", typ))
+	}
+	if key == "" {
+		return nil, NewValidationError(fmt.Sprintf("factories for %s need a non-empty key", typ))
+	}
+
+	wrapped := make(map[string]func(map[string]any) (reflect.Value, error), len(factories))
+	for name, factory := range factories {
+		if factory == nil {
+			return nil, NewValidationError(fmt.Sprintf("factory %q for %s is nil", name, typ))
+		}
+		wrapped[name] = func(config map[string]any) (reflect.Value, error) {
+			v, err := factory(config)
+
+			return reflect.ValueOf(&v).Elem(), err
+		}
+	}
+
+	return &Factories{typ: typ, key: key, factories: wrapped}, nil
+}
+
+// Type returns the interface type built by the factories.
+func (f *Factories) Type() reflect.Type {
+	return f.typ
+}
+
+// Key returns the input key holding the name of the factory to call.
+func (f *Factories) Key() string {
+	return f.key
+}
+
+// Names returns the names of the registered factories, sorted.
+func (f *Factories) Names() []string {
+	return slices.Sorted(maps.Keys(f.factories))
+}
+
+// Converter returns a DelegatingConverter calling the factory named in map input.
+// Input that is not a map[string]any is declined with ErrSkipConverter. A missing,
+// non-string or unknown name is rejected with a *ConstraintError, and errors returned
+// by factories are reported as a *ConversionError.
+func (f *Factories) Converter() DelegatingConverter {
+	return func(value any, ctx *ConvertContext) (reflect.Value, error) {
+		dataMap, ok := value.(map[string]any)
+		if !ok {
+			return reflect.Value{}, ErrSkipConverter
+		}
+
+		keyPath := buildFieldPath(ctx.fieldPath, f.key)
+		raw, ok := dataMap[f.key]
+		if !ok || raw == nil {
+			return reflect.Value{}, ctx.fail(NewConstraintError(keyPath, "factory",
+				fmt.Sprintf("missing key %q, expected one of %q", f.key, f.Names())))
+		}
+		name, ok := raw.(string)
+		if !ok {
+			return reflect.Value{}, ctx.fail(NewConstraintError(keyPath, "factory",
+				fmt.Sprintf("expected a name, got %T", raw)))
+		}
+		factory, ok := f.factories[name]
+		if !ok {
+			return reflect.Value{}, ctx.fail(NewConstraintError(keyPath, "factory",
+				fmt.Sprintf("unknown name %q, expected one of %q", name, f.Names())))
+		}
+
+		config := maps.Clone(dataMap)
+		delete(config, f.key)
+
+		return factory(config)
+	}
+}
+
+// WithFactories returns a new registry extending r with the converters of the given
+// factories, registered as delegating converters for their interface types. The receiver
+// is left unchanged.
+func (r *ConverterRegistry) WithFactories(factories ...*Factories) *ConverterRegistry {
+	delegating := make(map[reflect.Type]DelegatingConverter, len(factories))
+	for _, f := range factories {
+		delegating[f.typ] = f.Converter()
+	}
+
+	return r.WithDelegatingConverters(delegating)
+}
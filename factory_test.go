@@ -0,0 +1,211 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDriver interface {
+	Name() string
+}
+
+type testPostgres struct {
+	DSN string `schema:"dsn"`
+}
+
+func (*testPostgres) Name() string { return "postgres" }
+
+type testMemory struct{}
+
+func (testMemory) Name() string { return "memory" }
+
+func newTestDriverFactories(t *testing.T) *Factories {
+	t.Helper()
+
+	factories, err := NewFactories("driver", map[string]func(map[string]any) (testDriver, error){
+		"postgres": func(config map[string]any) (testDriver, error) {
+			var pg testPostgres
+			if err := Unmarshal(config, &pg); err != nil {
+				return nil, err
+			}
+			if pg.DSN == "" {
+				return nil, errors.New("dsn is required")
+			}
+
+			return &pg, nil
+		},
+		"memory": func(config map[string]any) (testDriver, error) {
+			if len(config) > 0 {
+				return nil, fmt.Errorf("unexpected options %v", config)
+			}
+
+			return testMemory{}, nil
+		},
+		"none": func(map[string]any) (testDriver, error) {
+			return nil, nil
+		},
+	})
+	require.NoError(t, err)
+
+	return factories
+}
+
+func TestNewFactories(t *testing.T) {
+	factories := newTestDriverFactories(t)
+	assert.Equal(t, reflect.TypeFor[testDriver](), factories.Type())
+	assert.Equal(t, "driver", factories.Key())
+	assert.Equal(t, []string{"memory", "none", "postgres"}, factories.Names())
+
+	tests := []struct {
+		name    string
+		build   func() (*Factories, error)
+		wantErr string
+	}{
+		{
+			name: "not an interface",
+			build: func() (*Factories, error) {
+				return NewFactories("driver", map[string]func(map[string]any) (testPostgres, error){})
+			},
+			wantErr: "factories must build an interface type, got mapstructure.testPostgres",
+		},
+		{
+			name: "empty key",
+			build: func() (*Factories, error) {
+				return NewFactories("", map[string]func(map[string]any) (testDriver, error){})
+			},
+			wantErr: "need a non-empty key",
+		},
+		{
+			name: "nil factory",
+			build: func() (*Factories, error) {
+				return NewFactories("driver", map[string]func(map[string]any) (testDriver, error){"postgres": nil})
+			},
+			wantErr: `factory "postgres" for mapstructure.testDriver is nil`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.build()
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_Factories(t *testing.T) {
+	type Config struct {
+		Primary  testDriver   `schema:"primary"`
+		Replicas []testDriver `schema:"replicas"`
+	}
+
+	u := NewUnmarshaler(NewStructMetadataCache("schema", "default"),
+		NewDefaultConverterRegistry().WithFactories(newTestDriverFactories(t)))
+
+	tests := []struct {
+		name    string
+		data    map[string]any
+		seed    Config
+		want    Config
+		wantErr string
+		errAs   func(error) bool
+	}{
+		{
+			name: "factory selected by name",
+			data: map[string]any{"primary": map[string]any{"driver": "postgres", "dsn": "postgres://db"}},
+			want: Config{Primary: &testPostgres{DSN: "postgres://db"}},
+		},
+		{
+			name: "name key removed from config",
+			data: map[string]any{"primary": map[string]any{"driver": "memory"}},
+			want: Config{Primary: testMemory{}},
+		},
+		{
+			name: "slice elements",
+			data: map[string]any{"replicas": []any{
+				map[string]any{"driver": "memory"},
+				map[string]any{"driver": "postgres", "dsn": "postgres://replica"},
+			}},
+			want: Config{Replicas: []testDriver{testMemory{}, &testPostgres{DSN: "postgres://replica"}}},
+		},
+		{
+			name: "nil result",
+			data: map[string]any{"primary": map[string]any{"driver": "none"}},
+			want: Config{},
+		},
+		{
+			name: "nil input",
+			data: map[string]any{"primary": nil},
+			want: Config{},
+		},
+		{
+			name: "implementation assigned directly",
+			data: map[string]any{"primary": testMemory{}},
+			want: Config{Primary: testMemory{}},
+		},
+		{
+			name: "seeded value completed in place",
+			data: map[string]any{"primary": map[string]any{"driver": "postgres", "dsn": "postgres://new"}},
+			seed: Config{Primary: &testPostgres{DSN: "postgres://old"}},
+			want: Config{Primary: &testPostgres{DSN: "postgres://new"}},
+		},
+		{
+			name:    "unknown name",
+			data:    map[string]any{"primary": map[string]any{"driver": "mysql"}},
+			wantErr: `primary.driver: unknown name "mysql", expected one of ["memory" "none" "postgres"]`,
+			errAs:   isErr[*ConstraintError],
+		},
+		{
+			name:    "missing name",
+			data:    map[string]any{"primary": map[string]any{"dsn": "postgres://db"}},
+			wantErr: `primary.driver: missing key "driver"`,
+			errAs:   isErr[*ConstraintError],
+		},
+		{
+			name:    "name not a string",
+			data:    map[string]any{"replicas": []any{map[string]any{"driver": 1}}},
+			wantErr: "replicas[0].driver: expected a name, got int",
+			errAs:   isErr[*ConstraintError],
+		},
+		{
+			name:    "factory error",
+			data:    map[string]any{"primary": map[string]any{"driver": "postgres"}},
+			wantErr: "dsn is required",
+			errAs:   isErr[*ConversionError],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.seed
+			err := u.Unmarshal(tt.data, &got)
+			if tt.errAs != nil {
+				require.Error(t, err)
+				assert.True(t, tt.errAs(err), "unexpected error type %T", err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConverterRegistry_WithFactories(t *testing.T) {
+	base := NewDefaultConverterRegistry()
+	registry := base.WithFactories(newTestDriverFactories(t))
+
+	_, ok := registry.FindDelegating(reflect.TypeFor[testDriver]())
+	assert.True(t, ok)
+
+	_, ok = base.FindDelegating(reflect.TypeFor[testDriver]())
+	assert.False(t, ok, "receiver unchanged")
+}
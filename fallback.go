@@ -0,0 +1,53 @@
+package mapstructure
+
+import "reflect"
+
+// OptionFallback is the tag option giving the value decoded into a field whose input fails
+// to decode, instead of failing the call, for pipelines that prefer degraded values over
+// dropped records, e.g. `schema:"retries,fallback=3"`. The value is decoded like a default
+// tag; the replaced inputs are listed in DecodeStats.Fallbacks when the call requests
+// WithStats, and logged as warnings with the logger set by WithLogger. Defaults and missing keys do
+// not use the fallback, and when the fallback does not decode either, the original error
+// is reported. Length constraints still apply to the fallback value.
+const OptionFallback = "fallback"
+
+// FieldFallback describes a field set from its fallback because its input failed to decode, see OptionFallback.
+type FieldFallback struct {
+	FieldPath string // Path of the field, e.g. "job.retries"
+	Input     any    // Input value that failed to decode
+	Err       error  // Error that decoding the input returned
+}
+
+// unmarshalFallback decodes the fallback of field into fieldValue after decoding input
+// failed with err, discarding the validator errors reported while decoding it, which
+// followed the first validationErrs. It reports whether the field has a fallback that decoded.
+func (u *Unmarshaler) unmarshalFallback(input any, fieldValue reflect.Value, fieldPath string, field *FieldMetadata,
+	err error, validationErrs int,
+) bool {
+	fallback, ok := field.Option(OptionFallback)
+	if !ok {
+		return false
+	}
+
+	if u.state != nil {
+		u.state.validationErrors = u.state.validationErrors[:validationErrs]
+	}
+
+	fieldValue.SetZero()
+	if u.unmarshalValue(fallback, fieldValue, fieldPath, field) != nil {
+		return false
+	}
+	u.recordFallback(input, fieldPath, err)
+
+	return true
+}
+
+// recordFallback reports that the field at fieldPath was set from its fallback
+// because input failed to decode with err.
+func (u *Unmarshaler) recordFallback(input any, fieldPath string, err error) {
+	u.warn("mapstructure: using fallback for field", "field", fieldPath, "error", err)
+
+	if stats := u.call.stats; stats != nil {
+		stats.Fallbacks = append(stats.Fallbacks, FieldFallback{FieldPath: fieldPath, Input: input, Err: err})
+	}
+}
@@ -0,0 +1,129 @@
+package mapstructure
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_Fallback(t *testing.T) {
+	type Job struct {
+		Retries int      `schema:"retries,fallback=3"`
+		Tags    []string `schema:"tags,fallback=none"`
+		Ratio   float64  `schema:"ratio,fallback=0.5" default:"1"`
+		Level   string   `schema:"level,fallback=info,maxlen=3"`
+		Limit   int      `schema:"limit,fallback=lots"`
+		Strict  int      `schema:"strict"`
+		Wait    *int     `schema:"wait,fallback=10"`
+	}
+
+	one, wait := 1, 10
+	tests := []struct {
+		name          string
+		data          map[string]any
+		want          Job
+		wantFallbacks []string // Paths of fields set from their fallback
+		wantErr       string
+	}{
+		{
+			name: "valid input kept",
+			data: map[string]any{"retries": "5", "wait": 1},
+			want: Job{Retries: 5, Ratio: 1, Wait: &one},
+		},
+		{
+			name:          "invalid input replaced",
+			data:          map[string]any{"retries": "many", "wait": "soon"},
+			want:          Job{Retries: 3, Ratio: 1, Wait: &wait},
+			wantFallbacks: []string{"retries", "wait"},
+		},
+		{
+			name:          "fallback takes precedence over the default",
+			data:          map[string]any{"ratio": "x"},
+			want:          Job{Ratio: 0.5},
+			wantFallbacks: []string{"ratio"},
+		},
+		{
+			name: "missing key uses the default",
+			data: map[string]any{},
+			want: Job{Ratio: 1},
+		},
+		{
+			name:    "invalid fallback reports the original error",
+			data:    map[string]any{"limit": "x"},
+			wantErr: `limit: cannot convert string to int`,
+		},
+		{
+			name:    "fields without the option still fail",
+			data:    map[string]any{"strict": "x"},
+			wantErr: "strict",
+		},
+		{
+			name:    "constraints apply to the fallback",
+			data:    map[string]any{"level": []any{map[string]any{}}},
+			wantErr: "level: length 4 exceeds maximum 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Job
+			var stats DecodeStats
+			err := Unmarshal(tt.data, &got, WithStats(&stats))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			var paths []string
+			for _, fallback := range stats.Fallbacks {
+				paths = append(paths, fallback.FieldPath)
+				assert.Equal(t, tt.data[fallback.FieldPath], fallback.Input)
+				assert.Error(t, fallback.Err)
+			}
+			assert.Equal(t, tt.wantFallbacks, paths)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_FallbackDetails(t *testing.T) {
+	t.Run("invalid defaults fail", func(t *testing.T) {
+		type Config struct {
+			Port int `schema:"port,fallback=80" default:"http"`
+		}
+
+		var got Config
+		assert.ErrorContains(t, Unmarshal(map[string]any{}, &got), "port")
+	})
+
+	t.Run("normalize", func(t *testing.T) {
+		type Config struct {
+			Retries int `schema:"retries,fallback=3"`
+		}
+
+		got, err := Normalize(map[string]any{"retries": "many"}, reflect.TypeFor[Config]())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"retries": 3}, got)
+	})
+
+	t.Run("logger", func(t *testing.T) {
+		type Config struct {
+			Retries int `schema:"retries,fallback=3"`
+		}
+
+		var buf bytes.Buffer
+		u := NewDefaultUnmarshaler(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+
+		var got Config
+		require.NoError(t, u.Unmarshal(map[string]any{"retries": "many"}, &got))
+		assert.Equal(t, 3, got.Retries)
+		assert.Contains(t, buf.String(), "field=retries")
+	})
+}
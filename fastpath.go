@@ -0,0 +1,74 @@
+package mapstructure
+
+import "reflect"
+
+// setScalar stores data into rv without going through reflect.ValueOf or a converter
+// when data is a builtin scalar whose type is exactly the type of rv. It reports
+// whether the value was stored; other combinations take the generic path.
+//
+// Only identical types are handled, so the outcome always matches direct assignment
+// and converters registered for the target type are never bypassed.
+func setScalar(data any, rv reflect.Value) bool {
+	if rv.Type().PkgPath() != "" {
+		return false // Named types such as time.Duration are never identical to builtins
+	}
+
+	switch d := data.(type) {
+	case string:
+		if rv.Kind() == reflect.String {
+			rv.SetString(d)
+
+			return true
+		}
+	case bool:
+		if rv.Kind() == reflect.Bool {
+			rv.SetBool(d)
+
+			return true
+		}
+	case int:
+		if rv.Kind() == reflect.Int {
+			rv.SetInt(int64(d))
+
+			return true
+		}
+	case int64:
+		if rv.Kind() == reflect.Int64 {
+			rv.SetInt(d)
+
+			return true
+		}
+	case int32:
+		if rv.Kind() == reflect.Int32 {
+			rv.SetInt(int64(d))
+
+			return true
+		}
+	case uint:
+		if rv.Kind() == reflect.Uint {
+			rv.SetUint(uint64(d))
+
+			return true
+		}
+	case uint64:
+		if rv.Kind() == reflect.Uint64 {
+			rv.SetUint(d)
+
+			return true
+		}
+	case float64:
+		if rv.Kind() == reflect.Float64 {
+			rv.SetFloat(d)
+
+			return true
+		}
+	case float32:
+		if rv.Kind() == reflect.Float32 {
+			rv.SetFloat(float64(d))
+
+			return true
+		}
+	}
+
+	return false
+}
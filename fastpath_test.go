@@ -0,0 +1,76 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetScalar(t *testing.T) {
+	type Level string
+
+	tests := []struct {
+		name   string
+		data   any
+		target any
+		want   any
+		stored bool
+	}{
+		{name: "string", data: "x", target: new(string), want: "x", stored: true},
+		{name: "bool", data: true, target: new(bool), want: true, stored: true},
+		{name: "int", data: 42, target: new(int), want: 42, stored: true},
+		{name: "int32", data: int32(42), target: new(int32), want: int32(42), stored: true},
+		{name: "int64", data: int64(42), target: new(int64), want: int64(42), stored: true},
+		{name: "uint", data: uint(42), target: new(uint), want: uint(42), stored: true},
+		{name: "uint64", data: uint64(42), target: new(uint64), want: uint64(42), stored: true},
+		{name: "float32", data: float32(1.5), target: new(float32), want: float32(1.5), stored: true},
+		{name: "float64", data: 1.5, target: new(float64), want: 1.5, stored: true},
+		{name: "different kind", data: 42, target: new(int64), want: int64(0)},
+		{name: "float to int", data: 42.0, target: new(int), want: 0},
+		{name: "named target", data: "debug", target: new(Level), want: Level("")},
+		{name: "named duration", data: int64(5), target: new(time.Duration), want: time.Duration(0)},
+		{name: "nil", data: nil, target: new(string), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rv := reflect.ValueOf(tt.target).Elem()
+			assert.Equal(t, tt.stored, setScalar(tt.data, rv))
+			assert.Equal(t, tt.want, rv.Interface())
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_ScalarFastPath(t *testing.T) {
+	type Config struct {
+		Name    string  `schema:"name"`
+		Port    int     `schema:"port"`
+		Ratio   float64 `schema:"ratio"`
+		Enabled bool    `schema:"enabled"`
+	}
+
+	u := NewDefaultUnmarshaler()
+	data := map[string]any{"name": "app", "port": 8080, "ratio": 0.5, "enabled": true}
+
+	var result Config
+	require.NoError(t, u.Unmarshal(data, &result))
+	assert.Equal(t, Config{Name: "app", Port: 8080, Ratio: 0.5, Enabled: true}, result)
+
+	// Custom converters for non-identical types still apply.
+	custom := NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[string](): func(value any) (reflect.Value, error) {
+			return reflect.ValueOf("converted"), nil
+		},
+	}))
+	require.NoError(t, custom.Unmarshal(map[string]any{"name": 1}, &result))
+	assert.Equal(t, "converted", result.Name)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		var scalar string
+		_ = setScalar("x", reflect.ValueOf(&scalar).Elem())
+	})
+	assert.LessOrEqual(t, allocs, 1.0)
+}
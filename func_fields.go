@@ -0,0 +1,81 @@
+package mapstructure
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// FuncFieldPolicy selects how the unmarshaler treats input keys of func and chan fields.
+// Such fields are never decoded, as input data cannot provide their values, and their
+// presence in a struct does not affect decoding of its other fields.
+type FuncFieldPolicy int
+
+const (
+	// FuncFieldsSkip silently ignores input keys of func and chan fields. This is the default.
+	FuncFieldsSkip FuncFieldPolicy = iota
+
+	// FuncFieldsWarn ignores input keys of func and chan fields, logging a warning
+	// with the logger set by WithLogger, or the default slog logger without one.
+	FuncFieldsWarn
+
+	// FuncFieldsError fails with an UnsupportedTypeError when the input has a key
+	// of a func or chan field.
+	FuncFieldsError
+)
+
+// WithFuncFieldPolicy sets how the unmarshaler treats input keys of func and chan fields.
+func WithFuncFieldPolicy(policy FuncFieldPolicy) Option {
+	return func(u *Unmarshaler) {
+		u.funcPolicy = policy
+	}
+}
+
+// isFuncOrChan reports whether typ is a func or chan type.
+func isFuncOrChan(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Func || typ.Kind() == reflect.Chan
+}
+
+// promoteFuncFields returns funcFields followed by the func and chan fields promoted
+// from the embedded structs of fields, with index paths relative to the outer struct.
+func (c *StructMetadataCache) promoteFuncFields(fields, funcFields []FieldMetadata) []FieldMetadata {
+	for i := range fields {
+		if fields[i].promoted == nil {
+			continue
+		}
+
+		for _, promoted := range fields[i].promoted.funcFields {
+			promoted.Index = append(slices.Clip(fields[i].Index), promoted.Index...)
+			funcFields = append(funcFields, promoted)
+		}
+	}
+
+	return funcFields
+}
+
+// checkFuncFields applies the func field policy to the keys of dataMap that belong
+// to func and chan fields of metadata.
+func (u *Unmarshaler) checkFuncFields(dataMap map[string]any, metadata *StructMetadata, fieldPath string) error {
+	if u.funcPolicy == FuncFieldsSkip {
+		return nil
+	}
+
+	for i := range metadata.funcFields {
+		field := &metadata.funcFields[i]
+		if _, ok := dataMap[field.MapKey]; !ok || !u.fieldSelected(field) {
+			continue
+		}
+
+		fullPath := buildFieldPath(fieldPath, field.MapKey)
+		if u.funcPolicy == FuncFieldsError {
+			return NewUnsupportedTypeError(fullPath, field.Type)
+		}
+		logger := u.logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Warn("mapstructure: ignoring input for func or chan field", "field", fullPath, "type", field.Type.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package mapstructure
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type FuncFieldsBase struct {
+	Done chan struct{} `schema:"done"`
+}
+
+type funcFieldsTarget struct {
+	FuncFieldsBase
+	Name     string       `schema:"name"`
+	OnChange func(string) `schema:"on_change"`
+}
+
+func TestStructMetadataCache_FuncFields(t *testing.T) {
+	metadata := NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[funcFieldsTarget]())
+
+	for _, field := range metadata.flat {
+		assert.False(t, isFuncOrChan(field.Type), field.MapKey)
+	}
+
+	var keys []string
+	for _, field := range metadata.funcFields {
+		keys = append(keys, field.MapKey)
+	}
+	assert.Equal(t, []string{"on_change", "done"}, keys)
+
+	assert.True(t, metadata.HasKey("on_change"), "func fields remain known keys")
+	assert.Equal(t, []int{0, 0}, metadata.funcFields[1].Index)
+}
+
+func TestUnmarshaler_Unmarshal_FuncFieldPolicy(t *testing.T) {
+	data := map[string]any{"name": "app", "on_change": "ignored", "done": true}
+
+	t.Run("skip by default", func(t *testing.T) {
+		var result funcFieldsTarget
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(data, &result))
+		assert.Equal(t, "app", result.Name)
+		assert.Nil(t, result.OnChange)
+		assert.Nil(t, result.Done)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		var buf bytes.Buffer
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+		defer slog.SetDefault(previous)
+
+		var result funcFieldsTarget
+		require.NoError(t, NewDefaultUnmarshaler(WithFuncFieldPolicy(FuncFieldsWarn)).Unmarshal(data, &result))
+		assert.Equal(t, "app", result.Name)
+		assert.Contains(t, buf.String(), "field=on_change")
+		assert.Contains(t, buf.String(), "field=done")
+	})
+
+	t.Run("warn to logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+		var result funcFieldsTarget
+		u := NewDefaultUnmarshaler(WithFuncFieldPolicy(FuncFieldsWarn), WithLogger(logger))
+		require.NoError(t, u.Unmarshal(data, &result))
+		assert.Contains(t, buf.String(), "field=on_change")
+		assert.Contains(t, buf.String(), "field=done")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var result funcFieldsTarget
+		err := NewDefaultUnmarshaler(WithFuncFieldPolicy(FuncFieldsError)).Unmarshal(data, &result)
+
+		var typeErr *UnsupportedTypeError
+		require.ErrorAs(t, err, &typeErr)
+		assert.Equal(t, "on_change", typeErr.FieldPath)
+		assert.Equal(t, reflect.TypeFor[func(string)](), typeErr.Type)
+	})
+
+	t.Run("error only for present keys", func(t *testing.T) {
+		var result funcFieldsTarget
+		u := NewDefaultUnmarshaler(WithFuncFieldPolicy(FuncFieldsError))
+		require.NoError(t, u.Unmarshal(map[string]any{"name": "app"}, &result))
+		assert.Equal(t, "app", result.Name)
+	})
+}
+
+func TestUnmarshaler_UnmarshalStrings_FuncFieldPolicy(t *testing.T) {
+	type Hooks struct {
+		Name string `schema:"name"`
+		CB   func() `schema:"cb"`
+	}
+	require.True(t, NewDefaultStructMetadataCache().GetMetadata(reflect.TypeFor[Hooks]()).flatScalars)
+
+	data := map[string]string{"name": "app", "cb": "ignored"}
+
+	t.Run("warn", func(t *testing.T) {
+		var buf bytes.Buffer
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+		defer slog.SetDefault(previous)
+
+		var result Hooks
+		require.NoError(t, NewDefaultUnmarshaler(WithFuncFieldPolicy(FuncFieldsWarn)).UnmarshalStrings(data, &result))
+		assert.Equal(t, "app", result.Name)
+		assert.Contains(t, buf.String(), "field=cb")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var result Hooks
+		err := NewDefaultUnmarshaler(WithFuncFieldPolicy(FuncFieldsError)).UnmarshalStrings(data, &result)
+
+		var typeErr *UnsupportedTypeError
+		require.ErrorAs(t, err, &typeErr)
+		assert.Equal(t, "cb", typeErr.FieldPath)
+	})
+}
@@ -0,0 +1,115 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+)
+
+// FuzzTarget is the representative struct decoded by FuzzUnmarshal. It covers scalars,
+// formats, pointers, slices, maps, nested and self-referential structs, embedded structs,
+// fields of type any and the built-in converters for times, network addresses and bytes.
+type FuzzTarget struct {
+	FuzzEmbedded
+
+	String   string            `schema:"string"`
+	Int      int               `schema:"int" default:"7"`
+	Int8     int8              `schema:"int8"`
+	Uint     uint              `schema:"uint"`
+	Float    float64           `schema:"float"`
+	Bool     bool              `schema:"bool"`
+	Bytes    []byte            `schema:"bytes"`
+	Size     int64             `schema:"size,format=bytes"`
+	Ratio    float64           `schema:"ratio,format=percent"`
+	Tags     []string          `schema:"tags,split=,"`
+	Any      any               `schema:"any"`
+	Time     time.Time         `schema:"time"`
+	Duration time.Duration     `schema:"duration"`
+	Date     Date              `schema:"date"`
+	IP       net.IP            `schema:"ip"`
+	Labels   map[string]string `schema:"labels"`
+	Matrix   [][]int           `schema:"matrix"`
+	Ptr      *int              `schema:"ptr"`
+	PtrPtr   **string          `schema:"ptrptr"`
+	Child    *FuzzTarget       `schema:"child"`
+	Children []FuzzTarget      `schema:"children"`
+	Name     string            `schema:"name,trim,minlen=1,maxlen=64" default:"anonymous"`
+}
+
+// FuzzEmbedded is embedded in FuzzTarget to cover promoted fields.
+type FuzzEmbedded struct {
+	ID string `schema:"id"`
+}
+
+// FuzzFlatTarget is the representative struct decoded by FuzzUnmarshalStrings. Its
+// scalar fields take the string map fast path of UnmarshalStrings.
+type FuzzFlatTarget struct {
+	Host    string        `schema:"HOST" default:"localhost"`
+	Port    uint16        `schema:"PORT"`
+	Debug   bool          `schema:"DEBUG"`
+	Ratio   float32       `schema:"RATIO,format=percent"`
+	Size    int64         `schema:"SIZE,format=bytes"`
+	Timeout time.Duration `schema:"TIMEOUT"`
+	Level   string        `schema:"LEVEL,lower,maxlen=8"`
+}
+
+// fuzzMaxDepth bounds the nesting decoded by FuzzUnmarshal.
+const fuzzMaxDepth = 64
+
+// fuzzUnmarshaler is the unmarshaler used by FuzzUnmarshal.
+var fuzzUnmarshaler = NewDefaultUnmarshaler(WithMaxDepth(fuzzMaxDepth))
+
+// FuzzUnmarshal decodes data, a JSON object, into a FuzzTarget. It follows the go-fuzz
+// conventions: it returns -1 for data that is not a JSON object, 1 when data decoded
+// and 0 when decoding failed with an error. Decoding must never panic, whatever the
+// input; fuzzers report a panic as a failure.
+func FuzzUnmarshal(data []byte) int {
+	var input map[string]any
+	if err := json.Unmarshal(data, &input); err != nil {
+		return -1
+	}
+
+	var target FuzzTarget
+	if err := fuzzUnmarshaler.Unmarshal(input, &target); err != nil {
+		return 0
+	}
+
+	return 1
+}
+
+// FuzzUnmarshalStrings decodes data, a JSON object of strings, into a FuzzFlatTarget
+// with UnmarshalStrings. It returns -1, 0 or 1 like FuzzUnmarshal.
+func FuzzUnmarshalStrings(data []byte) int {
+	var input map[string]string
+	if err := json.Unmarshal(data, &input); err != nil {
+		return -1
+	}
+
+	var target FuzzFlatTarget
+	if err := fuzzUnmarshaler.UnmarshalStrings(input, &target); err != nil {
+		return 0
+	}
+
+	return 1
+}
+
+// FuzzCorpus returns seed inputs for FuzzUnmarshal, covering every field of FuzzTarget
+// with valid and invalid values, so fuzzers start from interesting input.
+// Seeds made of strings only also exercise FuzzUnmarshalStrings.
+func FuzzCorpus() [][]byte {
+	return [][]byte{
+		[]byte(`{}`),
+		[]byte(`{"string":"s","int":1,"int8":127,"uint":1,"float":1.5,"bool":true,"id":"x"}`),
+		[]byte(`{"int":"42","int8":"300","uint":-1,"float":"NaN","bool":"yes"}`),
+		[]byte(`{"bytes":"aGVsbG8=","size":"1.5GiB","ratio":"75%","tags":"a,b,,c"}`),
+		[]byte(`{"size":"9EiB","ratio":"%","tags":["a",1,null]}`),
+		[]byte(`{"any":{"a":[1,"b",null,{"c":true}]},"labels":{"a":"b","c":1}}`),
+		[]byte(`{"time":"2024-01-02T03:04:05Z","duration":"1h2m","date":"2024-02-30","ip":"::1"}`),
+		[]byte(`{"time":1700000000,"duration":-1,"date":20240101,"ip":"999.1.1.1"}`),
+		[]byte(`{"matrix":[[1,2],[3,"x"],null,4],"ptr":1,"ptrptr":"p"}`),
+		[]byte(`{"child":{"child":{"child":{"name":" deep "}}},"children":[{"int":1},null,"x"]}`),
+		[]byte(`{"children":{"0":{}},"child":[],"labels":[],"matrix":{}}`),
+		[]byte(`{"name":"","string":{},"int":[],"bool":{},"time":[],"ip":{}}`),
+		[]byte(`{"HOST":" h ","PORT":"65536","DEBUG":"1","RATIO":"1e40%","SIZE":"-1KB","TIMEOUT":"1x","LEVEL":"DEBUGGING"}`),
+	}
+}
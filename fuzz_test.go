@@ -0,0 +1,73 @@
+package mapstructure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func FuzzUnmarshalJSON(f *testing.F) {
+	for _, seed := range FuzzCorpus() {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		FuzzUnmarshal(data)
+	})
+}
+
+func FuzzUnmarshalStringsJSON(f *testing.F) {
+	for _, seed := range FuzzCorpus() {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		FuzzUnmarshalStrings(data)
+	})
+}
+
+func TestFuzzUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{name: "not json", data: `{`, want: -1},
+		{name: "not an object", data: `[1,2]`, want: -1},
+		{name: "valid", data: `{"string":"s","child":{"int":"1"}}`, want: 1},
+		{name: "invalid value", data: `{"int":"x"}`, want: 0},
+		{name: "too deep", data: strings.Repeat(`{"child":`, fuzzMaxDepth+1) + `{}` + strings.Repeat(`}`, fuzzMaxDepth+1), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FuzzUnmarshal([]byte(tt.data)))
+		})
+	}
+}
+
+func TestFuzzUnmarshalStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{name: "not strings", data: `{"PORT":1}`, want: -1},
+		{name: "valid", data: `{"PORT":"8080","LEVEL":"INFO"}`, want: 1},
+		{name: "out of range", data: `{"PORT":"65536"}`, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FuzzUnmarshalStrings([]byte(tt.data)))
+		})
+	}
+}
+
+func TestFuzzCorpus(t *testing.T) {
+	for _, seed := range FuzzCorpus() {
+		assert.NotPanics(t, func() { FuzzUnmarshal(seed) }, string(seed))
+		assert.NotEqual(t, -1, FuzzUnmarshal(seed), "seed must be a JSON object: %s", seed)
+	}
+}
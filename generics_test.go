@@ -0,0 +1,117 @@
+package mapstructure
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type page[T any] struct {
+	Items []T `schema:"items"`
+	Total int `schema:"total"`
+	Next  *string
+}
+
+type result[T any] struct {
+	Data  T       `schema:"data"`
+	Error *string `schema:"error"`
+}
+
+type pagedUser struct {
+	ID   int    `schema:"id"`
+	Name string `schema:"name" default:"anonymous"`
+}
+
+func TestUnmarshaler_Unmarshal_GenericContainers(t *testing.T) {
+	t.Run("page of structs", func(t *testing.T) {
+		data := map[string]any{
+			"items": []any{
+				map[string]any{"id": 1, "name": "alice"},
+				map[string]any{"id": "2"},
+			},
+			"total": 2.0,
+		}
+
+		var got page[pagedUser]
+		require.NoError(t, Unmarshal(data, &got))
+		assert.Equal(t, page[pagedUser]{
+			Items: []pagedUser{{ID: 1, Name: "alice"}, {ID: 2, Name: "anonymous"}},
+			Total: 2,
+		}, got)
+	})
+
+	t.Run("type parameter converters", func(t *testing.T) {
+		var addrs page[netip.Addr]
+		require.NoError(t, Unmarshal(map[string]any{"items": []any{"10.0.0.1", "::1"}}, &addrs))
+		assert.Equal(t, []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")}, addrs.Items)
+
+		var dates result[Date]
+		require.NoError(t, Unmarshal(map[string]any{"data": "2024-02-29"}, &dates))
+		assert.Equal(t, Date{Year: 2024, Month: 2, Day: 29}, dates.Data)
+	})
+
+	t.Run("custom converter for type parameter", func(t *testing.T) {
+		type Cents int64
+
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry(map[reflect.Type]Converter{
+			reflect.TypeFor[Cents](): func(v any) (reflect.Value, error) {
+				f, err := convertFloat64(v)
+				if err != nil {
+					return reflect.Value{}, err
+				}
+
+				return reflect.ValueOf(Cents(f.Float()*100 + 0.5)), nil
+			},
+		}))
+
+		var got page[Cents]
+		require.NoError(t, u.Unmarshal(map[string]any{"items": []any{"1.25", 3}}, &got))
+		assert.Equal(t, []Cents{125, 300}, got.Items)
+	})
+
+	t.Run("nested instantiations", func(t *testing.T) {
+		data := map[string]any{
+			"data": map[string]any{
+				"items": []any{map[string]any{"items": []any{1, 2}, "total": 2}},
+				"total": 1,
+			},
+		}
+
+		got, err := NewDecoder[result[page[page[int]]]](nil).Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Data.Total)
+		require.Len(t, got.Data.Items, 1)
+		assert.Equal(t, []int{1, 2}, got.Data.Items[0].Items)
+	})
+
+	t.Run("pointer type parameter", func(t *testing.T) {
+		msg := "not found"
+		var got result[*pagedUser]
+		require.NoError(t, Unmarshal(map[string]any{"data": nil, "error": msg}, &got))
+		assert.Nil(t, got.Data)
+		require.NotNil(t, got.Error)
+		assert.Equal(t, msg, *got.Error)
+	})
+
+	t.Run("instantiations are cached separately", func(t *testing.T) {
+		cache := NewDefaultStructMetadataCache()
+		ints := cache.GetMetadata(reflect.TypeFor[page[int]]())
+		strs := cache.GetMetadata(reflect.TypeFor[page[string]]())
+
+		assert.NotSame(t, ints, strs)
+		assert.Equal(t, reflect.TypeFor[[]int](), ints.Fields[0].Type)
+		assert.Equal(t, reflect.TypeFor[[]string](), strs.Fields[0].Type)
+	})
+
+	t.Run("conversion errors carry element paths", func(t *testing.T) {
+		var got page[pagedUser]
+		err := Unmarshal(map[string]any{"items": []any{map[string]any{"id": "x"}}}, &got)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "items[0].id", convErr.FieldPath)
+	})
+}
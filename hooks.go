@@ -0,0 +1,43 @@
+package mapstructure
+
+import (
+	"reflect"
+	"slices"
+)
+
+// DecodeHook transforms an input value before it is decoded into a value of type to.
+// from is the type of value, nil for nil input. A hook returns value unchanged when it
+// does not apply, so generic transforms such as splitting strings into slices, mapping
+// names to enum values or expanding environment variables work for every target type
+// without registering one converter per type. Returning an error fails decoding of the
+// value with a ConversionError wrapping it.
+type DecodeHook func(value any, from, to reflect.Type) (any, error)
+
+// WithHooks returns a new unmarshaler extending u with the given decode hooks, which run
+// in order, after those already registered, on every value decoded: the top-level map,
+// struct fields, slice and map elements, and defaults. Each hook receives the value
+// returned by the previous one, and the last result is decoded as usual, by assignment,
+// converters or structural decoding. Hooks see tree nodes already converted to plain
+// values. u is left unchanged.
+func (u *Unmarshaler) WithHooks(hooks ...DecodeHook) *Unmarshaler {
+	configured := *u
+	configured.hooks = append(slices.Clip(u.hooks), hooks...)
+	if configured.defaults != nil {
+		configured.defaults = &defaultCache{} // Prepared defaults went through the previous hooks
+	}
+
+	return &configured
+}
+
+// runHooks passes data through the decode hooks for a value of type typ.
+func (u *Unmarshaler) runHooks(data any, typ reflect.Type, fieldPath string) (any, error) {
+	for _, hook := range u.hooks {
+		hooked, err := hook(data, reflect.TypeOf(data), typ)
+		if err != nil {
+			return nil, NewConversionError(fieldPath, data, typ, err)
+		}
+		data = hooked
+	}
+
+	return data, nil
+}
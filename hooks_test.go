@@ -0,0 +1,229 @@
+package mapstructure
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookColor int
+
+// splitHook splits comma-separated strings decoded into string slices.
+func splitHook(value any, from, to reflect.Type) (any, error) {
+	if s, ok := value.(string); ok && to == reflect.TypeFor[[]string]() {
+		return strings.Split(s, ","), nil
+	}
+
+	return value, nil
+}
+
+// colorHook maps color names to hookColor values.
+func colorHook(value any, from, to reflect.Type) (any, error) {
+	if from != reflect.TypeFor[string]() || to != reflect.TypeFor[hookColor]() {
+		return value, nil
+	}
+
+	switch value {
+	case "red":
+		return hookColor(1), nil
+	case "blue":
+		return hookColor(2), nil
+	default:
+		return nil, errors.New("unknown color")
+	}
+}
+
+// envHook expands environment variables in strings.
+func envHook(value any, from, to reflect.Type) (any, error) {
+	if s, ok := value.(string); ok {
+		return os.ExpandEnv(s), nil
+	}
+
+	return value, nil
+}
+
+func TestUnmarshaler_WithHooks(t *testing.T) {
+	type Theme struct {
+		Primary hookColor   `schema:"primary"`
+		Palette []hookColor `schema:"palette"`
+	}
+	type Config struct {
+		Tags  []string `schema:"tags"`
+		Theme Theme    `schema:"theme"`
+		Home  string   `schema:"home" default:"$HOOK_HOME/app"`
+		Count int      `schema:"count"`
+	}
+
+	t.Setenv("HOOK_HOME", "/srv")
+	u := NewDefaultUnmarshaler().WithHooks(envHook, splitHook, colorHook)
+
+	tests := []struct {
+		name    string
+		data    map[string]any
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "values transformed at every level",
+			data: map[string]any{
+				"tags":  "a,$HOOK_HOME",
+				"theme": map[string]any{"primary": "red", "palette": []any{"blue", "red"}},
+				"count": "3",
+			},
+			want: Config{
+				Tags:  []string{"a", "/srv"},
+				Theme: Theme{Primary: 1, Palette: []hookColor{2, 1}},
+				Home:  "/srv/app",
+				Count: 3,
+			},
+		},
+		{
+			name: "values hooks do not apply to",
+			data: map[string]any{"tags": []any{"a,b"}, "home": 1},
+			want: Config{Tags: []string{"a,b"}, Home: "1"},
+		},
+		{
+			name:    "hook errors",
+			data:    map[string]any{"theme": map[string]any{"palette": []any{"red", "green"}}},
+			wantErr: "theme.palette[1]: cannot convert string to mapstructure.hookColor: unknown color",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Config
+			err := u.Unmarshal(tt.data, &got)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				require.ErrorAs(t, err, new(*ConversionError))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshaler_WithHooksDetails(t *testing.T) {
+	type Config struct {
+		Name  string `schema:"name"`
+		Count *int   `schema:"count"`
+	}
+
+	t.Run("chained in order", func(t *testing.T) {
+		var seen []string
+		record := func(tag string) DecodeHook {
+			return func(value any, from, to reflect.Type) (any, error) {
+				if s, ok := value.(string); ok {
+					seen = append(seen, tag+":"+s)
+
+					return s + tag, nil
+				}
+
+				return value, nil
+			}
+		}
+
+		var got Config
+		require.NoError(t, NewDefaultUnmarshaler().WithHooks(record("1")).WithHooks(record("2")).
+			Unmarshal(map[string]any{"name": "a"}, &got))
+		assert.Equal(t, "a12", got.Name)
+		assert.Equal(t, []string{"1:a", "2:a1"}, seen)
+	})
+
+	t.Run("types passed", func(t *testing.T) {
+		var calls []string
+		hook := func(value any, from, to reflect.Type) (any, error) {
+			calls = append(calls, typeString(from)+" → "+to.String())
+
+			return value, nil
+		}
+
+		var got Config
+		require.NoError(t, NewDefaultUnmarshaler().WithHooks(hook).Unmarshal(map[string]any{"count": nil}, &got))
+		assert.Equal(t, []string{"map[string]interface {} → mapstructure.Config", "<nil> → *int"}, calls)
+	})
+
+	t.Run("typed decoders", func(t *testing.T) {
+		hook := func(value any, from, to reflect.Type) (any, error) {
+			if data, ok := value.(map[string]any); ok && to == reflect.TypeFor[Config]() {
+				return map[string]any{"name": "hooked", "count": data["count"]}, nil
+			}
+
+			return value, nil
+		}
+		u := NewDefaultUnmarshaler().WithHooks(hook)
+
+		var want Config
+		require.NoError(t, u.Unmarshal(map[string]any{"count": 1}, &want))
+		assert.Equal(t, "hooked", want.Name)
+
+		got, err := NewDecoder[Config](u).Decode(map[string]any{"count": 1})
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("derived unmarshalers independent", func(t *testing.T) {
+		base := NewDefaultUnmarshaler().WithHooks(envHook)
+		upper := base.WithHooks(func(value any, from, to reflect.Type) (any, error) {
+			if s, ok := value.(string); ok {
+				return strings.ToUpper(s), nil
+			}
+
+			return value, nil
+		})
+
+		var got Config
+		require.NoError(t, base.Unmarshal(map[string]any{"name": "a"}, &got))
+		assert.Equal(t, "a", got.Name)
+		require.NoError(t, upper.Unmarshal(map[string]any{"name": "a"}, &got))
+		assert.Equal(t, "A", got.Name)
+	})
+
+	t.Run("prepared defaults", func(t *testing.T) {
+		type Defaults struct {
+			Name string `schema:"name" default:"a"`
+		}
+
+		base := NewDefaultUnmarshaler(WithPreparedDefaults())
+		var got Defaults
+		require.NoError(t, base.Unmarshal(map[string]any{}, &got))
+
+		upper := base.WithHooks(func(value any, from, to reflect.Type) (any, error) {
+			if s, ok := value.(string); ok {
+				return strings.ToUpper(s), nil
+			}
+
+			return value, nil
+		})
+		require.NoError(t, upper.Unmarshal(map[string]any{}, &got))
+		assert.Equal(t, "A", got.Name)
+	})
+
+	t.Run("string maps", func(t *testing.T) {
+		type Flat struct {
+			Home string `schema:"home"`
+		}
+
+		t.Setenv("HOOK_HOME", "/srv")
+		var got Flat
+		require.NoError(t, NewDefaultUnmarshaler().WithHooks(envHook).UnmarshalStrings(map[string]string{"home": "$HOOK_HOME"}, &got))
+		assert.Equal(t, "/srv", got.Home)
+	})
+}
+
+// typeString returns the name of typ, "<nil>" for nil.
+func typeString(typ reflect.Type) string {
+	if typ == nil {
+		return "<nil>"
+	}
+
+	return typ.String()
+}
@@ -0,0 +1,121 @@
+// Package inspect writes human-readable reports of how mapstructure decodes a struct
+// type: the map key of every field, including promoted ones, with its Go field path,
+// type, default and tag options, followed by the struct's options and the fields that
+// cannot be decoded. It backs the mapstructure-inspect command and can be called from
+// tests or debug endpoints to answer "why isn't this key mapping".
+package inspect
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/talav/mapstructure"
+)
+
+// Write writes the report of the struct type typ, or pointer to one, as decoded by u
+// with the metadata of cache, which should be the cache u was created with.
+func Write(w io.Writer, typ reflect.Type, cache *mapstructure.StructMetadataCache, u *mapstructure.Unmarshaler) error {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("inspect: %v is not a struct type", typ)
+	}
+
+	metadata := cache.GetMetadata(typ)
+	fields := metadata.ResolvedFields()
+	withDocs := slices.ContainsFunc(fields, func(field *mapstructure.FieldMetadata) bool { return field.Doc != "" })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v (tag %q)\n\n", typ, u.Config().TagName)
+
+	var rows strings.Builder
+	table := tabwriter.NewWriter(&rows, 0, 4, 2, ' ', 0)
+	header := "KEY\tFIELD\tTYPE\tDEFAULT\tOPTIONS"
+	if withDocs {
+		header += "\tDOC"
+	}
+	fmt.Fprintln(table, header)
+	for _, field := range fields {
+		row := strings.Join([]string{
+			field.MapKey, fieldPath(typ, field.Index), field.Type.String(), defaultValue(field), options(field),
+		}, "\t")
+		if withDocs {
+			row += "\t" + firstLine(field.Doc)
+		}
+		fmt.Fprintln(table, row)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	for line := range strings.Lines(rows.String()) {
+		b.WriteString(strings.TrimRight(line, " \n") + "\n") // Empty last cells leave padding
+	}
+
+	fmt.Fprintf(&b, "\nstrict: %t\n", metadata.Options.Strict)
+	if err := metadata.Err(); err != nil {
+		fmt.Fprintf(&b, "tag error: %v\n", err)
+	}
+
+	unsupported := u.UnsupportedFields(typ)
+	if len(unsupported) == 0 {
+		b.WriteString("unsupported fields: none\n")
+	} else {
+		b.WriteString("unsupported fields:\n")
+		for _, err := range unsupported {
+			fmt.Fprintf(&b, "  %s: %v\n", err.FieldPath, err.Type)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// fieldPath returns the dotted Go path of the field of typ at index, e.g. "Audit.By"
+// for a field promoted from the embedded struct Audit.
+func fieldPath(typ reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i := range index {
+		names[i] = typ.FieldByIndex(index[:i+1]).Name
+	}
+
+	return strings.Join(names, ".")
+}
+
+// defaultValue returns the raw default of field, or "-" when it has none.
+func defaultValue(field *mapstructure.FieldMetadata) string {
+	if field.Default == nil {
+		return "-"
+	}
+
+	return *field.Default
+}
+
+// options returns the tag options of field in key order, as flags or key=value pairs.
+func options(field *mapstructure.FieldMetadata) string {
+	names := make([]string, 0, len(field.Options))
+	for name := range field.Options {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for i, name := range names {
+		if value := field.Options[name]; value != "" {
+			names[i] = name + "=" + value
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// firstLine returns the first line of doc.
+func firstLine(doc string) string {
+	line, _, _ := strings.Cut(doc, "\n")
+
+	return line
+}
@@ -0,0 +1,94 @@
+package inspect
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/talav/mapstructure"
+)
+
+type Audit struct {
+	By   string `schema:"by" default:"system"`
+	Name string `schema:"name"`
+}
+
+type Server struct {
+	_ struct{} `schema:",strict"`
+	*Audit
+	Name    string         `schema:"name,upper"`
+	Port    int            `schema:"port" default:"8080"`
+	Tags    []string       `schema:"tags,split=;"`
+	Handler chan int       `schema:"handler"`
+	Limits  map[string]int `schema:"limits"`
+	Pair    [2]int         `schema:"pair"`
+}
+
+type BadTag struct {
+	Name string `schema:"name,split='"`
+}
+
+func TestWrite(t *testing.T) {
+	cache := mapstructure.NewDefaultStructMetadataCache()
+	u := mapstructure.NewUnmarshaler(cache, mapstructure.NewDefaultConverterRegistry())
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, reflect.TypeFor[*Server](), cache, u))
+
+	assert.Equal(t, `inspect.Server (tag "schema")
+
+KEY     FIELD     TYPE            DEFAULT  OPTIONS
+by      Audit.By  string          system
+name    Name      string          -        upper
+port    Port      int             8080
+tags    Tags      []string        -        split=;
+limits  Limits    map[string]int  -
+pair    Pair      [2]int          -
+
+strict: true
+unsupported fields:
+  pair: [2]int
+`, buf.String())
+}
+
+func TestWrite_Docs(t *testing.T) {
+	pkg := reflect.TypeFor[Audit]().PkgPath()
+	cache := mapstructure.NewStructMetadataCache("schema", "default", mapstructure.WithFieldDocs(mapstructure.FieldDocs{
+		pkg + ".Audit": {"By": "Who made the change.\nDefaults to the system user."},
+	}))
+	u := mapstructure.NewUnmarshaler(cache, mapstructure.NewDefaultConverterRegistry())
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, reflect.TypeFor[Audit](), cache, u))
+
+	assert.Equal(t, `inspect.Audit (tag "schema")
+
+KEY   FIELD  TYPE    DEFAULT  OPTIONS  DOC
+by    By     string  system            Who made the change.
+name  Name   string  -
+
+strict: false
+unsupported fields: none
+`, buf.String())
+}
+
+func TestWrite_TagError(t *testing.T) {
+	cache := mapstructure.NewDefaultStructMetadataCache()
+	u := mapstructure.NewUnmarshaler(cache, mapstructure.NewDefaultConverterRegistry())
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, reflect.TypeFor[BadTag](), cache, u))
+	assert.Contains(t, buf.String(), "tag error: ")
+}
+
+func TestWrite_NotStruct(t *testing.T) {
+	cache := mapstructure.NewDefaultStructMetadataCache()
+	u := mapstructure.NewUnmarshaler(cache, mapstructure.NewDefaultConverterRegistry())
+
+	err := Write(&bytes.Buffer{}, reflect.TypeFor[map[string]any](), cache, u)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a struct type")
+}
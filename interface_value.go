@@ -0,0 +1,45 @@
+package mapstructure
+
+import "reflect"
+
+// unmarshalInterfaceValue decodes map input into the value already held by rv, an
+// interface, when it is a struct or a non-nil pointer to a struct decoded field by
+// field rather than by a converter: pointed-to structs
+// are updated in place, struct values are copied, updated and stored back. This lets
+// pre-seeded values with pluggable sections, e.g. a Storage interface holding a
+// *S3Config, be completed from input. It reports false for other values and input,
+// which are decoded as usual.
+func (u *Unmarshaler) unmarshalInterfaceValue(data any, rv reflect.Value, fieldPath string, field *FieldMetadata) (bool, error) {
+	if rv.IsNil() {
+		return false, nil
+	}
+	if _, ok := data.(map[string]any); !ok {
+		return false, nil
+	}
+
+	current := rv.Elem()
+	switch {
+	case current.Kind() == reflect.Ptr && !current.IsNil() && u.decodableStruct(current.Type().Elem()):
+		return true, u.unmarshalValue(data, current.Elem(), fieldPath, field)
+	case u.decodableStruct(current.Type()):
+		updated := reflect.New(current.Type()).Elem()
+		updated.Set(current)
+		pendingRefs := u.pendingRefCount()
+		if err := u.unmarshalValue(data, updated, fieldPath, field); err != nil {
+			return true, err
+		}
+		if err := u.checkCopiedRefs(pendingRefs); err != nil {
+			return true, err
+		}
+		rv.Set(updated)
+
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// decodableStruct reports whether typ is a struct decoded from maps field by field.
+func (u *Unmarshaler) decodableStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && !u.hasConverter(typ)
+}
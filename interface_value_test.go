@@ -0,0 +1,101 @@
+package mapstructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storageSection interface {
+	Kind() string
+}
+
+type s3Storage struct {
+	Bucket string `schema:"bucket"`
+	Region string `schema:"region"`
+}
+
+func (s3Storage) Kind() string { return "s3" }
+
+type diskStorage struct {
+	Path string `schema:"path"`
+}
+
+func (*diskStorage) Kind() string { return "disk" }
+
+func TestUnmarshaler_Unmarshal_InterfaceValue(t *testing.T) {
+	type Config struct {
+		Name    string         `schema:"name"`
+		Storage storageSection `schema:"storage"`
+		Extra   any            `schema:"extra"`
+	}
+
+	t.Run("pointer updated in place", func(t *testing.T) {
+		disk := &diskStorage{Path: "/tmp"}
+		config := Config{Storage: disk}
+
+		require.NoError(t, Unmarshal(map[string]any{"storage": map[string]any{"path": "/var/data"}}, &config))
+		assert.Same(t, disk, config.Storage)
+		assert.Equal(t, "/var/data", disk.Path)
+	})
+
+	t.Run("struct value replaced by updated copy", func(t *testing.T) {
+		config := Config{Storage: s3Storage{Bucket: "old", Region: "eu-west-1"}}
+
+		require.NoError(t, Unmarshal(map[string]any{"storage": map[string]any{"bucket": "new"}}, &config))
+		assert.Equal(t, s3Storage{Bucket: "new", Region: "eu-west-1"}, config.Storage)
+	})
+
+	t.Run("empty interface holding a struct pointer", func(t *testing.T) {
+		disk := &diskStorage{}
+		config := Config{Extra: disk}
+
+		require.NoError(t, Unmarshal(map[string]any{"extra": map[string]any{"path": "/srv"}}, &config))
+		assert.Same(t, disk, config.Extra)
+		assert.Equal(t, "/srv", disk.Path)
+	})
+
+	t.Run("errors carry the field path", func(t *testing.T) {
+		type Limits struct {
+			Max int `schema:"max"`
+		}
+		config := Config{Extra: &Limits{}}
+
+		err := Unmarshal(map[string]any{"extra": map[string]any{"max": "many"}}, &config)
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "extra.max", convErr.FieldPath)
+	})
+
+	t.Run("other values decode as usual", func(t *testing.T) {
+		now := time.Now()
+		tests := []struct {
+			name  string
+			extra any
+			input any
+		}{
+			{name: "nil interface", extra: nil, input: map[string]any{"path": "/srv"}},
+			{name: "non-map input", extra: &diskStorage{}, input: "replaced"},
+			{name: "map value", extra: map[string]any{"a": 1}, input: map[string]any{"b": 2}},
+			{name: "struct with converter", extra: now, input: map[string]any{"b": 2}},
+			{name: "nil pointer", extra: (*diskStorage)(nil), input: map[string]any{"path": "/srv"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				config := Config{Extra: tt.extra}
+				require.NoError(t, Unmarshal(map[string]any{"extra": tt.input}, &config))
+				assert.Equal(t, tt.input, config.Extra)
+			})
+		}
+	})
+
+	t.Run("nil interface without assignable input fails", func(t *testing.T) {
+		var config Config
+
+		err := Unmarshal(map[string]any{"storage": map[string]any{"path": "/srv"}}, &config)
+		require.Error(t, err)
+	})
+}
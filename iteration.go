@@ -0,0 +1,44 @@
+package mapstructure
+
+// IterationStrategy selects how struct fields are matched with input keys.
+type IterationStrategy int
+
+const (
+	// IterateAuto visits every field, unless the struct has many times more fields
+	// than the input has keys, in which case the keys are looked up. This is the default.
+	IterateAuto IterationStrategy = iota
+
+	// IterateFields visits every field of the struct and looks its key up in the input.
+	// It suits dense inputs that set most fields.
+	IterateFields
+
+	// IterateKeys looks up the field of every input key, visiting only those fields
+	// and the fields that apply without a key (embedded structs and defaults).
+	// It suits sparse inputs, such as partial updates of large structs.
+	IterateKeys
+)
+
+// sparseRatio is how many times more fields than input keys a struct must have for
+// IterateAuto to look the keys up.
+const sparseRatio = 4
+
+// WithIterationStrategy sets how the unmarshaler matches struct fields with input keys.
+// Both strategies decode identically, in field order; they differ only in speed.
+func WithIterationStrategy(strategy IterationStrategy) Option {
+	return func(u *Unmarshaler) {
+		u.iteration = strategy
+	}
+}
+
+// iterateKeys reports whether unmarshalFields should look up the keys of an input
+// with keys entries for a struct with fields flattened fields.
+func (u *Unmarshaler) iterateKeys(keys, fields int) bool {
+	switch u.iteration {
+	case IterateFields:
+		return false
+	case IterateKeys:
+		return true
+	default:
+		return keys*sparseRatio < fields
+	}
+}
@@ -0,0 +1,62 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_IterateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy IterationStrategy
+		keys     int
+		fields   int
+		want     bool
+	}{
+		{name: "auto dense", strategy: IterateAuto, keys: 5, fields: 10, want: false},
+		{name: "auto sparse", strategy: IterateAuto, keys: 2, fields: 10, want: true},
+		{name: "auto threshold", strategy: IterateAuto, keys: 3, fields: 12, want: false},
+		{name: "fields", strategy: IterateFields, keys: 1, fields: 100, want: false},
+		{name: "keys", strategy: IterateKeys, keys: 100, fields: 1, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewDefaultUnmarshaler(WithIterationStrategy(tt.strategy))
+			assert.Equal(t, tt.want, u.iterateKeys(tt.keys, tt.fields))
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_IterationStrategies(t *testing.T) {
+	type Base struct {
+		ID string `schema:"id"`
+	}
+	type Config struct {
+		Base
+		Name    string   `schema:"name" default:"app"`
+		Port    int      `schema:"port"`
+		Tags    []string `schema:"tags"`
+		Admin   string   `schema:"admin,groups=admin"`
+		Address string   `schema:"address" default:"{{.Name}}:{{.Port}}"`
+	}
+
+	inputs := []map[string]any{
+		{},
+		{"port": 8080},
+		{"id": "1", "name": "api", "port": 80, "tags": []any{"a"}, "admin": "root"},
+		{"Base": map[string]any{"id": "2"}, "unknown": true},
+	}
+
+	for _, data := range inputs {
+		var byFields, byKeys Config
+		errFields := NewDefaultUnmarshaler(WithIterationStrategy(IterateFields)).Unmarshal(data, &byFields, WithGroups("admin"))
+		errKeys := NewDefaultUnmarshaler(WithIterationStrategy(IterateKeys)).Unmarshal(data, &byKeys, WithGroups("admin"))
+
+		require.NoError(t, errFields)
+		require.NoError(t, errKeys)
+		assert.Equal(t, byFields, byKeys, "input %v", data)
+	}
+}
@@ -0,0 +1,20 @@
+package mapstructure
+
+import "log/slog"
+
+// WithLogger sets the logger receiving the warnings of the unmarshaler: input ignored
+// under FuncFieldsWarn and fields set from their "fallback" tag option. Without a logger,
+// FuncFieldsWarn warnings go to the default slog logger and fallbacks are not logged;
+// use WithStats to collect fallbacks instead.
+func WithLogger(logger *slog.Logger) Option {
+	return func(u *Unmarshaler) {
+		u.logger = logger
+	}
+}
+
+// warn logs a warning with the logger set by WithLogger, if any.
+func (u *Unmarshaler) warn(msg string, args ...any) {
+	if u.logger != nil {
+		u.logger.Warn(msg, args...)
+	}
+}
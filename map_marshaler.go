@@ -0,0 +1,68 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MapMarshaler is implemented by types that marshal themselves to a map, such as value
+// objects whose wire shape differs from their fields. The map is used as it is.
+type MapMarshaler interface {
+	MarshalMap() (map[string]any, error)
+}
+
+// MapValueMarshaler is implemented by types that marshal themselves to a value of their
+// choice, such as a string or a number. The value is used as it is.
+type MapValueMarshaler interface {
+	MarshalMapValue() (any, error)
+}
+
+// marshalOverride returns the result of the MarshalMap or MarshalMapValue method of rv,
+// or of its address for pointer receivers, and false when rv has neither. MarshalMap
+// takes precedence when both are implemented.
+func marshalOverride(rv reflect.Value) (any, bool, error) {
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface || !rv.CanInterface() {
+		return nil, false, nil
+	}
+
+	candidates := []any{rv.Interface()}
+	if rv.CanAddr() {
+		candidates = append(candidates, rv.Addr().Interface())
+	}
+
+	for _, v := range candidates {
+		if marshaler, ok := v.(MapMarshaler); ok {
+			m, err := marshaler.MarshalMap()
+
+			return m, true, err
+		}
+	}
+	for _, v := range candidates {
+		if marshaler, ok := v.(MapValueMarshaler); ok {
+			value, err := marshaler.MarshalMapValue()
+
+			return value, true, err
+		}
+	}
+
+	return nil, false, nil
+}
+
+// marshalRoot returns the map rv, the value passed to Marshal, marshals itself to with
+// MarshalMap or MarshalMapValue, and false when it implements neither.
+func marshalRoot(rv reflect.Value) (map[string]any, bool, error) {
+	value, ok, err := marshalOverride(rv)
+	if !ok {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, NewConversionError("", rv.Interface(), rv.Type(), err)
+	}
+
+	result, isMap := value.(map[string]any)
+	if !isMap && value != nil {
+		return nil, true, NewValidationError(fmt.Sprintf("%s marshals itself to %T, not a map", rv.Type(), value))
+	}
+
+	return result, true, nil
+}
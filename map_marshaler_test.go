@@ -0,0 +1,79 @@
+package mapstructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMoney struct {
+	Cents    int64
+	Currency string
+}
+
+func (m testMoney) MarshalMap() (map[string]any, error) {
+	if m.Currency == "" {
+		return nil, errors.New("missing currency")
+	}
+
+	return map[string]any{"amount": float64(m.Cents) / 100, "currency": m.Currency}, nil
+}
+
+type testColor struct {
+	R, G, B uint8
+}
+
+func (c *testColor) MarshalMapValue() (any, error) {
+	return []any{c.R, c.G, c.B}, nil
+}
+
+// MarshalText is ignored in favor of MarshalMapValue
+func (c *testColor) MarshalText() ([]byte, error) {
+	return []byte("color"), nil
+}
+
+func TestMarshal_MapMarshaler(t *testing.T) {
+	type Product struct {
+		Price   testMoney   `schema:"price"`
+		Color   testColor   `schema:"color"`
+		Accent  *testColor  `schema:"accent"`
+		Palette []testColor `schema:"palette"`
+	}
+
+	got, err := Marshal(&Product{
+		Price:   testMoney{Cents: 1250, Currency: "EUR"},
+		Color:   testColor{R: 1, G: 2, B: 3},
+		Palette: []testColor{{R: 4}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"price":   map[string]any{"amount": 12.5, "currency": "EUR"},
+		"color":   []any{uint8(1), uint8(2), uint8(3)},
+		"accent":  nil,
+		"palette": []any{[]any{uint8(4), uint8(0), uint8(0)}},
+	}, got)
+
+	_, err = Marshal(Product{Color: testColor{}})
+	var convErr *ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, "price", convErr.FieldPath)
+	assert.ErrorContains(t, err, "missing currency")
+}
+
+func TestMarshal_MapMarshalerRoot(t *testing.T) {
+	got, err := Marshal(testMoney{Cents: 100, Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"amount": 1.0, "currency": "USD"}, got)
+
+	_, err = Marshal(testMoney{})
+	assert.ErrorContains(t, err, "missing currency")
+
+	_, err = Marshal(&testColor{})
+	require.ErrorAs(t, err, new(*ValidationError))
+	assert.EqualError(t, err, "mapstructure.testColor marshals itself to []interface {}, not a map")
+
+	_, err = Marshal(nil)
+	require.ErrorAs(t, err, new(*ValidationError))
+}
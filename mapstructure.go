@@ -2,7 +2,9 @@ package mapstructure
 
 import (
 	"fmt"
+	"log/slog"
 	"reflect"
+	"slices"
 )
 
 var defaultUnmarshaler = &Unmarshaler{
@@ -13,17 +15,50 @@ var defaultUnmarshaler = &Unmarshaler{
 // Unmarshal transforms map[string]any into a Go struct pointed to by result.
 // result must be a pointer to the target type.
 // This is a convenience function that uses a shared default unmarshaler.
-func Unmarshal(data map[string]any, result any) error {
-	return defaultUnmarshaler.Unmarshal(data, result)
+func Unmarshal(data map[string]any, result any, opts ...CallOption) error {
+	return defaultUnmarshaler.Unmarshal(data, result, opts...)
 }
 
 // Unmarshaler handles unmarshaling of maps to Go structs.
+// It is immutable once constructed and safe for concurrent use: each call keeps its own
+// state, and methods deriving unmarshalers (With, WithValidators...) return copies.
+// Registered hooks (converters, transformers, validators, verifiers, sanitizers, resolvers, metrics)
+// are called from the decoding goroutines and must be safe for concurrent use themselves.
 type Unmarshaler struct {
-	fieldCache *StructMetadataCache
-	converters *ConverterRegistry
+	fieldCache      *StructMetadataCache
+	converters      *ConverterRegistry
+	transformers    map[reflect.Type]Transformer // Input rewrites by struct type, see WithTransformers
+	defaultFuncs    map[reflect.Type]DefaultFunc // Derived defaults by struct type, see WithDefaultFuncs
+	validators      map[reflect.Type]Validator   // Cross-field checks by struct type, see WithValidators
+	verifiers       map[reflect.Type]Verifier    // Checks against the raw input by struct type, see WithVerifiers
+	hooks           []DecodeHook                 // Input transforms run on every value, see WithHooks
+	sanitizers      map[string]Sanitizer         // Named string sanitizers, see WithSanitizers
+	exprFuncs       map[string]ExprFunc          // Named expression functions, see WithExprFuncs
+	anyPolicy       AnyPolicy                    // Normalization of values decoded into any, see WithAnyPolicy
+	copyReferences  bool                         // Deep-copy directly assigned values, see WithCopyReferences
+	verifyInput     bool                         // Fail when decoding mutates the input, see WithInputVerification
+	iteration       IterationStrategy            // Matching of fields with input keys, see WithIterationStrategy
+	funcPolicy      FuncFieldPolicy              // Handling of input for func and chan fields, see WithFuncFieldPolicy
+	maxDepth        int                          // Limit of input nesting, 0 for none, see WithMaxDepth
+	references      bool                         // Resolve "$ref" input, see WithReferences
+	resolver        RefResolver                  // Resolves references not declared in the input, may be nil
+	metrics         Metrics                      // Observes decodes and conversions, see WithMetrics
+	logger          *slog.Logger                 // Receives warnings, may be nil, see WithLogger
+	convertersFirst bool                         // Converters take precedence over assignment, see WithConvertersFirst
+	nullPolicy      NullPolicy                   // Handling of nil input for values that cannot be nil, see WithNullPolicy
+	nilInput        NilInputPolicy               // Handling of a nil input map, see WithNilInputPolicy
+	bytesAsStrings  bool                         // Decode []byte input as strings, see WithBytesAsStrings
+	errorUnset      bool                         // Fail on fields receiving no value, see WithErrorUnset
+	embeddedPolicy  EmbeddedPolicy               // Combining of promoted keys and nested maps, see WithEmbeddedPolicy
+	defaults        *defaultCache                // Converted default tags, nil unless WithPreparedDefaults
+	noDefaults      bool                         // Ignore default tags and default funcs, see WithoutDefaults
+	duplicates      DuplicateKeyPolicy           // Handling of keys repeated in ordered input, see WithDuplicateKeyPolicy
+	yamlUnmarshal   UnmarshalFunc                // Parses YAML bodies, nil without YAML support, see WithYAMLUnmarshal
+	call            callOptions                  // Per-call settings, see beginCall
+	state           *decodeState                 // Per-call state, nil outside calls that need it
 }
 
-// NewUnmarshaler creates a new unmarshaler with explicit dependencies.
+// NewUnmarshaler creates a new unmarshaler with explicit dependencies and options.
 // For custom configurations, construct the cache and converters separately:
 //
 //	// With custom tag
@@ -35,32 +70,63 @@ type Unmarshaler struct {
 //	cache := NewDefaultStructMetadataCache()
 //	converters := NewDefaultConverterRegistry(customConverters)
 //	u := NewUnmarshaler(cache, converters)
-func NewUnmarshaler(fieldCache *StructMetadataCache, converters *ConverterRegistry) *Unmarshaler {
-	return &Unmarshaler{
+//
+//	// With options
+//	u := NewUnmarshaler(cache, converters, WithAnyPolicy(AnyNumbers))
+func NewUnmarshaler(fieldCache *StructMetadataCache, converters *ConverterRegistry, opts ...Option) *Unmarshaler {
+	u := &Unmarshaler{
 		fieldCache: fieldCache,
 		converters: converters,
 	}
+	u.applyOptions(opts)
+
+	return u
 }
 
-// NewDefaultUnmarshaler creates a new unmarshaler with default settings.
+// NewDefaultUnmarshaler creates a new unmarshaler with default settings and the given options.
 // Uses "schema" tags for field mapping and "default" tags for default values.
-func NewDefaultUnmarshaler() *Unmarshaler {
-	return NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry())
+func NewDefaultUnmarshaler(opts ...Option) *Unmarshaler {
+	return NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry(), opts...)
 }
 
 // Unmarshal transforms map[string]any into a Go struct pointed to by result.
 // result must be a pointer to the target type.
-func (u *Unmarshaler) Unmarshal(data map[string]any, result any) error {
+// opts configure this call only, e.g. WithGroups("admin").
+func (u *Unmarshaler) Unmarshal(data map[string]any, result any, opts ...CallOption) error {
 	rv, err := validateResultPointer(result)
 	if err != nil {
 		return err
 	}
+	if err := u.validateTarget(rv.Type()); err != nil {
+		return err
+	}
 
-	return u.unmarshalValue(data, rv, "")
+	return u.run(data, rv.Type(), opts, func(call *Unmarshaler) error {
+		return call.unmarshalValue(data, rv, "", nil)
+	})
+}
+
+// run performs one decode call of data into a value of type typ configured with opts,
+// verifying the input when enabled. decode receives the unmarshaler configured for the call.
+func (u *Unmarshaler) run(data map[string]any, typ reflect.Type, opts []CallOption, decode func(call *Unmarshaler) error) error {
+	if err := u.checkNilInput(data == nil); err != nil {
+		return err
+	}
+
+	call := u.beginCall(typ, opts)
+	if u.verifyInput {
+		return verifyInputUnchanged(data, func() error {
+			return call.endCall(decode(call))
+		})
+	}
+
+	return call.endCall(decode(call))
 }
 
 // unmarshalValue recursively unmarshals a value into the reflect.Value.
-func (u *Unmarshaler) unmarshalValue(data any, rv reflect.Value, fieldPath string) error {
+// field is the metadata of the struct field being decoded (nil at the top level);
+// it is passed down to pointer and slice elements so tag options apply to them too.
+func (u *Unmarshaler) unmarshalValue(data any, rv reflect.Value, fieldPath string, field *FieldMetadata) error {
 	if !rv.CanSet() {
 		return nil
 	}
@@ -68,42 +134,131 @@ func (u *Unmarshaler) unmarshalValue(data any, rv reflect.Value, fieldPath strin
 	kind := rv.Kind()
 	typ := rv.Type()
 
+	// Nodes of kind-tagged trees are converted as decoding reaches them
+	if node, ok := data.(Node); ok {
+		data = nodeInput(node, typ)
+	}
+
+	// Decode hooks transform the input before anything else sees it
+	if len(u.hooks) > 0 {
+		hooked, err := u.runHooks(data, typ, fieldPath)
+		if err != nil {
+			return err
+		}
+		data = hooked
+	}
+
+	// Nil input keeps values that cannot be nil under NullsKeep
+	if data == nil && u.nullPolicy == NullsKeep && !isNillable(kind) {
+		return nil
+	}
+
+	// Byte slices from codecs such as msgpack stand for strings
+	if u.bytesAsStrings {
+		data = bytesAsString(data, kind)
+	}
+
+	// Parse wire formats selected by the "format" tag option.
+	// Pointers, slices and sets pass the option on to their elements instead.
+	if kind != reflect.Ptr && kind != reflect.Slice && kind != reflect.Map {
+		formatted, err := applyFormat(data, field, typ)
+		if err != nil {
+			return NewConversionError(fieldPath, data, typ, err)
+		}
+		data = formatted
+	}
+
+	// Normalize values assigned to fields of type any
+	if u.anyPolicy != 0 && kind == reflect.Interface && isAnyType(typ) {
+		data = normalizeAny(data, u.anyPolicy)
+	}
+
+	// Interfaces holding a struct decode map input into it
+	if kind == reflect.Interface {
+		if done, err := u.unmarshalInterfaceValue(data, rv, fieldPath, field); done {
+			return err
+		}
+	}
+
+	// Registered converters run before assignment when they take precedence
+	convertFirst := u.convertersFirst || u.converters.IsAuthoritative(typ)
+	if convertFirst {
+		if done, err := u.applyConverter(data, rv, fieldPath, field); done {
+			return err
+		}
+	}
+
+	// Scalars of the exact target type are stored without reflect.ValueOf
+	if setScalar(data, rv) {
+		return nil
+	}
+
 	// Direct assignment if types are compatible
 	if data != nil {
 		dataType := reflect.TypeOf(data)
 		if dataType.AssignableTo(typ) {
-			rv.Set(reflect.ValueOf(data))
+			rv.Set(u.assignable(reflect.ValueOf(data)))
+			if kind == reflect.Slice {
+				return uniqueSlice(rv, fieldPath, field)
+			}
 
 			return nil
 		}
 	}
 
-	// Try converter for the target type
-	if conv, ok := u.converters.Find(typ); ok {
-		converted, err := conv(data)
-		if err != nil {
-			return NewConversionError(fieldPath, data, typ, err)
+	// Try the converter registered for the target type.
+	// Converters returning ErrSkipConverter fall through to structural decoding.
+	if !convertFirst {
+		if done, err := u.applyConverter(data, rv, fieldPath, field); done {
+			return err
 		}
-		rv.Set(converted)
+	}
 
-		return nil
+	return u.unmarshalStructural(data, rv, fieldPath, field)
+}
+
+// unmarshalStructural unmarshals data into rv by the shape of its type, without
+// looking up a converter for the type: sets, pointers, slices and structs.
+func (u *Unmarshaler) unmarshalStructural(data any, rv reflect.Value, fieldPath string, field *FieldMetadata) error {
+	typ := rv.Type()
+
+	// Sets are maps with empty struct values, decoded from slices
+	if isSetType(typ) {
+		return u.unmarshalSet(data, rv, fieldPath, field)
 	}
 
 	//nolint:exhaustive // Unsupported types are handled in default case with error
-	switch kind {
+	switch rv.Kind() {
 	case reflect.Ptr:
-		return u.unmarshalPtr(data, rv, fieldPath)
+		return u.unmarshalPtr(data, rv, fieldPath, field)
 	case reflect.Slice:
-		return u.unmarshalSlice(data, rv, fieldPath)
+		return u.unmarshalSlice(data, rv, fieldPath, field)
+	case reflect.Map, reflect.Interface:
+		// nil is acceptable for maps and interfaces no converter handled
+		if data == nil {
+			rv.Set(reflect.Zero(typ))
+
+			return nil
+		}
+
+		return NewUnsupportedTypeError(fieldPath, typ)
 	case reflect.Struct:
+		if _, ok := field.Option(OptionTuple); ok {
+			tuple, err := u.tupleInput(data, typ, fieldPath)
+			if err != nil {
+				return err
+			}
+			data = tuple
+		}
+
 		return u.unmarshalStruct(data, rv, fieldPath)
 	default:
-		return fmt.Errorf("%s: no converter registered for type %v", fieldPath, typ)
+		return NewUnsupportedTypeError(fieldPath, typ)
 	}
 }
 
 // unmarshalPtr unmarshals a pointer value.
-func (u *Unmarshaler) unmarshalPtr(data any, rv reflect.Value, fieldPath string) error {
+func (u *Unmarshaler) unmarshalPtr(data any, rv reflect.Value, fieldPath string, field *FieldMetadata) error {
 	// If data is nil or missing, set pointer to nil
 	if data == nil {
 		rv.Set(reflect.Zero(rv.Type()))
@@ -111,17 +266,32 @@ func (u *Unmarshaler) unmarshalPtr(data any, rv reflect.Value, fieldPath string)
 		return nil
 	}
 
+	// Share the object a reference stands for
+	if u.references {
+		if id, ok := refID(data); ok {
+			return u.setRef(id, rv, fieldPath)
+		}
+	}
+
 	// If pointer is nil, allocate new instance
 	if rv.IsNil() {
 		rv.Set(reflect.New(rv.Type().Elem()))
 	}
 
+	// Declare the object before decoding it, so references within it can resolve to it
+	if u.references {
+		var err error
+		if data, err = u.declareRef(data, rv, fieldPath); err != nil {
+			return err
+		}
+	}
+
 	// Recursively unmarshal the pointed-to type
-	return u.unmarshalValue(data, rv.Elem(), fieldPath)
+	return u.unmarshalValue(data, rv.Elem(), fieldPath, field)
 }
 
 // unmarshalSlice unmarshals a slice value.
-func (u *Unmarshaler) unmarshalSlice(data any, rv reflect.Value, fieldPath string) error {
+func (u *Unmarshaler) unmarshalSlice(data any, rv reflect.Value, fieldPath string, field *FieldMetadata) error {
 	// nil is acceptable for slices
 	if data == nil {
 		rv.Set(reflect.Zero(rv.Type()))
@@ -129,6 +299,20 @@ func (u *Unmarshaler) unmarshalSlice(data any, rv reflect.Value, fieldPath strin
 		return nil
 	}
 
+	// Split delimited strings when the "split" tag option is set
+	if delim, ok := splitDelimiter(field); ok {
+		if b, isBytes := data.([]byte); isBytes && u.bytesAsStrings {
+			data = string(b)
+		}
+		if s, ok := data.(string); ok {
+			elems, err := splitDelimited(s, delim)
+			if err != nil {
+				return NewConversionError(fieldPath, data, rv.Type(), err)
+			}
+			data = elems
+		}
+	}
+
 	// Use reflection to handle any slice type ([]any, []byte, []int, etc.)
 	dataVal := reflect.ValueOf(data)
 	if dataVal.Kind() != reflect.Slice && dataVal.Kind() != reflect.Array {
@@ -142,34 +326,45 @@ func (u *Unmarshaler) unmarshalSlice(data any, rv reflect.Value, fieldPath strin
 		return nil
 	}
 
-	return u.unmarshalSliceElements(dataVal, rv, fieldPath, dataLen)
+	pendingRefs := u.pendingRefCount()
+	if err := u.unmarshalSliceElements(dataVal, rv, fieldPath, dataLen, field); err != nil {
+		return err
+	}
+	if _, unique := field.Option(OptionUnique); unique {
+		if err := u.checkCopiedRefs(pendingRefs); err != nil {
+			return err
+		}
+	}
+
+	return uniqueSlice(rv, fieldPath, field)
 }
 
 // unmarshalSliceElements handles the actual slice element unmarshaling with fast paths.
-func (u *Unmarshaler) unmarshalSliceElements(dataVal, rv reflect.Value, fieldPath string, dataLen int) error {
-	// Pre-allocate slice with appropriate capacity
-	slice := reflect.MakeSlice(rv.Type(), dataLen, dataLen)
-	sliceElemType := slice.Type().Elem()
-
-	// Fast path 1: direct assignment for fully compatible types
-	if dataVal.Type().AssignableTo(slice.Type()) {
-		rv.Set(dataVal)
+func (u *Unmarshaler) unmarshalSliceElements(dataVal, rv reflect.Value, fieldPath string, dataLen int, field *FieldMetadata) error {
+	// Fast path 1: direct assignment for fully compatible types, before allocating
+	if dataVal.Type().AssignableTo(rv.Type()) {
+		rv.Set(u.assignable(dataVal))
 
 		return nil
 	}
 
+	// Pre-allocate slice with appropriate capacity
+	slice := reflect.MakeSlice(rv.Type(), dataLen, dataLen)
+	sliceElemType := slice.Type().Elem()
+
 	// Fast path 2: direct copy for same element type
 	if dataVal.Type().Elem() == sliceElemType {
-		reflect.Copy(slice, dataVal)
+		reflect.Copy(slice, u.assignable(dataVal))
 		rv.Set(slice)
 
 		return nil
 	}
 
-	// Fast path 3: direct element assignment for interface targets
-	if sliceElemType.Kind() == reflect.Interface {
+	// Fast path 3: direct element assignment for any targets.
+	// Other interfaces go element by element, so their converters (e.g. factories) run.
+	if isAnyType(sliceElemType) {
 		for i := range dataLen {
-			slice.Index(i).Set(dataVal.Index(i))
+			slice.Index(i).Set(u.assignable(dataVal.Index(i)))
 		}
 		rv.Set(slice)
 
@@ -177,14 +372,31 @@ func (u *Unmarshaler) unmarshalSliceElements(dataVal, rv reflect.Value, fieldPat
 	}
 
 	// Regular conversion path: element-by-element with converters
+	if err := u.enter(fieldPath); err != nil {
+		return err
+	}
+	defer u.leave()
+
+	// Elements failing to decode are dropped with the "skipinvalid" tag option
+	_, skipInvalid := field.Option(OptionSkipInvalid)
+	n := 0
 	for i := range dataLen {
 		elemPath := fmt.Sprintf("%s[%d]", fieldPath, i)
-		if err := u.unmarshalValue(dataVal.Index(i).Interface(), slice.Index(i), elemPath); err != nil {
+		validationErrs, pendingRefs := u.validationErrorCount(), u.pendingRefCount()
+		err := u.unmarshalValue(dataVal.Index(i).Interface(), slice.Index(n), elemPath, field)
+		switch {
+		case err == nil:
+			n++
+		case skipInvalid:
+			slice.Index(n).SetZero()
+			u.dropPendingRefs(pendingRefs) // The slot is reused by the next element
+			u.recordSkipped(elemPath, i, err, validationErrs)
+		default:
 			return err
 		}
 	}
 
-	rv.Set(slice)
+	rv.Set(slice.Slice(0, n))
 
 	return nil
 }
@@ -198,58 +410,246 @@ func (u *Unmarshaler) unmarshalStruct(data any, rv reflect.Value, fieldPath stri
 	}
 
 	// Get cached fields
-	typ := rv.Type()
-	metadata := u.fieldCache.GetMetadata(typ)
+	return u.decodeStruct(dataMap, rv, u.fieldCache.GetMetadata(rv.Type()), fieldPath)
+}
+
+// decodeStruct decodes dataMap into the struct rv described by metadata.
+func (u *Unmarshaler) decodeStruct(dataMap map[string]any, rv reflect.Value, metadata *StructMetadata, fieldPath string) error {
+	if metadata.tagErr != nil {
+		return metadata.tagErr
+	}
+
+	if err := u.enter(fieldPath); err != nil {
+		return err
+	}
+	defer u.leave()
+
+	// Rewrite the input with the transformer registered for the type
+	input := dataMap
+	dataMap = u.transform(rv.Type(), dataMap)
 
-	// Process each cached field
-	for _, field := range metadata.Fields {
-		fieldValue := rv.Field(field.Index)
+	// Reject unknown keys for strict structs, unless a remainder field collects them
+	if metadata.Options.Strict && metadata.remain == 0 {
+		if err := checkUnknownKeys(dataMap, metadata, fieldPath); err != nil {
+			return err
+		}
+	}
 
-		// Handle embedded structs
-		if field.Embedded {
-			if err := u.unmarshalEmbeddedField(dataMap, fieldValue, field, fieldPath); err != nil {
+	if err := u.unmarshalFields(dataMap, rv, metadata, fieldPath); err != nil {
+		return err
+	}
+
+	if err := u.applyDefaultFunc(rv, fieldPath); err != nil {
+		return err
+	}
+
+	if err := u.verify(input, rv, fieldPath); err != nil {
+		return err
+	}
+
+	u.validate(rv, fieldPath)
+
+	return nil
+}
+
+// unmarshalFields decodes the fields described by metadata, including the fields
+// promoted from embedded structs, from dataMap into rv.
+func (u *Unmarshaler) unmarshalFields(dataMap map[string]any, rv reflect.Value, metadata *StructMetadata, fieldPath string) error {
+	closed := u.closedEmbeds(dataMap, metadata)
+
+	// Enforce mutually exclusive keys
+	if err := u.checkOneOf(dataMap, metadata, closed, fieldPath); err != nil {
+		return err
+	}
+
+	// Report input for func and chan fields, which are never decoded
+	if err := u.checkFuncFields(dataMap, metadata, fieldPath); err != nil {
+		return err
+	}
+
+	// Require a value for every field without a default
+	if err := u.checkUnsetFields(dataMap, metadata, closed, fieldPath); err != nil {
+		return err
+	}
+
+	// Template defaults are applied after all other fields are decoded
+	var templateDefaults []*FieldMetadata
+
+	// Sparse input: visit only the fields of the input keys, plus those that may
+	// apply without a key, in field order
+	if u.iterateKeys(len(dataMap), len(metadata.flat)) {
+		for _, pos := range lookupFields(dataMap, metadata) {
+			if err := u.unmarshalField(dataMap, rv, metadata, &metadata.flat[pos], closed, fieldPath, &templateDefaults); err != nil {
 				return err
 			}
+		}
+	} else {
+		for i := range metadata.flat {
+			if err := u.unmarshalField(dataMap, rv, metadata, &metadata.flat[i], closed, fieldPath, &templateDefaults); err != nil {
+				return err
+			}
+		}
+	}
 
-			continue
+	// Keys no field accepts go to the remainder field
+	u.unmarshalRemain(dataMap, rv, metadata, closed)
+	u.recordKeys(dataMap, metadata, closed, fieldPath)
+
+	return u.applyTemplateDefaults(rv, metadata, templateDefaults, fieldPath)
+}
+
+// lookupFields returns the sorted positions in metadata.flat of the fields of the keys
+// of dataMap and of the fields that may apply without a key.
+func lookupFields(dataMap map[string]any, metadata *StructMetadata) []int {
+	positions := make([]int, 0, len(dataMap)+len(metadata.eager))
+	positions = append(positions, metadata.eager...)
+	for key := range dataMap {
+		if field, ok := metadata.byKey[key]; ok {
+			positions = append(positions, field.pos)
 		}
+	}
 
-		// Get value from map, fall back to default if not present
-		value, exists := dataMap[field.MapKey]
-		if !exists {
-			if field.Default == nil {
-				continue
-			}
+	slices.Sort(positions)
+
+	return slices.Compact(positions)
+}
+
+// unmarshalField decodes field from dataMap into rv, deferring template defaults
+// to templateDefaults.
+func (u *Unmarshaler) unmarshalField(dataMap map[string]any, rv reflect.Value, metadata *StructMetadata, field *FieldMetadata,
+	closed []bool, fieldPath string, templateDefaults *[]*FieldMetadata,
+) error {
+	// Skip fields outside the groups selected for this call, promoted fields
+	// of embedded structs that are not decoded from dataMap, and remainders
+	if !isOpen(closed, field) || !u.fieldSelected(field) || isRemain(field) {
+		return nil
+	}
+
+	// Named embedded: decode the struct from the nested map under its Go field name
+	if field.Embedded {
+		if nestedData, ok := u.embeddedInput(dataMap, metadata, field); ok {
+			return u.unmarshalValue(nestedData, fieldByIndex(rv, field.Index), fieldPath, nil)
+		}
+
+		return nil
+	}
+
+	// Get value from map, fall back to the default of an embedded struct declaring
+	// the field, then to the field's default, if not present
+	value, exists := dataMap[field.MapKey]
+	fromDefault := !exists
+	if !exists && !u.hasDefault(field) {
+		return nil
+	}
+	if !exists && field.embeddedDefault != nil {
+		value, exists = *field.embeddedDefault, true
+	}
+	if !exists {
+		if field.Default == nil {
+			return nil
+		}
 
-			value = *field.Default
+		if field.defaultTemplate != nil {
+			*templateDefaults = append(*templateDefaults, field)
+
+			return nil
 		}
 
-		// Unmarshal the field value (handles converters and built-in conversion)
-		fullPath := buildFieldPath(fieldPath, field.MapKey)
-		if err := u.unmarshalValue(value, fieldValue, fullPath); err != nil {
-			return fmt.Errorf("%s: %w", fullPath, err)
+		value = *field.Default
+	}
+
+	return u.decodeField(value, rv, field, fieldPath, fromDefault, !exists)
+}
+
+// decodeField decodes value, or the tag default of field when tagDefault, into the
+// field of rv, recovering input errors unless the value comes from a default.
+func (u *Unmarshaler) decodeField(value any, rv reflect.Value, field *FieldMetadata, fieldPath string, fromDefault, tagDefault bool) error {
+	// Unmarshal the field value (handles converters and built-in conversion)
+	fullPath := buildFieldPath(fieldPath, field.MapKey)
+	fieldValue := fieldByIndex(rv, field.Index)
+	validationErrs, pendingRefs := u.validationErrorCount(), u.pendingRefCount()
+	var err error
+	if source, ok := field.Option(OptionExpr); ok {
+		err = u.unmarshalExpr(value, fieldValue, fullPath, source)
+	} else if tagDefault {
+		err = u.unmarshalDefault(fieldValue, fullPath, field)
+	} else {
+		err = u.unmarshalValue(value, fieldValue, fullPath, field)
+	}
+	if err != nil && (fromDefault || !u.recoverField(value, fieldValue, fullPath, field, err, validationErrs, pendingRefs)) {
+		return fmt.Errorf("%s: %w", fullPath, err)
+	}
+	u.countField(fromDefault)
+
+	// Normalize decoded strings, then enforce length constraints on the result
+	if err := u.normalizeStrings(fieldValue, fullPath, field); err != nil {
+		return err
+	}
+
+	return checkLength(fieldValue, fullPath, field)
+}
+
+// closedEmbeds reports, by position in metadata.flat, the embedded structs whose promoted
+// fields are not decoded from dataMap: those outside the selected groups, those decoded
+// from a nested map instead, and those embedded in closed ones. It returns nil when all
+// embedded structs are open.
+func (u *Unmarshaler) closedEmbeds(dataMap map[string]any, metadata *StructMetadata) []bool {
+	var closed []bool
+
+	for _, i := range metadata.eager {
+		field := &metadata.flat[i]
+		if !field.Embedded {
+			continue
+		}
+
+		if _, nested := nestedEmbed(dataMap, field); isOpen(closed, field) && u.fieldSelected(field) && !nested {
+			continue
+		}
+
+		if closed == nil {
+			closed = make([]bool, len(metadata.flat))
 		}
+		closed[i] = true
 	}
 
-	return nil
+	return closed
 }
 
-// unmarshalEmbeddedField handles unmarshaling of embedded struct fields.
-func (u *Unmarshaler) unmarshalEmbeddedField(dataMap map[string]any, fieldValue reflect.Value, field FieldMetadata, fieldPath string) error {
-	if field.Type.Kind() != reflect.Struct {
-		return nil
+// isOpen reports whether field is decoded from the input map of the struct, i.e. it is
+// an own field or promoted from an embedded struct that closedEmbeds left open.
+func isOpen(closed []bool, field *FieldMetadata) bool {
+	return closed == nil || field.owner == 0 || !closed[field.owner-1]
+}
+
+// nestedEmbed returns the nested map holding the embedded struct field, when dataMap
+// addresses it by its Go field name.
+func nestedEmbed(dataMap map[string]any, field *FieldMetadata) (map[string]any, bool) {
+	if field.promoted == nil {
+		return nil, false
 	}
 
-	// Check if there's a nested map with the field name (named embedded)
-	if nestedMap, exists := dataMap[field.StructFieldName]; exists {
-		if nestedData, ok := nestedMap.(map[string]any); ok {
-			// Named embedded: unmarshal from nested map
-			return u.unmarshalValue(nestedData, fieldValue, fieldPath)
+	nestedData, ok := dataMap[field.StructFieldName].(map[string]any)
+
+	return nestedData, ok
+}
+
+// checkUnknownKeys returns a ConstraintError for the first (in sorted order) key of
+// dataMap that does not map to a field of the struct.
+func checkUnknownKeys(dataMap map[string]any, metadata *StructMetadata, fieldPath string) error {
+	var unknown []string
+	for key := range dataMap {
+		if !metadata.HasKey(key) {
+			unknown = append(unknown, key)
 		}
 	}
 
-	// Anonymous embedded: pass entire data map (promoted fields)
-	return u.unmarshalValue(dataMap, fieldValue, fieldPath)
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknown)
+
+	return NewConstraintError(buildFieldPath(fieldPath, unknown[0]), "strict", fmt.Sprintf("unknown key %q", unknown[0]))
 }
 
 // validateResultPointer validates that result is a non-nil pointer and returns its element.
@@ -266,6 +666,25 @@ func validateResultPointer(result any) (reflect.Value, error) {
 	return rv.Elem(), nil
 }
 
+// validateTarget returns an UnsupportedTargetError when typ, the type result points to,
+// or the type typ points to, is a kind no input map decodes into and has no converter.
+func (u *Unmarshaler) validateTarget(typ reflect.Type) error {
+	if typ.Kind() == reflect.Ptr && !u.hasConverter(typ) {
+		typ = typ.Elem()
+	}
+	if u.hasConverter(typ) {
+		return nil
+	}
+
+	//nolint:exhaustive // Other kinds are decoded or reported by unmarshalValue
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return NewUnsupportedTargetError(typ)
+	default:
+		return nil
+	}
+}
+
 // buildFieldPath builds a field path for error messages.
 func buildFieldPath(base, field string) string {
 	if base == "" {
@@ -274,3 +693,23 @@ func buildFieldPath(base, field string) string {
 
 	return base + "." + field
 }
+
+// fieldByIndex returns the nested field of the struct rv at the index path, like
+// reflect.Value.FieldByIndex, but allocating nil embedded struct pointers on the way.
+// It returns the zero Value when a nil pointer cannot be allocated because it is unexported.
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv
+}
@@ -86,6 +86,120 @@ func TestUnmarshaler_Unmarshal_Slices(t *testing.T) {
 	}
 }
 
+func TestUnmarshaler_Unmarshal_NestedSlices(t *testing.T) {
+	type Grid struct {
+		Matrix [][]float64        `schema:"matrix"`
+		Words  [][]string         `schema:"words"`
+		Cube   [][][]int          `schema:"cube"`
+		Rows   []*[]int           `schema:"rows"`
+		CSV    [][]string         `schema:"csv,split=,"`
+		Upper  [][]string         `schema:"upper,upper"`
+		Any    [][]any            `schema:"any"`
+		Sets   []map[int]struct{} `schema:"sets"`
+	}
+
+	one := 1
+	tests := []struct {
+		name     string
+		data     map[string]any
+		expected Grid
+	}{
+		{
+			name:     "matrix of mixed numbers",
+			data:     map[string]any{"matrix": []any{[]any{1, "2.5", int64(3)}, []any{float32(0.5)}}},
+			expected: Grid{Matrix: [][]float64{{1, 2.5, 3}, {0.5}}},
+		},
+		{
+			name:     "typed rows converted",
+			data:     map[string]any{"matrix": [][]int{{1, 2}, {3}}, "words": [][]any{{"a", 1, true}}},
+			expected: Grid{Matrix: [][]float64{{1, 2}, {3}}, Words: [][]string{{"a", "1", "1"}}},
+		},
+		{
+			name:     "rows of different input types",
+			data:     map[string]any{"matrix": []any{[]int{1}, []string{"2"}, []float64{3}}},
+			expected: Grid{Matrix: [][]float64{{1}, {2}, {3}}},
+		},
+		{
+			name:     "jagged, empty and nil rows",
+			data:     map[string]any{"matrix": []any{[]any{1, 2, 3}, []any{}, nil}},
+			expected: Grid{Matrix: [][]float64{{1, 2, 3}, {}, nil}},
+		},
+		{
+			name:     "three levels",
+			data:     map[string]any{"cube": []any{[]any{[]any{1, "2"}, []any{}}, []any{[]any{3}}}},
+			expected: Grid{Cube: [][][]int{{{1, 2}, {}}, {{3}}}},
+		},
+		{
+			name:     "pointers to rows",
+			data:     map[string]any{"rows": []any{[]any{"1"}, nil}},
+			expected: Grid{Rows: []*[]int{{one}, nil}},
+		},
+		{
+			name:     "split applies to rows",
+			data:     map[string]any{"csv": []any{"a,b", "c", []any{"d"}}},
+			expected: Grid{CSV: [][]string{{"a", "b"}, {"c"}, {"d"}}},
+		},
+		{
+			name:     "normalization applies to nested strings",
+			data:     map[string]any{"upper": []any{[]any{"a", "b"}, []any{"c"}}},
+			expected: Grid{Upper: [][]string{{"A", "B"}, {"C"}}},
+		},
+		{
+			name:     "rows of any",
+			data:     map[string]any{"any": []any{[]any{1, "a", nil}, []string{"b"}}},
+			expected: Grid{Any: [][]any{{1, "a", nil}, {"b"}}},
+		},
+		{
+			name:     "slices of sets",
+			data:     map[string]any{"sets": []any{[]any{1, "2", 1}}},
+			expected: Grid{Sets: []map[int]struct{}{{1: {}, 2: {}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Grid
+			require.NoError(t, Unmarshal(tt.data, &result))
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("error paths are indexed at every level", func(t *testing.T) {
+		errTests := []struct {
+			name string
+			data map[string]any
+			path string
+		}{
+			{name: "matrix cell", data: map[string]any{"matrix": []any{[]any{1}, []any{}, []any{1, 2, 3, 4, 5, "x"}}}, path: "matrix[2][5]"},
+			{name: "cube cell", data: map[string]any{"cube": []any{[]any{}, []any{[]any{1}, []any{1, "y"}}}}, path: "cube[1][1][1]"},
+			{name: "row not a slice", data: map[string]any{"matrix": []any{[]any{1}, "x"}}, path: "matrix[1]"},
+			{name: "typed row cell", data: map[string]any{"matrix": []any{[]string{"1", "x"}}}, path: "matrix[0][1]"},
+			{name: "pointer row cell", data: map[string]any{"rows": []any{nil, []any{"z"}}}, path: "rows[1][0]"},
+		}
+
+		for _, tt := range errTests {
+			t.Run(tt.name, func(t *testing.T) {
+				var result Grid
+				err := Unmarshal(tt.data, &result)
+
+				var convErr *ConversionError
+				require.ErrorAs(t, err, &convErr)
+				assert.Equal(t, tt.path, convErr.FieldPath)
+			})
+		}
+	})
+
+	t.Run("copied rows do not share input", func(t *testing.T) {
+		input := [][]float64{{1, 2}, {3}}
+		u := NewDefaultUnmarshaler(WithCopyReferences())
+
+		var result Grid
+		require.NoError(t, u.Unmarshal(map[string]any{"matrix": input}, &result))
+		result.Matrix[0][0] = 99
+		assert.InDelta(t, 1.0, input[0][0], 0)
+	})
+}
+
 func TestUnmarshaler_Unmarshal_BasicTypes(t *testing.T) {
 	type Basic struct {
 		Name   string
@@ -284,6 +398,34 @@ func TestUnmarshaler_Unmarshal_Errors(t *testing.T) {
 	}
 }
 
+func TestUnmarshaler_Unmarshal_UnsupportedTarget(t *testing.T) {
+	u := testUnmarshaler()
+	data := map[string]any{"a": 1}
+
+	var ch chan int
+	err := u.Unmarshal(data, &ch)
+	var targetErr *UnsupportedTargetError
+	require.ErrorAs(t, err, &targetErr)
+	assert.Equal(t, reflect.TypeFor[chan int](), targetErr.Type)
+	assert.EqualError(t, err, "cannot unmarshal into chan int: target kind chan is not supported, "+
+		"use a pointer to a struct, a map or an interface, or register a converter for the type")
+
+	var fn *func()
+	require.ErrorAs(t, u.Unmarshal(data, &fn), &targetErr)
+	assert.Equal(t, reflect.TypeFor[func()](), targetErr.Type)
+
+	require.ErrorAs(t, u.UnmarshalStrings(map[string]string{"a": "1"}, &ch), &targetErr)
+
+	// Registered converters make any type a valid target
+	withConverter := NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[chan int](): func(any) (reflect.Value, error) {
+			return reflect.ValueOf(make(chan int)), nil
+		},
+	}))
+	require.NoError(t, withConverter.Unmarshal(data, &ch))
+	assert.NotNil(t, ch)
+}
+
 func TestUnmarshaler_Unmarshal_UnsupportedType(t *testing.T) {
 	type Target struct {
 		Complex complex128
@@ -298,6 +440,11 @@ func TestUnmarshaler_Unmarshal_UnsupportedType(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "no converter registered")
+
+	var typeErr *UnsupportedTypeError
+	require.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "Complex", typeErr.FieldPath)
+	assert.Equal(t, reflect.TypeFor[complex128](), typeErr.Type)
 }
 
 //nolint:forcetypeassert,thelper // Test code - type assertions are expected to succeed
@@ -836,6 +983,163 @@ func TestUnmarshaler_Unmarshal_EmbeddedStructs(t *testing.T) {
 	})
 }
 
+func TestUnmarshaler_Unmarshal_PromotedFields(t *testing.T) {
+	type Base struct {
+		ID   string `schema:"id"`
+		Name string `schema:"name"`
+	}
+	type Audit struct {
+		Base
+		By string `schema:"by"`
+	}
+	type Left struct {
+		Label string `schema:"label"`
+	}
+	type Right struct {
+		Label string `schema:"label"`
+	}
+	type Record struct {
+		Audit
+		Left
+		Right
+		Name string `schema:"name"`
+	}
+
+	u := NewDefaultUnmarshaler()
+
+	t.Run("deeply promoted", func(t *testing.T) {
+		var result Record
+		require.NoError(t, u.Unmarshal(map[string]any{"id": "1", "by": "alice"}, &result))
+		assert.Equal(t, "1", result.ID)
+		assert.Equal(t, "alice", result.By)
+	})
+
+	t.Run("shallower field wins", func(t *testing.T) {
+		var result Record
+		require.NoError(t, u.Unmarshal(map[string]any{"name": "outer"}, &result))
+		assert.Equal(t, "outer", result.Name)
+		assert.Empty(t, result.Base.Name)
+	})
+
+	t.Run("ambiguous fields are ignored", func(t *testing.T) {
+		var result Record
+		require.NoError(t, u.Unmarshal(map[string]any{"label": "x"}, &result))
+		assert.Empty(t, result.Left.Label)
+		assert.Empty(t, result.Right.Label)
+	})
+
+	t.Run("nested map for inner embedded struct", func(t *testing.T) {
+		var result Record
+		data := map[string]any{"id": "ignored", "Base": map[string]any{"id": "2", "name": "inner"}}
+		require.NoError(t, u.Unmarshal(data, &result))
+		assert.Equal(t, "2", result.ID)
+		assert.Equal(t, "inner", result.Base.Name)
+	})
+
+	t.Run("oneof scoped to named embedded struct", func(t *testing.T) {
+		type Source struct {
+			URL  *string `schema:"url,oneof=src,required"`
+			Path *string `schema:"path,oneof=src"`
+		}
+		type Job struct {
+			Source
+			Name string `schema:"name"`
+		}
+
+		var result Job
+		data := map[string]any{"name": "j", "Source": map[string]any{"path": "/tmp"}}
+		require.NoError(t, u.Unmarshal(data, &result))
+		require.NotNil(t, result.Path)
+		assert.Equal(t, "/tmp", *result.Path)
+
+		err := u.Unmarshal(map[string]any{"name": "j"}, &result)
+		var conErr *ConstraintError
+		require.ErrorAs(t, err, &conErr)
+		assert.Equal(t, "oneof", conErr.Constraint)
+	})
+
+	t.Run("template defaults of promoted fields", func(t *testing.T) {
+		type Endpoint struct {
+			Host string `schema:"host" default:"localhost"`
+			Addr string `schema:"addr" default:"{{.Host}}:80"`
+		}
+		type Service struct {
+			Endpoint
+			Name string `schema:"name"`
+		}
+
+		var result Service
+		require.NoError(t, u.Unmarshal(map[string]any{"host": "example.com"}, &result))
+		assert.Equal(t, "example.com:80", result.Addr)
+	})
+
+	t.Run("embedded struct outside selected groups", func(t *testing.T) {
+		type Secrets struct {
+			Token string `schema:"token"`
+		}
+		type Account struct {
+			Secrets `schema:",groups=admin"`
+			Name    string `schema:"name"`
+		}
+
+		var result Account
+		data := map[string]any{"name": "a", "token": "t"}
+		require.NoError(t, u.Unmarshal(data, &result, WithGroups("user")))
+		assert.Empty(t, result.Token)
+
+		require.NoError(t, u.Unmarshal(data, &result, WithGroups("admin")))
+		assert.Equal(t, "t", result.Token)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_SparseInput(t *testing.T) {
+	type Audit struct {
+		By string `schema:"by" default:"system"`
+	}
+	type Wide struct {
+		Audit
+		F1  string `schema:"f1"`
+		F2  string `schema:"f2"`
+		F3  int    `schema:"f3"`
+		F4  int    `schema:"f4"`
+		F5  string `schema:"f5" default:"five"`
+		F6  string `schema:"f6"`
+		F7  string `schema:"f7"`
+		F8  int    `schema:"f8"`
+		F9  string `schema:"f9"`
+		F10 string `schema:"f10" default:"{{.F1}}-ten"`
+	}
+
+	u := NewDefaultUnmarshaler()
+
+	t.Run("matches field-driven decoding", func(t *testing.T) {
+		var result Wide
+		require.NoError(t, u.Unmarshal(map[string]any{"f1": "one", "f8": 8}, &result))
+		assert.Equal(t, Wide{
+			Audit: Audit{By: "system"},
+			F1:    "one",
+			F5:    "five",
+			F8:    8,
+			F10:   "one-ten",
+		}, result)
+	})
+
+	t.Run("nested embedded map", func(t *testing.T) {
+		var result Wide
+		require.NoError(t, u.Unmarshal(map[string]any{"Audit": map[string]any{"by": "alice"}}, &result))
+		assert.Equal(t, "alice", result.By)
+	})
+
+	t.Run("first error in field order", func(t *testing.T) {
+		var result Wide
+		err := u.Unmarshal(map[string]any{"f8": "x", "f3": "y"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "f3", convErr.FieldPath)
+	})
+}
+
 func TestUnmarshaler_Unmarshal_EmbeddedStructs_NonStruct(t *testing.T) {
 	// Test that non-struct embedded fields are handled gracefully
 	type CustomInt int
@@ -861,6 +1165,56 @@ func TestUnmarshaler_Unmarshal_EmbeddedStructs_NonStruct(t *testing.T) {
 	})
 }
 
+func TestUnmarshaler_Unmarshal_EmbeddedPointers(t *testing.T) {
+	type Audit struct {
+		By string `schema:"by"`
+	}
+	type Record struct {
+		*Audit
+		Name string `schema:"name"`
+	}
+
+	u := NewDefaultUnmarshaler()
+
+	tests := []struct {
+		name string
+		data map[string]any
+		want Record
+	}{
+		{
+			name: "promoted field allocates the embedded struct",
+			data: map[string]any{"name": "a", "by": "me"},
+			want: Record{Audit: &Audit{By: "me"}, Name: "a"},
+		},
+		{
+			name: "left nil without promoted keys",
+			data: map[string]any{"name": "a"},
+			want: Record{Name: "a"},
+		},
+		{
+			name: "nested under the Go field name",
+			data: map[string]any{"Audit": map[string]any{"by": "you"}},
+			want: Record{Audit: &Audit{By: "you"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Record
+			require.NoError(t, u.Unmarshal(tt.data, &result))
+			assert.Equal(t, tt.want, result)
+		})
+	}
+
+	t.Run("existing embedded struct is reused", func(t *testing.T) {
+		audit := &Audit{By: "old"}
+		result := Record{Audit: audit}
+		require.NoError(t, u.Unmarshal(map[string]any{"by": "new"}, &result))
+		assert.Same(t, audit, result.Audit)
+		assert.Equal(t, "new", audit.By)
+	})
+}
+
 func TestUnmarshaler_Unmarshal_SliceFastPaths(t *testing.T) {
 	type ResultIntSlice struct {
 		Items []int
@@ -989,3 +1343,48 @@ func TestUnmarshaler_Unmarshal_SliceFastPaths(t *testing.T) {
 		assert.Equal(t, "Items", convErr.FieldPath)
 	})
 }
+
+func TestFieldByIndex(t *testing.T) {
+	type Leaf struct {
+		Name string
+	}
+	type Middle struct {
+		*Leaf
+	}
+	type Root struct {
+		ID int
+		Middle
+		hidden *Leaf
+	}
+
+	t.Run("direct field", func(t *testing.T) {
+		var root Root
+		fv := fieldByIndex(reflect.ValueOf(&root).Elem(), []int{0})
+		fv.SetInt(7)
+		assert.Equal(t, 7, root.ID)
+	})
+
+	t.Run("allocates nil pointers", func(t *testing.T) {
+		var root Root
+		fv := fieldByIndex(reflect.ValueOf(&root).Elem(), []int{1, 0, 0})
+		fv.SetString("leaf")
+		require.NotNil(t, root.Leaf)
+		assert.Equal(t, "leaf", root.Name)
+	})
+
+	t.Run("keeps existing pointers", func(t *testing.T) {
+		leaf := &Leaf{Name: "a"}
+		root := Root{Middle: Middle{Leaf: leaf}}
+		fv := fieldByIndex(reflect.ValueOf(&root).Elem(), []int{1, 0, 0})
+		fv.SetString("b")
+		assert.Same(t, leaf, root.Leaf)
+		assert.Equal(t, "b", leaf.Name)
+	})
+
+	t.Run("unexported nil pointer", func(t *testing.T) {
+		var root Root
+		fv := fieldByIndex(reflect.ValueOf(&root).Elem(), []int{2, 0})
+		assert.False(t, fv.IsValid())
+		assert.Nil(t, root.hidden)
+	})
+}
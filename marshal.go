@@ -0,0 +1,338 @@
+package mapstructure
+
+import (
+	"cmp"
+	"encoding"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+)
+
+var defaultMarshaler = &Marshaler{
+	fieldCache: NewDefaultStructMetadataCache(),
+}
+
+// OptionOmitEmpty is the tag option that omits a field from marshaled maps when it holds
+// an empty value: false, 0, "", a nil pointer or interface, an empty slice, map or array,
+// or a zero struct.
+const OptionOmitEmpty = "omitempty"
+
+// Marshal transforms the struct v, or a pointer to it, into a map[string]any.
+// This is a convenience function that uses a shared default marshaler.
+func Marshal(v any) (map[string]any, error) {
+	return defaultMarshaler.Marshal(v)
+}
+
+// Marshaler handles marshaling of Go structs to maps. It is the counterpart of
+// Unmarshaler and reads the same struct metadata, so a map produced by a Marshaler
+// decodes back into an equal struct.
+type Marshaler struct {
+	fieldCache *StructMetadataCache
+	redact     bool                    // Mask secret fields, see Redacted
+	zeroPolicy ZeroPolicy              // Emission of zero values, see WithZeroPolicy
+	encodeName NameEncoder             // Keys of fields not named by their tag, see WithNameEncoder
+	typeKey    string                  // Key of implementation names, see WithTypeNames
+	typeNames  map[reflect.Type]string // Implementation names by type, see WithTypeNames
+}
+
+// NewMarshaler creates a new marshaler reading struct metadata from fieldCache.
+// Share the cache with an Unmarshaler to use the same tag names for both directions.
+func NewMarshaler(fieldCache *StructMetadataCache, opts ...MarshalOption) *Marshaler {
+	m := &Marshaler{fieldCache: fieldCache}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// NewDefaultMarshaler creates a new marshaler with default settings.
+// Uses "schema" tags for field mapping.
+func NewDefaultMarshaler(opts ...MarshalOption) *Marshaler {
+	return NewMarshaler(NewDefaultStructMetadataCache(), opts...)
+}
+
+// Marshal transforms the struct v, or a pointer to it, into a map[string]any.
+//
+// Fields are keyed like Unmarshal reads them, with fields promoted from embedded structs
+// at the top level. Nested structs become map[string]any, slices and arrays become []any,
+// and sets (map[T]struct{}) become sorted []any. Values implementing encoding.TextMarshaler,
+// as well as Date, TimeOfDay and net.HardwareAddr, become strings; other fmt.Stringer types
+// are marshaled by their fields or elements. Types implementing MapMarshaler or MapValueMarshaler
+// marshal themselves, which takes precedence. Other values are stored as they are.
+func (m *Marshaler) Marshal(v any) (map[string]any, error) {
+	visiting := make(map[copyKey]struct{})
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		visiting[copyKey{ptr: rv.Pointer(), typ: rv.Type()}] = struct{}{}
+		rv = rv.Elem()
+	}
+	if result, ok, err := marshalRoot(rv); ok {
+		return result, err
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, NewValidationError("value must be a struct or a non-nil pointer to a struct")
+	}
+
+	return m.marshalStruct(rv, "", visiting)
+}
+
+// marshalStruct marshals the struct rv into a map. visiting holds the pointers being
+// marshaled, to detect cycles.
+func (m *Marshaler) marshalStruct(rv reflect.Value, fieldPath string, visiting map[copyKey]struct{}) (map[string]any, error) {
+	metadata := m.fieldCache.GetMetadata(rv.Type())
+	if metadata.tagErr != nil {
+		return nil, metadata.tagErr
+	}
+
+	result := make(map[string]any, len(metadata.flat))
+	for i := range metadata.flat {
+		field := &metadata.flat[i]
+		if field.Embedded {
+			continue // Promoted fields follow in metadata.flat
+		}
+		if isRemain(field) {
+			continue // Entries are added after the fields, see marshalRemain
+		}
+
+		fieldValue, ok := fieldByIndexNoAlloc(rv, field.Index)
+		if !ok {
+			continue // Promoted from a nil embedded pointer
+		}
+		if _, ok := field.Option(OptionOmitEmpty); ok && isEmptyValue(fieldValue) {
+			continue
+		}
+		if m.omitZero(fieldValue) {
+			continue
+		}
+		key := m.fieldKey(field)
+		if m.redacted(field) {
+			result[key] = RedactedValue
+
+			continue
+		}
+
+		value, err := m.marshalValue(fieldValue, buildFieldPath(fieldPath, key), visiting)
+		if err != nil {
+			return nil, err
+		}
+		m.discriminate(field, fieldValue, value)
+		result[key] = value
+	}
+
+	if err := m.marshalRemain(rv, metadata, result, fieldPath, visiting); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// marshalValue marshals rv into its map representation.
+func (m *Marshaler) marshalValue(rv reflect.Value, fieldPath string, visiting map[copyKey]struct{}) (any, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	if value, ok, err := marshalOverride(rv); ok {
+		if err != nil {
+			return nil, NewConversionError(fieldPath, rv.Interface(), rv.Type(), err)
+		}
+
+		return value, nil
+	}
+
+	if text, ok, err := m.marshalText(rv); ok {
+		if err != nil {
+			return nil, NewConversionError(fieldPath, rv.Interface(), rv.Type(), err)
+		}
+
+		return text, nil
+	}
+
+	//nolint:exhaustive // Remaining kinds are stored as they are
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Kind() == reflect.Ptr {
+			key := copyKey{ptr: rv.Pointer(), typ: rv.Type()}
+			if _, ok := visiting[key]; ok {
+				return nil, NewConversionError(fieldPath, nil, rv.Type(), errors.New("cycle detected"))
+			}
+			visiting[key] = struct{}{}
+			defer delete(visiting, key)
+		}
+
+		return m.marshalValue(rv.Elem(), fieldPath, visiting)
+	case reflect.Struct:
+		return m.marshalStruct(rv, fieldPath, visiting)
+	case reflect.Slice:
+		if rv.Len() == 0 {
+			if value, ok := m.emptyCollection(rv); ok {
+				return value, nil
+			}
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface(), nil // Bytes stay bytes, like []byte inputs
+		}
+
+		return m.marshalElements(rv, fieldPath, visiting)
+	case reflect.Array:
+		return m.marshalElements(rv, fieldPath, visiting)
+	case reflect.Map:
+		if rv.Len() == 0 {
+			if value, ok := m.emptyCollection(rv); ok {
+				return value, nil
+			}
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if isSetType(rv.Type()) {
+			return m.marshalSet(rv, fieldPath, visiting)
+		}
+		if rv.Type().Key().Kind() == reflect.String {
+			return m.marshalMap(rv, fieldPath, visiting)
+		}
+
+		return rv.Interface(), nil
+	default:
+		return rv.Interface(), nil
+	}
+}
+
+// marshalElements marshals the elements of the slice or array rv into a []any.
+func (m *Marshaler) marshalElements(rv reflect.Value, fieldPath string, visiting map[copyKey]struct{}) ([]any, error) {
+	result := make([]any, rv.Len())
+	for i := range rv.Len() {
+		elem, err := m.marshalValue(rv.Index(i), fmt.Sprintf("%s[%d]", fieldPath, i), visiting)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = elem
+	}
+
+	return result, nil
+}
+
+// marshalMap marshals the values of the string-keyed map rv into a map[string]any.
+func (m *Marshaler) marshalMap(rv reflect.Value, fieldPath string, visiting map[copyKey]struct{}) (map[string]any, error) {
+	result := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		value, err := m.marshalValue(iter.Value(), buildFieldPath(fieldPath, key), visiting)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// marshalSet marshals the members of the set rv into a []any, sorted so the output
+// is deterministic. Members of kinds without a natural order are sorted by their
+// formatted value.
+func (m *Marshaler) marshalSet(rv reflect.Value, fieldPath string, visiting map[copyKey]struct{}) ([]any, error) {
+	keys := rv.MapKeys()
+	slices.SortFunc(keys, compareValues)
+
+	result := make([]any, len(keys))
+	for i, key := range keys {
+		elem, err := m.marshalValue(key, fmt.Sprintf("%s[%d]", fieldPath, i), visiting)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = elem
+	}
+
+	return result, nil
+}
+
+// compareValues orders values of the same type: numbers and strings naturally,
+// anything else by its formatted value.
+func compareValues(a, b reflect.Value) int {
+	//nolint:exhaustive // Other kinds are compared by their formatted value
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	default:
+		return cmp.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	}
+}
+
+// marshalText returns the text form of rv and true when its type implements
+// encoding.TextMarshaler or is one of stringerTypes. Zero values of the latter reporting
+// so through an IsZero method become "". Redacted marshalers marshal structs holding
+// secret fields by their fields instead, so the secrets are masked.
+func (m *Marshaler) marshalText(rv reflect.Value) (string, bool, error) {
+	if rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface || !rv.CanInterface() {
+		return "", false, nil
+	}
+	if m.redact && m.holdsSecrets(rv.Type(), make(map[reflect.Type]struct{})) {
+		return "", false, nil
+	}
+
+	v := rv.Interface()
+	if !rv.Type().Implements(textMarshalerType) && rv.CanAddr() && reflect.PointerTo(rv.Type()).Implements(textMarshalerType) {
+		v = rv.Addr().Interface()
+	}
+
+	if tm, ok := v.(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+
+		return string(text), true, err
+	}
+
+	if _, ok := stringerTypes[rv.Type()]; ok {
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return "", false, nil
+		}
+		if z, ok := v.(interface{ IsZero() bool }); ok && z.IsZero() {
+			return "", true, nil // Converters decode "" to the zero value
+		}
+
+		return v.(fmt.Stringer).String(), true, nil //nolint:forcetypeassert // stringerTypes implement fmt.Stringer
+	}
+
+	return "", false, nil
+}
+
+// stringerTypes are the types without a MarshalText method marshaled by their String
+// method, which the default converters parse back. Other types implementing fmt.Stringer,
+// such as configuration structs describing themselves for logs, are not, as their String
+// output rarely decodes back into them.
+var stringerTypes = map[reflect.Type]struct{}{
+	reflect.TypeFor[Date]():             {},
+	reflect.TypeFor[TimeOfDay]():        {},
+	reflect.TypeFor[net.HardwareAddr](): {},
+}
+
+// textMarshalerType is the reflect.Type of encoding.TextMarshaler.
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// isEmptyValue reports whether rv is empty in the sense of the omitempty tag option.
+func isEmptyValue(rv reflect.Value) bool {
+	//nolint:exhaustive // Other kinds are empty when zero
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
@@ -0,0 +1,216 @@
+package mapstructure
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshaler_Marshal(t *testing.T) {
+	type Address struct {
+		City string `schema:"city"`
+		Zip  string `schema:"zip,omitempty"`
+	}
+	type Base struct {
+		ID int `schema:"id"`
+	}
+	type User struct {
+		Base
+		Name     string              `schema:"name"`
+		Nick     string              `schema:"nick,omitempty"`
+		Address  Address             `schema:"address"`
+		Previous *Address            `schema:"previous"`
+		Tags     []string            `schema:"tags"`
+		Scores   [2]int              `schema:"scores"`
+		Raw      []byte              `schema:"raw"`
+		Roles    map[string]struct{} `schema:"roles"`
+		Labels   map[string]int      `schema:"labels"`
+		Extra    any                 `schema:"extra"`
+		Skipped  string              `schema:"-"`
+		internal string
+	}
+
+	user := User{
+		Base:    Base{ID: 7},
+		Name:    "alice",
+		Address: Address{City: "Oslo"},
+		Tags:    []string{"a", "b"},
+		Scores:  [2]int{1, 2},
+		Raw:     []byte("hi"),
+		Roles:   map[string]struct{}{"b": {}, "a": {}},
+		Labels:  map[string]int{"x": 1},
+		Extra:   &Address{City: "Bergen"},
+		Skipped: "skip",
+	}
+
+	got, err := Marshal(&user)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"id":       7,
+		"name":     "alice",
+		"address":  map[string]any{"city": "Oslo"},
+		"previous": nil,
+		"tags":     []any{"a", "b"},
+		"scores":   []any{1, 2},
+		"raw":      []byte("hi"),
+		"roles":    []any{"a", "b"},
+		"labels":   map[string]any{"x": 1},
+		"extra":    map[string]any{"city": "Bergen"},
+	}, got)
+}
+
+func TestMarshaler_Marshal_EmbeddedPointers(t *testing.T) {
+	type Audit struct {
+		By string `schema:"by"`
+	}
+	type Record struct {
+		*Audit
+		Name string `schema:"name"`
+	}
+
+	got, err := Marshal(Record{Audit: &Audit{By: "me"}, Name: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"by": "me", "name": "a"}, got)
+
+	// Fields promoted from a nil embedded pointer are omitted
+	got, err = Marshal(Record{Name: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "a"}, got)
+}
+
+func TestMarshaler_Marshal_Text(t *testing.T) {
+	type Event struct {
+		Day  Date             `schema:"day"`
+		At   time.Time        `schema:"at"`
+		IP   netip.Addr       `schema:"ip"`
+		MAC  net.HardwareAddr `schema:"mac"`
+		Took time.Duration    `schema:"took"`
+	}
+
+	event := Event{
+		Day:  Date{Year: 2024, Month: time.March, Day: 5},
+		At:   time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		IP:   netip.MustParseAddr("10.0.0.1"),
+		MAC:  net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		Took: time.Second,
+	}
+
+	got, err := Marshal(event)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"day":  "2024-03-05",
+		"at":   "2024-03-05T10:00:00Z",
+		"ip":   "10.0.0.1",
+		"mac":  "00:01:02:03:04:05",
+		"took": time.Second,
+	}, got)
+
+	t.Run("zero date", func(t *testing.T) {
+		got, err := Marshal(Event{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "", got["day"])
+	})
+}
+
+// marshalDSN is a struct describing itself for logs.
+type marshalDSN struct {
+	User string `schema:"user"`
+	Host string `schema:"host"`
+}
+
+func (d marshalDSN) String() string { return "postgres://" + d.User + "@" + d.Host }
+
+// marshalTags is a named slice describing itself for logs.
+type marshalTags []string
+
+func (t marshalTags) String() string { return strings.Join(t, ",") }
+
+func TestMarshaler_Marshal_Stringers(t *testing.T) {
+	type Config struct {
+		DB   marshalDSN  `schema:"db"`
+		Tags marshalTags `schema:"tags"`
+	}
+
+	cfg := Config{DB: marshalDSN{User: "u", Host: "h"}, Tags: marshalTags{"a", "b"}}
+	got, err := Marshal(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"db":   map[string]any{"user": "u", "host": "h"},
+		"tags": []any{"a", "b"},
+	}, got, "String methods are not used")
+
+	_, err = RoundTrip[Config](nil, nil, got)
+	require.NoError(t, err)
+}
+
+func TestMarshaler_Marshal_Errors(t *testing.T) {
+	type node struct {
+		Name string `schema:"name"`
+		Next *node  `schema:"next"`
+	}
+
+	t.Run("not a struct", func(t *testing.T) {
+		for _, v := range []any{nil, 1, map[string]any{}, (*node)(nil)} {
+			_, err := Marshal(v)
+
+			var valErr *ValidationError
+			require.ErrorAs(t, err, &valErr, "%T", v)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		loop := &node{Name: "a"}
+		loop.Next = &node{Name: "b", Next: loop}
+
+		_, err := Marshal(loop)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "next.next", convErr.FieldPath)
+	})
+
+	t.Run("shared pointers are not cycles", func(t *testing.T) {
+		type pair struct {
+			A *node `schema:"a"`
+			B *node `schema:"b"`
+		}
+		shared := &node{Name: "s"}
+
+		got, err := Marshal(pair{A: shared, B: shared})
+
+		require.NoError(t, err)
+		assert.Equal(t, got["a"], got["b"])
+	})
+
+	t.Run("malformed tag", func(t *testing.T) {
+		type Bad struct {
+			Name string `schema:"name,enum='a"`
+		}
+
+		_, err := Marshal(Bad{})
+
+		var tagErr *TagError
+		require.ErrorAs(t, err, &tagErr)
+	})
+}
+
+func TestNewMarshaler_CustomTags(t *testing.T) {
+	type Config struct {
+		Name string `json:"name"`
+		Port int    `json:"port,omitempty"`
+	}
+
+	m := NewMarshaler(NewStructMetadataCache("json", ""))
+	got, err := m.Marshal(Config{Name: "api"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "api"}, got)
+}
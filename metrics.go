@@ -0,0 +1,43 @@
+package mapstructure
+
+import (
+	"reflect"
+	"time"
+)
+
+// Metrics receives observations of the work done by an Unmarshaler, so services can feed
+// counters and histograms of the metrics system of their choice. Implementations are
+// called synchronously from decoding goroutines and must be safe for concurrent use.
+type Metrics interface {
+	// ObserveDecode is called once per decode call with its duration, the type decoded
+	// and the error returned by the call, nil on success.
+	ObserveDecode(duration time.Duration, typ reflect.Type, err error)
+
+	// ObserveConversion is called for every registered converter invoked on a value,
+	// with the target type and the error returned by the converter. Converters that
+	// decline the value with ErrSkipConverter are not observed.
+	ObserveConversion(typ reflect.Type, err error)
+}
+
+// WithMetrics makes the unmarshaler report decodes and conversions to metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(u *Unmarshaler) {
+		u.metrics = metrics
+	}
+}
+
+// observeCall reports the end of a call, which returned err, to the stats and metrics
+// requested for it.
+func (u *Unmarshaler) observeCall(err error) {
+	if u.call.stats == nil && u.metrics == nil {
+		return
+	}
+
+	duration := time.Since(u.state.started)
+	if stats := u.call.stats; stats != nil {
+		stats.Duration = duration
+	}
+	if u.metrics != nil {
+		u.metrics.ObserveDecode(duration, u.state.target, err)
+	}
+}
@@ -0,0 +1,103 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeObservation struct {
+	typ reflect.Type
+	err error
+}
+
+type recordingMetrics struct {
+	mu          sync.Mutex
+	decodes     []decodeObservation
+	conversions []decodeObservation
+}
+
+func (m *recordingMetrics) ObserveDecode(duration time.Duration, typ reflect.Type, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.decodes = append(m.decodes, decodeObservation{typ: typ, err: err})
+}
+
+func (m *recordingMetrics) ObserveConversion(typ reflect.Type, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.conversions = append(m.conversions, decodeObservation{typ: typ, err: err})
+}
+
+func TestUnmarshaler_WithMetrics(t *testing.T) {
+	type Config struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port"`
+	}
+	configType := reflect.TypeFor[Config]()
+	intType := reflect.TypeFor[int]()
+
+	t.Run("successful decode", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		u := NewDefaultUnmarshaler(WithMetrics(metrics))
+
+		var result Config
+		require.NoError(t, u.Unmarshal(map[string]any{"host": "example.com", "port": "8080"}, &result))
+
+		require.Len(t, metrics.decodes, 1)
+		assert.Equal(t, configType, metrics.decodes[0].typ)
+		require.NoError(t, metrics.decodes[0].err)
+		assert.Equal(t, []decodeObservation{{typ: intType}}, metrics.conversions)
+	})
+
+	t.Run("failed conversion", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		u := NewDefaultUnmarshaler(WithMetrics(metrics))
+
+		var result Config
+		err := u.Unmarshal(map[string]any{"port": "nope"}, &result)
+		require.Error(t, err)
+
+		require.Len(t, metrics.decodes, 1)
+		assert.Equal(t, err, metrics.decodes[0].err)
+		require.Len(t, metrics.conversions, 1)
+		assert.Equal(t, intType, metrics.conversions[0].typ)
+		assert.Error(t, metrics.conversions[0].err)
+	})
+
+	t.Run("declined conversions are not observed", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		skip := func(value any) (reflect.Value, error) {
+			return reflect.Value{}, ErrSkipConverter
+		}
+		converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{intType: skip})
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters, WithMetrics(metrics))
+
+		var result Config
+		require.Error(t, u.Unmarshal(map[string]any{"port": "8080"}, &result))
+		assert.Empty(t, metrics.conversions)
+	})
+
+	t.Run("other entry points", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		u := NewDefaultUnmarshaler(WithMetrics(metrics))
+
+		_, err := NewDecoder[Config](u).Decode(map[string]any{"host": "a"})
+		require.NoError(t, err)
+		require.NoError(t, u.UnmarshalStrings(map[string]string{"port": "1"}, &Config{}))
+		_, err = u.ConvertValue("x", intType)
+		require.Error(t, err)
+
+		require.Len(t, metrics.decodes, 3)
+		assert.Equal(t, configType, metrics.decodes[0].typ)
+		assert.Equal(t, configType, metrics.decodes[1].typ)
+		assert.Equal(t, intType, metrics.decodes[2].typ)
+		var convErr *ConversionError
+		assert.True(t, errors.As(metrics.decodes[2].err, &convErr))
+	})
+}
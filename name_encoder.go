@@ -0,0 +1,82 @@
+package mapstructure
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameEncoder derives the map key of a struct field from its Go field name, see WithNameEncoder.
+type NameEncoder func(fieldName string) string
+
+// WithNameEncoder makes the marshaler key fields whose tag does not name a key by their Go
+// field name passed through encoder, e.g. SnakeCase, so structs follow a wire convention
+// without tagging every field. Fields named by their tag keep that key. Decoding still
+// reads untagged fields by their Go name; to decode the encoded keys, rewrite them with a
+// Transformer or tag the fields. encoder must not map two fields of a struct to one key.
+func WithNameEncoder(encoder NameEncoder) MarshalOption {
+	return func(m *Marshaler) {
+		m.encodeName = encoder
+	}
+}
+
+// fieldKey returns the key m marshals field under.
+func (m *Marshaler) fieldKey(field *FieldMetadata) string {
+	if m.encodeName == nil || field.named {
+		return field.MapKey
+	}
+
+	return m.encodeName(field.StructFieldName)
+}
+
+// SnakeCase is a NameEncoder converting a Go field name to snake_case, keeping initialisms
+// together: "HTTPServerURL" becomes "http_server_url" and "UserID" becomes "user_id".
+func SnakeCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitWords(fieldName), "_"))
+}
+
+// CamelCase is a NameEncoder converting a Go field name to camelCase by lowering its first
+// word: "HTTPServerURL" becomes "httpServerURL" and "ID" becomes "id".
+func CamelCase(fieldName string) string {
+	words := splitWords(fieldName)
+	if len(words) == 0 {
+		return fieldName
+	}
+	words[0] = strings.ToLower(words[0])
+
+	return strings.Join(words, "")
+}
+
+// splitWords splits a Go identifier into words at case changes: before an upper case
+// letter following a lower case letter or digit, and before the last letter of a run of
+// upper case letters followed by a lower case one. Underscores separate words too.
+func splitWords(name string) []string {
+	runes := []rune(name)
+
+	var words []string
+	start := 0
+	for i := range runes {
+		if runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+
+			continue
+		}
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+
+		prev := runes[i-1]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
@@ -0,0 +1,73 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameEncoders(t *testing.T) {
+	tests := []struct {
+		name  string
+		snake string
+		camel string
+	}{
+		{name: "Name", snake: "name", camel: "name"},
+		{name: "UserID", snake: "user_id", camel: "userID"},
+		{name: "ID", snake: "id", camel: "id"},
+		{name: "HTTPServerURL", snake: "http_server_url", camel: "httpServerURL"},
+		{name: "Port8080Addr", snake: "port8080_addr", camel: "port8080Addr"},
+		{name: "Retry_Count", snake: "retry_count", camel: "retryCount"},
+		{name: "X", snake: "x", camel: "x"},
+		{name: "", snake: "", camel: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.snake, SnakeCase(tt.name))
+			assert.Equal(t, tt.camel, CamelCase(tt.name))
+		})
+	}
+}
+
+func TestMarshaler_WithNameEncoder(t *testing.T) {
+	type Base struct {
+		CreatedAt string
+	}
+	type Server struct {
+		Base
+		HostName  string
+		MaxConns  int    `schema:",omitempty"`
+		PublicURL string `schema:"url"`
+		TLS       struct {
+			CertFile string
+		}
+	}
+
+	server := Server{Base: Base{CreatedAt: "today"}, HostName: "example.com", PublicURL: "https://example.com"}
+	server.TLS.CertFile = "cert.pem"
+
+	got, err := NewDefaultMarshaler(WithNameEncoder(SnakeCase)).Marshal(server)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"created_at": "today",
+		"host_name":  "example.com",
+		"url":        "https://example.com",
+		"tls":        map[string]any{"cert_file": "cert.pem"},
+	}, got)
+
+	got, err = NewDefaultMarshaler(WithNameEncoder(CamelCase)).Marshal(server)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"createdAt": "today",
+		"hostName":  "example.com",
+		"url":       "https://example.com",
+		"tls":       map[string]any{"certFile": "cert.pem"},
+	}, got)
+
+	// Without an encoder untagged fields keep their Go names
+	got, err = Marshal(server)
+	require.NoError(t, err)
+	assert.Contains(t, got, "HostName")
+}
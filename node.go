@@ -0,0 +1,164 @@
+package mapstructure
+
+import (
+	"iter"
+	"reflect"
+)
+
+// NodeKind identifies the kind of value held by a Node.
+type NodeKind int
+
+const (
+	// NullNode holds no value and decodes like nil.
+	NullNode NodeKind = iota
+
+	// BoolNode holds a bool, see Node.Bool.
+	BoolNode
+
+	// NumberNode holds a number, see Node.Number.
+	NumberNode
+
+	// StringNode holds a string, see Node.Text.
+	StringNode
+
+	// ObjectNode holds named child nodes, see Node.Fields.
+	ObjectNode
+
+	// ListNode holds a sequence of child nodes, see Node.Elems.
+	ListNode
+)
+
+// Node is a node of a tree of kind-tagged values, as used by RPC frameworks to carry
+// dynamic data, e.g. the google.protobuf.Value trees of structpb. Wrapping such a tree in
+// a Node lets the unmarshaler decode it without converting it to maps first: nodes are
+// converted a level at a time as decoding reaches them, so subtrees under keys that no
+// field reads are never visited.
+//
+// Only the accessor matching Kind is called.
+type Node interface {
+	Kind() NodeKind
+	Bool() bool
+	Number() float64
+	Text() string
+	Fields() iter.Seq2[string, Node]
+	Elems() iter.Seq[Node]
+}
+
+// UnmarshalNode decodes the object node into the struct pointed to by result using the
+// default unmarshaler. See Unmarshaler.UnmarshalNode.
+func UnmarshalNode(node Node, result any, opts ...CallOption) error {
+	return defaultUnmarshaler.UnmarshalNode(node, result, opts...)
+}
+
+// UnmarshalNode decodes the object node into the struct pointed to by result, with the
+// same rules as Unmarshal. Node values met while decoding, including those in maps
+// passed to Unmarshal, are converted to nil, bool, float64, string, map[string]any or
+// []any when decoding reaches them; fields of type any, []any or map[string]any receive
+// the whole subtree converted, and fields of an interface type the node implements
+// receive the node itself. Input transformers see the child objects and lists of the
+// map they transform as nodes.
+func (u *Unmarshaler) UnmarshalNode(node Node, result any, opts ...CallOption) error {
+	if node == nil || node.Kind() != ObjectNode {
+		return NewValidationError("node must be an object")
+	}
+
+	data, _ := nodeValue(node).(map[string]any)
+
+	return u.Unmarshal(data, result, opts...)
+}
+
+// nodeInput returns the value of node decoded into a value of type typ, see UnmarshalNode.
+func nodeInput(node Node, typ reflect.Type) any {
+	//nolint:exhaustive // Other kinds decode the node a level at a time
+	switch typ.Kind() {
+	case reflect.Interface:
+		if !isAnyType(typ) && reflect.TypeOf(node).Implements(typ) {
+			return node
+		}
+
+		return nodeTree(node)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if typ.Elem().Kind() == reflect.Interface {
+			return nodeTree(node)
+		}
+	}
+
+	return nodeValue(node)
+}
+
+// nodeValue returns the value of node: nil, a bool, float64 or string for scalar nodes,
+// and a map[string]any or []any for object and list nodes, holding the values of scalar
+// children and object and list children as nodes.
+func nodeValue(node Node) any {
+	switch node.Kind() {
+	case ObjectNode:
+		fields := make(map[string]any)
+		for key, child := range node.Fields() {
+			fields[key] = childValue(child)
+		}
+
+		return fields
+	case ListNode:
+		elems := []any{}
+		for child := range node.Elems() {
+			elems = append(elems, childValue(child))
+		}
+
+		return elems
+	default:
+		return scalarValue(node)
+	}
+}
+
+// childValue returns the value of a child node in the value of its parent, see nodeValue.
+func childValue(node Node) any {
+	if node == nil {
+		return nil
+	}
+	if kind := node.Kind(); kind == ObjectNode || kind == ListNode {
+		return node
+	}
+
+	return scalarValue(node)
+}
+
+// nodeTree returns the value of node with the values of all its descendants.
+func nodeTree(node Node) any {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind() {
+	case ObjectNode:
+		fields := make(map[string]any)
+		for key, child := range node.Fields() {
+			fields[key] = nodeTree(child)
+		}
+
+		return fields
+	case ListNode:
+		elems := []any{}
+		for child := range node.Elems() {
+			elems = append(elems, nodeTree(child))
+		}
+
+		return elems
+	default:
+		return scalarValue(node)
+	}
+}
+
+// scalarValue returns the value of a scalar node, nil for null and unknown kinds.
+func scalarValue(node Node) any {
+	//nolint:exhaustive // Object and list nodes are not scalars
+	switch node.Kind() {
+	case BoolNode:
+		return node.Bool()
+	case NumberNode:
+		return node.Number()
+	case StringNode:
+		return node.Text()
+	default:
+		return nil
+	}
+}
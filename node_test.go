@@ -0,0 +1,175 @@
+package mapstructure
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode is a kind-tagged tree node in the style of structpb.Value. visits counts
+// the calls to Fields and Elems, shared by all nodes of a tree.
+type testNode struct {
+	kind   NodeKind
+	b      bool
+	n      float64
+	s      string
+	fields map[string]*testNode
+	elems  []*testNode
+	visits *int
+}
+
+func (n *testNode) Kind() NodeKind  { return n.kind }
+func (n *testNode) Bool() bool      { return n.b }
+func (n *testNode) Number() float64 { return n.n }
+func (n *testNode) Text() string    { return n.s }
+
+func (n *testNode) Fields() iter.Seq2[string, Node] {
+	*n.visits++
+
+	return func(yield func(string, Node) bool) {
+		for _, key := range slices.Sorted(maps.Keys(n.fields)) {
+			if !yield(key, n.fields[key]) {
+				return
+			}
+		}
+	}
+}
+
+func (n *testNode) Elems() iter.Seq[Node] {
+	*n.visits++
+
+	return func(yield func(Node) bool) {
+		for _, elem := range n.elems {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// toTestNode builds the tree of value, made of maps, slices and scalars.
+func toTestNode(value any, visits *int) *testNode {
+	switch v := value.(type) {
+	case nil:
+		return &testNode{kind: NullNode, visits: visits}
+	case bool:
+		return &testNode{kind: BoolNode, b: v, visits: visits}
+	case float64:
+		return &testNode{kind: NumberNode, n: v, visits: visits}
+	case string:
+		return &testNode{kind: StringNode, s: v, visits: visits}
+	case map[string]any:
+		fields := make(map[string]*testNode, len(v))
+		for key, child := range v {
+			fields[key] = toTestNode(child, visits)
+		}
+
+		return &testNode{kind: ObjectNode, fields: fields, visits: visits}
+	case []any:
+		elems := make([]*testNode, len(v))
+		for i, child := range v {
+			elems[i] = toTestNode(child, visits)
+		}
+
+		return &testNode{kind: ListNode, elems: elems, visits: visits}
+	default:
+		panic("unsupported test value")
+	}
+}
+
+type nodeAddress struct {
+	City string `schema:"city"`
+}
+
+type nodeTarget struct {
+	Name      string         `schema:"name"`
+	Port      int            `schema:"port"`
+	Enabled   bool           `schema:"enabled"`
+	Nickname  *string        `schema:"nickname"`
+	Address   nodeAddress    `schema:"address"`
+	Previous  []*nodeAddress `schema:"previous"`
+	Tags      []string       `schema:"tags"`
+	Matrix    [][]int        `schema:"matrix"`
+	Extra     any            `schema:"extra"`
+	Labels    map[string]any `schema:"labels"`
+	Raw       Node           `schema:"raw"`
+	Untouched string         `schema:"untouched" default:"none"`
+}
+
+func TestUnmarshaler_UnmarshalNode(t *testing.T) {
+	var visits int
+	root := toTestNode(map[string]any{
+		"name":     "api",
+		"port":     8080.0,
+		"enabled":  true,
+		"nickname": nil,
+		"address":  map[string]any{"city": "Oslo"},
+		"previous": []any{map[string]any{"city": "Bergen"}, nil},
+		"tags":     []any{"a", "b"},
+		"matrix":   []any{[]any{1.0, 2.0}, []any{}},
+		"extra":    map[string]any{"nested": []any{1.0, "x"}},
+		"labels":   map[string]any{"team": "core"},
+		"raw":      map[string]any{"kept": true},
+		"ignored":  map[string]any{"deep": map[string]any{"deeper": []any{1.0}}},
+	}, &visits)
+
+	var result nodeTarget
+	require.NoError(t, NewDefaultUnmarshaler().UnmarshalNode(root, &result))
+
+	assert.Equal(t, "api", result.Name)
+	assert.Equal(t, 8080, result.Port)
+	assert.True(t, result.Enabled)
+	assert.Nil(t, result.Nickname)
+	assert.Equal(t, nodeAddress{City: "Oslo"}, result.Address)
+	assert.Equal(t, []*nodeAddress{{City: "Bergen"}, nil}, result.Previous)
+	assert.Equal(t, []string{"a", "b"}, result.Tags)
+	assert.Equal(t, [][]int{{1, 2}, {}}, result.Matrix)
+	assert.Equal(t, map[string]any{"nested": []any{1.0, "x"}}, result.Extra)
+	assert.Equal(t, map[string]any{"team": "core"}, result.Labels)
+	assert.Same(t, root.fields["raw"], result.Raw, "Node fields keep the node")
+	assert.Equal(t, "none", result.Untouched)
+
+	// The root, address, previous and its first element, tags, matrix and its two rows,
+	// extra and its list, and labels: the ignored subtree is never visited.
+	assert.Equal(t, 11, visits)
+}
+
+func TestUnmarshaler_UnmarshalNode_Errors(t *testing.T) {
+	var visits int
+
+	t.Run("root must be an object", func(t *testing.T) {
+		var result nodeTarget
+		err := UnmarshalNode(toTestNode([]any{"x"}, &visits), &result)
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+
+		require.ErrorAs(t, UnmarshalNode(nil, &result), &validationErr)
+	})
+
+	t.Run("conversion errors have paths", func(t *testing.T) {
+		var result nodeTarget
+		err := UnmarshalNode(toTestNode(map[string]any{"previous": []any{map[string]any{"city": []any{"x"}}}}, &visits), &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "previous[0].city", convErr.FieldPath)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_NodeValues(t *testing.T) {
+	var visits int
+	data := map[string]any{
+		"name":    toTestNode("api", &visits),
+		"address": toTestNode(map[string]any{"city": "Oslo"}, &visits),
+	}
+
+	var result nodeTarget
+	require.NoError(t, Unmarshal(data, &result))
+	assert.Equal(t, "api", result.Name)
+	assert.Equal(t, "Oslo", result.Address.City)
+}
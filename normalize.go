@@ -0,0 +1,143 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// String normalization tag options.
+const (
+	OptionTrim     = "trim"
+	OptionUpper    = "upper"
+	OptionLower    = "lower"
+	OptionSanitize = "sanitize"
+)
+
+// normalizeStrings applies the "trim", "upper", "lower" and "sanitize" tag options to a
+// decoded field value: to strings, including named string types, behind pointers and in
+// slices and arrays. Trimming happens first, then case folding, then the sanitizers in
+// the order listed. Values shared with the input are replaced rather than modified.
+func (u *Unmarshaler) normalizeStrings(rv reflect.Value, fieldPath string, field *FieldMetadata) error {
+	_, trim := field.Option(OptionTrim)
+	_, upper := field.Option(OptionUpper)
+	_, lower := field.Option(OptionLower)
+	names, sanitize := field.Option(OptionSanitize)
+	if !trim && !upper && !lower && !sanitize {
+		return nil
+	}
+	if upper && lower {
+		return NewConstraintError(fieldPath, OptionUpper, "upper and lower cannot be combined")
+	}
+
+	var sanitizers []Sanitizer
+	if sanitize {
+		for name := range strings.SplitSeq(names, "|") {
+			sanitizer, ok := u.sanitizer(strings.TrimSpace(name))
+			if !ok {
+				return NewConversionError(fieldPath, nil, rv.Type(), fmt.Errorf("unknown sanitizer %q", name))
+			}
+			sanitizers = append(sanitizers, sanitizer)
+		}
+	}
+
+	normalize := func(path, s string) (string, error) {
+		if trim {
+			s = strings.TrimSpace(s)
+		}
+		switch {
+		case upper:
+			s = strings.ToUpper(s)
+		case lower:
+			s = strings.ToLower(s)
+		}
+
+		for _, sanitizer := range sanitizers {
+			sanitized, err := sanitizer(path, s)
+			if err != nil {
+				return "", NewConversionError(path, s, rv.Type(), err)
+			}
+			s = sanitized
+		}
+
+		return s, nil
+	}
+
+	normalized, changed, err := normalizeValue(rv, fieldPath, normalize)
+	if err != nil {
+		return err
+	}
+	if changed {
+		rv.Set(normalized)
+	}
+
+	return nil
+}
+
+// normalizeValue returns rv with normalize applied to its strings and true, or rv and
+// false when no string changed. Changed pointers, slices and arrays are copied.
+func normalizeValue(rv reflect.Value, fieldPath string, normalize func(fieldPath, s string) (string, error)) (reflect.Value, bool, error) {
+	//nolint:exhaustive // Only strings and their containers are normalized
+	switch rv.Kind() {
+	case reflect.String:
+		s, err := normalize(fieldPath, rv.String())
+		if err != nil || s == rv.String() {
+			return rv, false, err
+		}
+
+		v := reflect.New(rv.Type()).Elem()
+		v.SetString(s)
+
+		return v, true, nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return rv, false, nil
+		}
+
+		elem, changed, err := normalizeValue(rv.Elem(), fieldPath, normalize)
+		if err != nil || !changed {
+			return rv, false, err
+		}
+
+		p := reflect.New(rv.Type().Elem())
+		p.Elem().Set(elem)
+
+		return p, true, nil
+	case reflect.Slice, reflect.Array:
+		var out reflect.Value
+		for i := range rv.Len() {
+			elem, changed, err := normalizeValue(rv.Index(i), fmt.Sprintf("%s[%d]", fieldPath, i), normalize)
+			if err != nil {
+				return rv, false, err
+			}
+			if !changed {
+				continue
+			}
+			if !out.IsValid() {
+				out = copyElements(rv)
+			}
+			out.Index(i).Set(elem)
+		}
+
+		if !out.IsValid() {
+			return rv, false, nil
+		}
+
+		return out, true, nil
+	default:
+		return rv, false, nil
+	}
+}
+
+// copyElements returns a settable shallow copy of the slice or array rv.
+func copyElements(rv reflect.Value) reflect.Value {
+	var c reflect.Value
+	if rv.Kind() == reflect.Slice {
+		c = reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+	} else {
+		c = reflect.New(rv.Type()).Elem()
+	}
+	reflect.Copy(c, rv)
+
+	return c
+}
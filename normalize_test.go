@@ -0,0 +1,73 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_NormalizeStrings(t *testing.T) {
+	type Code string
+	type Record struct {
+		Code    string    `schema:"code,trim,upper"`
+		Email   *string   `schema:"email,trim,lower"`
+		Tags    []string  `schema:"tags,trim,lower"`
+		Kind    Code      `schema:"kind,upper"`
+		Pair    [2]string `schema:"pair,trim"`
+		Name    string    `schema:"name,trim,minlen=2"`
+		Raw     string    `schema:"raw"`
+		Default string    `schema:"default,trim,upper" default:" eu "`
+	}
+
+	email := "  Bob@Example.COM "
+	tags := []string{" Go ", "rust"}
+	data := map[string]any{
+		"code":  "  ab-12 ",
+		"email": &email,
+		"tags":  tags,
+		"kind":  Code("basic"),
+		"pair":  [2]string{" a", "b "},
+		"name":  " ok ",
+		"raw":   "  as is ",
+	}
+
+	var result Record
+	err := Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, "AB-12", result.Code)
+	require.NotNil(t, result.Email)
+	assert.Equal(t, "bob@example.com", *result.Email)
+	assert.Equal(t, []string{"go", "rust"}, result.Tags)
+	assert.Equal(t, Code("BASIC"), result.Kind)
+	assert.Equal(t, [2]string{"a", "b"}, result.Pair)
+	assert.Equal(t, "ok", result.Name)
+	assert.Equal(t, "  as is ", result.Raw)
+	assert.Equal(t, "EU", result.Default)
+
+	assert.Equal(t, "  Bob@Example.COM ", email, "input pointer is not modified")
+	assert.Equal(t, []string{" Go ", "rust"}, tags, "input slice is not modified")
+
+	t.Run("length is checked after trimming", func(t *testing.T) {
+		var result Record
+		err := Unmarshal(map[string]any{"name": " a  "}, &result)
+
+		var constraintErr *ConstraintError
+		require.ErrorAs(t, err, &constraintErr)
+		assert.Equal(t, OptionMinLen, constraintErr.Constraint)
+	})
+
+	t.Run("upper and lower conflict", func(t *testing.T) {
+		type Bad struct {
+			Code string `schema:"code,upper,lower"`
+		}
+
+		var result Bad
+		err := Unmarshal(map[string]any{"code": "x"}, &result)
+
+		var constraintErr *ConstraintError
+		require.ErrorAs(t, err, &constraintErr)
+		assert.Equal(t, "code", constraintErr.FieldPath)
+	})
+}
@@ -0,0 +1,71 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+)
+
+// NullPolicy selects how the unmarshaler treats nil input for values that cannot be nil:
+// strings, bools, numbers, arrays and structs. Nil input always clears pointers, slices,
+// maps and interfaces.
+type NullPolicy int
+
+const (
+	// NullsError passes nil input to the converter of the type, which fails with a
+	// ConversionError for the built-in types. This is the default.
+	NullsError NullPolicy = iota
+
+	// NullsKeep leaves the value unchanged, as encoding/json does with null.
+	// Defaults do not apply, as the key is present.
+	NullsKeep
+)
+
+// WithNullPolicy sets how the unmarshaler treats nil input for values that cannot be nil.
+func WithNullPolicy(policy NullPolicy) Option {
+	return func(u *Unmarshaler) {
+		u.nullPolicy = policy
+	}
+}
+
+// NilInputPolicy selects how Unmarshal treats a nil input map.
+type NilInputPolicy int
+
+const (
+	// NilInputEmpty decodes a nil input map like an empty one, applying defaults.
+	// This is the default.
+	NilInputEmpty NilInputPolicy = iota
+
+	// NilInputError fails the call with ErrNilInput, leaving the result unchanged.
+	NilInputError
+)
+
+// ErrNilInput is returned for a nil input map under NilInputError.
+var ErrNilInput = errors.New("input map is nil")
+
+// WithNilInputPolicy sets how the unmarshaler treats a nil input map, for callers that
+// tell a missing document apart from an empty one.
+func WithNilInputPolicy(policy NilInputPolicy) Option {
+	return func(u *Unmarshaler) {
+		u.nilInput = policy
+	}
+}
+
+// checkNilInput returns ErrNilInput when the input map is nil under NilInputError.
+func (u *Unmarshaler) checkNilInput(isNil bool) error {
+	if isNil && u.nilInput == NilInputError {
+		return ErrNilInput
+	}
+
+	return nil
+}
+
+// isNillable reports whether values of kind can be nil.
+func isNillable(kind reflect.Kind) bool {
+	//nolint:exhaustive // Only kinds with a nil value qualify
+	switch kind {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,100 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nullsTarget struct {
+	Name   string          `schema:"name"`
+	Count  int             `schema:"count"`
+	Point  struct{ X int } `schema:"point"`
+	Ptr    *int            `schema:"ptr"`
+	Tags   []string        `schema:"tags"`
+	Labels map[string]any  `schema:"labels"`
+	Extra  any             `schema:"extra"`
+}
+
+func TestUnmarshaler_Unmarshal_NullPolicy(t *testing.T) {
+	one := 1
+	seeded := func() nullsTarget {
+		return nullsTarget{
+			Name: "keep", Count: 5, Point: struct{ X int }{X: 1}, Ptr: &one,
+			Tags: []string{"a"}, Labels: map[string]any{"a": 1}, Extra: "x",
+		}
+	}
+
+	t.Run("nil clears nillable values by default", func(t *testing.T) {
+		result := seeded()
+		data := map[string]any{"ptr": nil, "tags": nil, "labels": nil, "extra": nil}
+		require.NoError(t, NewDefaultUnmarshaler().Unmarshal(data, &result))
+		assert.Equal(t, nullsTarget{Name: "keep", Count: 5, Point: struct{ X int }{X: 1}}, result)
+	})
+
+	t.Run("error by default", func(t *testing.T) {
+		for _, key := range []string{"name", "count", "point"} {
+			result := seeded()
+			err := NewDefaultUnmarshaler().Unmarshal(map[string]any{key: nil}, &result)
+
+			var convErr *ConversionError
+			require.ErrorAs(t, err, &convErr, key)
+			assert.Equal(t, key, convErr.FieldPath)
+		}
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		result := seeded()
+		data := map[string]any{"name": nil, "count": nil, "point": nil, "ptr": nil, "tags": nil, "labels": nil, "extra": nil}
+		require.NoError(t, NewDefaultUnmarshaler(WithNullPolicy(NullsKeep)).Unmarshal(data, &result))
+		assert.Equal(t, nullsTarget{Name: "keep", Count: 5, Point: struct{ X int }{X: 1}}, result)
+	})
+
+	t.Run("keep does not fall back to defaults", func(t *testing.T) {
+		var result struct {
+			Count int `schema:"count" default:"3"`
+		}
+		require.NoError(t, NewDefaultUnmarshaler(WithNullPolicy(NullsKeep)).Unmarshal(map[string]any{"count": nil}, &result))
+		assert.Zero(t, result.Count)
+	})
+
+	t.Run("keep applies to slice elements", func(t *testing.T) {
+		var result struct {
+			Counts []int `schema:"counts"`
+		}
+		data := map[string]any{"counts": []any{1, nil, 3}}
+		require.NoError(t, NewDefaultUnmarshaler(WithNullPolicy(NullsKeep)).Unmarshal(data, &result))
+		assert.Equal(t, []int{1, 0, 3}, result.Counts)
+	})
+}
+
+func TestUnmarshaler_NilInputPolicy(t *testing.T) {
+	type Config struct {
+		Port int `schema:"port" default:"8080"`
+	}
+
+	t.Run("empty by default", func(t *testing.T) {
+		var cfg Config
+		require.NoError(t, Unmarshal(nil, &cfg))
+		assert.Equal(t, 8080, cfg.Port)
+
+		cfg = Config{}
+		require.NoError(t, NewDefaultUnmarshaler().UnmarshalStrings(nil, &cfg))
+		assert.Equal(t, 8080, cfg.Port)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		u := NewDefaultUnmarshaler(WithNilInputPolicy(NilInputError))
+
+		cfg := Config{Port: 1}
+		require.ErrorIs(t, u.Unmarshal(nil, &cfg), ErrNilInput)
+		assert.Equal(t, 1, cfg.Port, "result unchanged")
+		require.ErrorIs(t, u.UnmarshalStrings(nil, &cfg), ErrNilInput)
+		require.ErrorIs(t, NewDecoder[Config](u).DecodeTo(&cfg, nil), ErrNilInput)
+
+		// Empty maps still apply defaults
+		require.NoError(t, u.Unmarshal(map[string]any{}, &cfg))
+		assert.Equal(t, 8080, cfg.Port)
+	})
+}
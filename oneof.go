@@ -0,0 +1,88 @@
+package mapstructure
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mutually exclusive field tag options: fields sharing a "oneof" group name may not be set
+// together, and one of them must be set when any is also tagged "required".
+const (
+	OptionOneOf    = "oneof"
+	OptionRequired = "required"
+)
+
+// oneOfGroup describes fields sharing a "oneof" tag option: at most one of them may be
+// present in the input, and exactly one when any of them is tagged "required".
+// Groups are scoped to the struct declaring their fields, so embedded structs may
+// reuse group names.
+type oneOfGroup struct {
+	name     string
+	owner    int   // owner of the fields, see FieldMetadata
+	fields   []int // Positions in StructMetadata.flat
+	required bool
+}
+
+// buildOneOfGroups collects the "oneof" groups of fields in declaration order.
+func buildOneOfGroups(fields []FieldMetadata) []oneOfGroup {
+	var groups []oneOfGroup
+
+	for i := range fields {
+		name, ok := fields[i].Option(OptionOneOf)
+		if !ok || name == "" {
+			continue
+		}
+
+		idx := -1
+		for j := range groups {
+			if groups[j].name == name && groups[j].owner == fields[i].owner {
+				idx = j
+
+				break
+			}
+		}
+		if idx < 0 {
+			groups = append(groups, oneOfGroup{name: name, owner: fields[i].owner})
+			idx = len(groups) - 1
+		}
+
+		groups[idx].fields = append(groups[idx].fields, i)
+		if _, required := fields[i].Option(OptionRequired); required {
+			groups[idx].required = true
+		}
+	}
+
+	return groups
+}
+
+// checkOneOf enforces the "oneof" groups of metadata against dataMap.
+// Keys with nil values count as absent; fields not selected for the call, and fields
+// promoted from embedded structs that closedEmbeds reported closed, are ignored.
+func (u *Unmarshaler) checkOneOf(dataMap map[string]any, metadata *StructMetadata, closed []bool, fieldPath string) error {
+	for _, group := range metadata.oneOf {
+		var present, candidates []string
+
+		for _, i := range group.fields {
+			field := &metadata.flat[i]
+			if !isOpen(closed, field) || !u.fieldSelected(field) {
+				continue
+			}
+
+			candidates = append(candidates, fmt.Sprintf("%q", field.MapKey))
+			if v, ok := dataMap[field.MapKey]; ok && v != nil {
+				present = append(present, fmt.Sprintf("%q", field.MapKey))
+			}
+		}
+
+		switch {
+		case len(present) > 1:
+			return NewConstraintError(fieldPath, OptionOneOf, fmt.Sprintf("only one of %s may be set (oneof %q), got %s",
+				strings.Join(candidates, ", "), group.name, strings.Join(present, ", ")))
+		case len(present) == 0 && group.required && len(candidates) > 0:
+			return NewConstraintError(fieldPath, OptionOneOf, fmt.Sprintf("one of %s must be set (oneof %q)",
+				strings.Join(candidates, ", "), group.name))
+		}
+	}
+
+	return nil
+}
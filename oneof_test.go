@@ -0,0 +1,74 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructMetadataCache_OneOfGroups(t *testing.T) {
+	type Payment struct {
+		Card   string `schema:"card,oneof=method"`
+		IBAN   string `schema:"iban,oneof=method,required"`
+		Email  string `schema:"email,oneof=contact"`
+		Amount int    `schema:"amount"`
+		Phone  string `schema:"phone,oneof=contact"`
+	}
+
+	metadata := NewDefaultStructMetadataCache().GetMetadata(reflect.TypeOf(Payment{}))
+
+	assert.Equal(t, []oneOfGroup{
+		{name: "method", fields: []int{0, 1}, required: true},
+		{name: "contact", fields: []int{2, 4}},
+	}, metadata.oneOf)
+}
+
+func TestUnmarshaler_Unmarshal_OneOf(t *testing.T) {
+	type Contact struct {
+		Email string `schema:"email,oneof=contact"`
+		Phone string `schema:"phone,oneof=contact"`
+	}
+
+	type Payment struct {
+		Card   string  `schema:"card,oneof=method,required"`
+		IBAN   *string `schema:"iban,oneof=method"`
+		Crypto string  `schema:"crypto,oneof=method,groups=beta"`
+		Contact
+		Payer Contact `schema:"payer"`
+	}
+
+	tests := []struct {
+		name    string
+		data    map[string]any
+		opts    []CallOption
+		wantErr string
+	}{
+		{name: "one set", data: map[string]any{"card": "4111"}},
+		{name: "nil counts as absent", data: map[string]any{"card": "4111", "iban": nil}},
+		{name: "two set", data: map[string]any{"card": "4111", "iban": "DE00"}, wantErr: `root: only one of "card", "iban" may be set (oneof "method"), got "card", "iban"`},
+		{name: "required group empty", data: map[string]any{}, wantErr: `root: one of "card", "iban" must be set (oneof "method")`},
+		{name: "unselected field ignored", data: map[string]any{"card": "4111", "crypto": "btc"}},
+		{name: "selected group field counts", data: map[string]any{"card": "4111", "crypto": "btc"}, opts: []CallOption{WithGroups("beta")}, wantErr: `got "card", "crypto"`},
+		{name: "promoted fields checked", data: map[string]any{"card": "4111", "email": "a@b", "phone": "1"}, wantErr: `oneof "contact"`},
+		{name: "nested struct checked", data: map[string]any{"card": "4111", "payer": map[string]any{"email": "a@b", "phone": "1"}}, wantErr: "payer: only one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Payment
+			err := NewDefaultUnmarshaler().Unmarshal(tt.data, &result, tt.opts...)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var constraintErr *ConstraintError
+			require.ErrorAs(t, err, &constraintErr)
+			assert.Equal(t, "oneof", constraintErr.Constraint)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
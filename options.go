@@ -0,0 +1,29 @@
+package mapstructure
+
+// Option configures a setting of an Unmarshaler, such as a policy or a limit; a later
+// option for the same setting replaces an earlier one. Registries of types or names,
+// such as WithValidators, WithHooks, WithSanitizers and WithExprFuncs, are methods
+// returning a new unmarshaler instead, as each call extends the entries already
+// registered, so a derived unmarshaler adds to those of its base without restating them.
+type Option func(*Unmarshaler)
+
+// applyOptions applies opts to u.
+func (u *Unmarshaler) applyOptions(opts []Option) {
+	for _, opt := range opts {
+		opt(u)
+	}
+}
+
+// With returns a new unmarshaler derived from u with opts applied on top of its settings,
+// e.g. a strict API decoder and a lenient config decoder built from one base. The derived
+// unmarshaler shares the metadata cache, converter registry and registered hooks of u,
+// so creating it is cheap and struct metadata is built once for both. u is left unchanged.
+func (u *Unmarshaler) With(opts ...Option) *Unmarshaler {
+	derived := *u
+	if derived.defaults != nil {
+		derived.defaults = &defaultCache{} // The options may change how defaults convert
+	}
+	derived.applyOptions(opts)
+
+	return &derived
+}
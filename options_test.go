@@ -0,0 +1,36 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_With(t *testing.T) {
+	type Node struct {
+		Name  string `schema:"name"`
+		Child *Node  `schema:"child"`
+	}
+	data := map[string]any{"name": "a", "child": map[string]any{"name": "b", "child": map[string]any{"name": "c"}}}
+
+	base := NewDefaultUnmarshaler(WithIterationStrategy(IterateKeys))
+	strict := base.With(WithMaxDepth(2))
+
+	assert.Same(t, base.fieldCache, strict.fieldCache, "metadata cache is shared")
+	assert.Same(t, base.converters, strict.converters, "registry is shared")
+	assert.Equal(t, IterateKeys, strict.Config().Iteration, "base settings are kept")
+	assert.Zero(t, base.Config().MaxDepth, "base is unchanged")
+
+	var lenient Node
+	require.NoError(t, base.Unmarshal(data, &lenient))
+	assert.Equal(t, "c", lenient.Child.Child.Name)
+
+	var limited Node
+	var constraintErr *ConstraintError
+	require.ErrorAs(t, strict.Unmarshal(data, &limited), &constraintErr)
+
+	// Later options override earlier ones
+	relaxed := strict.With(WithMaxDepth(0))
+	require.NoError(t, relaxed.Unmarshal(data, &limited))
+}
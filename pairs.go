@@ -0,0 +1,112 @@
+package mapstructure
+
+import "fmt"
+
+// KV is a key/value pair of ordered input, see UnmarshalPairs.
+type KV struct {
+	Key   string
+	Value any
+}
+
+// DuplicateKeyPolicy selects how UnmarshalPairs treats keys given more than once.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicatesLastWins decodes the last value given for a key, as later lines of a
+	// configuration file override earlier ones. This is the default.
+	DuplicatesLastWins DuplicateKeyPolicy = iota
+
+	// DuplicatesFirstWins decodes the first value given for a key, ignoring later ones.
+	DuplicatesFirstWins
+
+	// DuplicatesError fails with a ConstraintError for the second value given for a key.
+	DuplicatesError
+)
+
+// WithDuplicateKeyPolicy sets how the unmarshaler treats keys given more than once in ordered input.
+func WithDuplicateKeyPolicy(policy DuplicateKeyPolicy) Option {
+	return func(u *Unmarshaler) {
+		u.duplicates = policy
+	}
+}
+
+// UnmarshalPairs transforms ordered key/value pairs into a Go struct pointed to by result.
+// This is a convenience function that uses a shared default unmarshaler.
+func UnmarshalPairs(pairs []KV, result any, opts ...CallOption) error {
+	return defaultUnmarshaler.UnmarshalPairs(pairs, result, opts...)
+}
+
+// UnmarshalPairs transforms ordered key/value pairs, as supplied by TOML or INI parsers
+// and HTTP trailers, into a Go struct pointed to by result. Keys given more than once are
+// resolved in input order under the duplicate key policy, and values that are []KV
+// themselves, directly or as elements of []any, decode like nested maps. The pairs are
+// then decoded exactly like the equivalent map with Unmarshal; nil pairs are a nil map.
+// opts configure this call only, e.g. WithGroups("admin").
+func (u *Unmarshaler) UnmarshalPairs(pairs []KV, result any, opts ...CallOption) error {
+	data, err := u.pairsMap(pairs, "")
+	if err != nil {
+		return err
+	}
+
+	return u.Unmarshal(data, result, opts...)
+}
+
+// pairsMap returns pairs at fieldPath as a map, resolving duplicate keys.
+func (u *Unmarshaler) pairsMap(pairs []KV, fieldPath string) (map[string]any, error) {
+	if pairs == nil {
+		return nil, nil
+	}
+
+	data := make(map[string]any, len(pairs))
+	for _, pair := range pairs {
+		keyPath := buildFieldPath(fieldPath, pair.Key)
+		value, err := u.pairsValue(pair.Value, keyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, seen := data[pair.Key]; seen {
+			switch u.duplicates {
+			case DuplicatesFirstWins:
+				continue
+			case DuplicatesError:
+				return nil, NewConstraintError(keyPath, "duplicate", fmt.Sprintf("duplicate key %q", pair.Key))
+			case DuplicatesLastWins:
+			}
+		}
+		data[pair.Key] = value
+	}
+
+	return data, nil
+}
+
+// pairsValue returns value at fieldPath with the pairs it holds turned into maps.
+func (u *Unmarshaler) pairsValue(value any, fieldPath string) (any, error) {
+	switch v := value.(type) {
+	case []KV:
+		return u.pairsMap(v, fieldPath)
+	case []any:
+		var elems []any // Copy of v, made when an element changes
+		for i, elem := range v {
+			pairs, ok := elem.([]KV)
+			if !ok {
+				continue
+			}
+
+			converted, err := u.pairsMap(pairs, fmt.Sprintf("%s[%d]", fieldPath, i))
+			if err != nil {
+				return nil, err
+			}
+			if elems == nil {
+				elems = append([]any(nil), v...)
+			}
+			elems[i] = converted
+		}
+
+		if elems != nil {
+			return elems, nil
+		}
+	}
+
+	return value, nil
+}
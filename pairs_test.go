@@ -0,0 +1,110 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_UnmarshalPairs(t *testing.T) {
+	type Server struct {
+		Name string `schema:"name"`
+		Port int    `schema:"port"`
+	}
+	type Config struct {
+		Title   string         `schema:"title"`
+		Owner   Server         `schema:"owner"`
+		Servers []Server       `schema:"servers"`
+		Extra   map[string]any `schema:",remain"`
+	}
+
+	tests := []struct {
+		name    string
+		policy  DuplicateKeyPolicy
+		pairs   []KV
+		want    Config
+		wantErr string
+	}{
+		{
+			name:  "flat pairs",
+			pairs: []KV{{"title", "app"}, {"mode", "dev"}},
+			want:  Config{Title: "app", Extra: map[string]any{"mode": "dev"}},
+		},
+		{
+			name: "nested pairs",
+			pairs: []KV{
+				{"owner", []KV{{"name", "a"}, {"port", "80"}}},
+				{"servers", []any{[]KV{{"name", "b"}}, map[string]any{"name": "c"}}},
+			},
+			want: Config{Owner: Server{Name: "a", Port: 80}, Servers: []Server{{Name: "b"}, {Name: "c"}}},
+		},
+		{
+			name:  "last value wins by default",
+			pairs: []KV{{"title", "a"}, {"title", "b"}},
+			want:  Config{Title: "b"},
+		},
+		{
+			name:   "first value wins",
+			policy: DuplicatesFirstWins,
+			pairs:  []KV{{"title", "a"}, {"title", "b"}, {"owner", []KV{{"port", 1}, {"port", 2}}}},
+			want:   Config{Title: "a", Owner: Server{Port: 1}},
+		},
+		{
+			name:    "duplicates rejected",
+			policy:  DuplicatesError,
+			pairs:   []KV{{"servers", []any{[]KV{{"name", "a"}, {"name", "b"}}}}},
+			wantErr: `servers[0].name: duplicate key "name"`,
+		},
+		{
+			name:    "decoding errors",
+			pairs:   []KV{{"owner", []KV{{"port", "http"}}}},
+			wantErr: "owner.port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Config
+			err := NewDefaultUnmarshaler(WithDuplicateKeyPolicy(tt.policy)).UnmarshalPairs(tt.pairs, &got)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshalPairs(t *testing.T) {
+	type Config struct {
+		Host string `schema:"host" default:"localhost"`
+	}
+
+	t.Run("default unmarshaler", func(t *testing.T) {
+		var got Config
+		require.NoError(t, UnmarshalPairs([]KV{{"host", "a"}, {"host", "b"}}, &got))
+		assert.Equal(t, Config{Host: "b"}, got)
+	})
+
+	t.Run("nil pairs", func(t *testing.T) {
+		var got Config
+		require.NoError(t, UnmarshalPairs(nil, &got))
+		assert.Equal(t, Config{Host: "localhost"}, got)
+
+		err := NewDefaultUnmarshaler(WithNilInputPolicy(NilInputError)).UnmarshalPairs(nil, &got)
+		assert.ErrorIs(t, err, ErrNilInput)
+	})
+
+	t.Run("input unchanged", func(t *testing.T) {
+		servers := []any{[]KV{{"host", "a"}}}
+		var got struct {
+			Servers []Config `schema:"servers"`
+		}
+		require.NoError(t, UnmarshalPairs([]KV{{"servers", servers}}, &got))
+		assert.Equal(t, []any{[]KV{{"host", "a"}}}, servers)
+	})
+}
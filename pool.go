@@ -0,0 +1,64 @@
+package mapstructure
+
+import (
+	"reflect"
+	"sync"
+)
+
+// DecodeTo decodes data into target, a value typically reused across records to reduce
+// allocations in ingestion loops. target is reset to the zero value first, so nothing
+// decoded from a previous record leaks into this one; decode into a live struct with
+// Unmarshal to merge input instead.
+// opts configure this call only, e.g. WithGroups("admin").
+func (d *Decoder[T]) DecodeTo(target *T, data map[string]any, opts ...CallOption) error {
+	if target == nil {
+		return NewValidationError("target is nil")
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	var zero T
+	*target = zero
+	rv := reflect.ValueOf(target).Elem()
+
+	return d.u.run(data, rv.Type(), opts, func(call *Unmarshaler) error {
+		if d.metadata == nil || len(call.hooks) > 0 { // Hooks also see the top-level map
+			return call.unmarshalValue(data, rv, "", nil)
+		}
+
+		return call.decodeStruct(data, rv, d.metadata, "")
+	})
+}
+
+// DecodeBatchTo is DecodeBatch decoding into targets obtained from newTarget, such as
+// PoolTargets of a caller's sync.Pool, instead of allocating them. Each target is reset
+// as by DecodeTo. The caller owns the returned targets and returns them to their pool
+// once processed. Errors are aligned with items, nil when every item decoded.
+func (d *Decoder[T]) DecodeBatchTo(items []map[string]any, newTarget func() *T, opts ...CallOption) (results []*T, errs []error) {
+	results = make([]*T, len(items))
+	for i, item := range items {
+		results[i] = newTarget()
+		if err := d.DecodeTo(results[i], item, opts...); err != nil {
+			if errs == nil {
+				errs = make([]error, len(items))
+			}
+			errs[i] = err
+		}
+	}
+
+	return results, errs
+}
+
+// PoolTargets returns a target factory for DecodeBatchTo taking values from pool, which
+// should hold *T values. New targets are allocated when the pool is empty and has no
+// New function, or returns a value of another type.
+func PoolTargets[T any](pool *sync.Pool) func() *T {
+	return func() *T {
+		if target, ok := pool.Get().(*T); ok && target != nil {
+			return target
+		}
+
+		return new(T)
+	}
+}
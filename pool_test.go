@@ -0,0 +1,85 @@
+package mapstructure
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pooledEvent struct {
+	ID     int      `schema:"id"`
+	Source string   `schema:"source" default:"api"`
+	Tags   []string `schema:"tags"`
+	Note   *string  `schema:"note"`
+}
+
+func TestDecoder_DecodeTo(t *testing.T) {
+	decoder := NewDecoder[pooledEvent](nil)
+	note := "stale"
+	target := &pooledEvent{ID: 9, Source: "old", Tags: []string{"x"}, Note: &note}
+
+	require.NoError(t, decoder.DecodeTo(target, map[string]any{"id": "1"}))
+	assert.Equal(t, &pooledEvent{ID: 1, Source: "api"}, target, "fields of the previous record are reset")
+
+	err := decoder.DecodeTo(nil, map[string]any{})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestDecoder_DecodeBatchTo(t *testing.T) {
+	decoder := NewDecoder[pooledEvent](nil)
+
+	t.Run("targets come from the factory", func(t *testing.T) {
+		var created []*pooledEvent
+		factory := func() *pooledEvent {
+			target := &pooledEvent{ID: -1, Tags: []string{"stale"}}
+			created = append(created, target)
+
+			return target
+		}
+
+		results, errs := decoder.DecodeBatchTo([]map[string]any{{"id": 1}, {"id": "bad"}, {"id": 3, "tags": []any{"a"}}}, factory)
+		require.Len(t, errs, 3)
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1])
+		assert.NoError(t, errs[2])
+		assert.Equal(t, created, results)
+		assert.Equal(t, &pooledEvent{ID: 1, Source: "api"}, results[0])
+		assert.Equal(t, &pooledEvent{ID: 3, Source: "api", Tags: []string{"a"}}, results[2])
+	})
+
+	t.Run("reuse through a sync.Pool", func(t *testing.T) {
+		pool := &sync.Pool{}
+		items := []map[string]any{{"id": 1, "tags": []any{"a"}}, {"id": 2}}
+
+		first, errs := decoder.DecodeBatchTo(items, PoolTargets[pooledEvent](pool))
+		require.Nil(t, errs)
+		for _, target := range first {
+			pool.Put(target)
+		}
+
+		second, errs := decoder.DecodeBatchTo(items[1:], PoolTargets[pooledEvent](pool))
+		require.Nil(t, errs)
+		assert.Equal(t, &pooledEvent{ID: 2, Source: "api"}, second[0], "reused targets are reset")
+	})
+}
+
+func TestPoolTargets(t *testing.T) {
+	t.Run("empty pool allocates", func(t *testing.T) {
+		target := PoolTargets[pooledEvent](&sync.Pool{})()
+		assert.NotNil(t, target)
+	})
+
+	t.Run("pool New is used", func(t *testing.T) {
+		made := &pooledEvent{ID: 7}
+		pool := &sync.Pool{New: func() any { return made }}
+		assert.Same(t, made, PoolTargets[pooledEvent](pool)())
+	})
+
+	t.Run("values of another type are ignored", func(t *testing.T) {
+		pool := &sync.Pool{New: func() any { return "not an event" }}
+		assert.NotNil(t, PoolTargets[pooledEvent](pool)())
+	})
+}
@@ -0,0 +1,134 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// WithPreparedDefaults makes the unmarshaler convert each default tag once and reuse the
+// converted value on later decodes of the field, cutting the parse cost of hot structs
+// whose defaults apply on most calls. Values are cached per unmarshaler, since its
+// converters and options decide the conversion; unmarshalers derived with With start
+// with an empty cache. Only defaults of types holding no pointers, slices, maps or
+// interfaces are cached, such as numbers, strings, durations and fixed-size arrays of
+// them; template defaults, the defaults of embedded structs and fields with an expr
+// option are converted on every decode as before. Converters of cached types must not
+// depend on the call, e.g. through ConvertContext.Value.
+func WithPreparedDefaults() Option {
+	return func(u *Unmarshaler) {
+		u.defaults = &defaultCache{}
+	}
+}
+
+// defaultCache holds the converted defaults of fields, see WithPreparedDefaults.
+type defaultCache struct {
+	values sync.Map // *FieldMetadata to its converted default as a reflect.Value, or noDefaultValue
+}
+
+// noDefaultValue marks fields whose default is not cached.
+type noDefaultValue struct{}
+
+// PrepareDefaults converts the default tags of the fields of the struct types typs, or
+// pointers to them, ahead of the first decode, for unmarshalers created with
+// WithPreparedDefaults. Fields promoted from embedded structs are included, fields of
+// nested struct types are not. Besides moving the conversion cost to startup, it reports
+// invalid defaults before input is decoded. It has no effect on other unmarshalers.
+func (u *Unmarshaler) PrepareDefaults(typs ...reflect.Type) error {
+	if u.defaults == nil {
+		return nil
+	}
+
+	for _, typ := range typs {
+		if typ == nil || structType(typ).Kind() != reflect.Struct {
+			return NewValidationError(fmt.Sprintf("cannot prepare defaults of %v: type must be a struct or a pointer to a struct", typ))
+		}
+
+		metadata := u.fieldCache.GetMetadata(structType(typ))
+		if metadata.tagErr != nil {
+			return metadata.tagErr
+		}
+
+		for i := range metadata.flat {
+			field := &metadata.flat[i]
+			if field.Default == nil || field.Embedded || field.defaultTemplate != nil {
+				continue
+			}
+
+			if _, err := u.preparedDefault(field, field.MapKey); err != nil {
+				return fmt.Errorf("%v: %w", typ, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// unmarshalDefault decodes the default tag of field into fieldValue, from the cache
+// when the unmarshaler prepares defaults.
+func (u *Unmarshaler) unmarshalDefault(fieldValue reflect.Value, fieldPath string, field *FieldMetadata) error {
+	if u.defaults == nil {
+		return u.unmarshalValue(*field.Default, fieldValue, fieldPath, field)
+	}
+
+	prepared, err := u.preparedDefault(field, fieldPath)
+	if err != nil {
+		return err
+	}
+	if !prepared.IsValid() {
+		return u.unmarshalValue(*field.Default, fieldValue, fieldPath, field)
+	}
+
+	fieldValue.Set(prepared)
+
+	return nil
+}
+
+// preparedDefault returns the cached default of field, converting it on first use,
+// or the zero Value when the default of field is not cached.
+func (u *Unmarshaler) preparedDefault(field *FieldMetadata, fieldPath string) (reflect.Value, error) {
+	if cached, ok := u.defaults.values.Load(field); ok {
+		prepared, _ := cached.(reflect.Value)
+
+		return prepared, nil
+	}
+
+	if _, isExpr := field.Option(OptionExpr); isExpr || !referenceFree(field.Type) {
+		u.defaults.values.Store(field, noDefaultValue{})
+
+		return reflect.Value{}, nil
+	}
+
+	prepared := reflect.New(field.Type).Elem()
+	if err := u.unmarshalValue(*field.Default, prepared, fieldPath, field); err != nil {
+		return reflect.Value{}, err
+	}
+	u.defaults.values.Store(field, prepared)
+
+	return prepared, nil
+}
+
+// referenceFree reports whether values of typ hold no pointers, slices, maps, interfaces,
+// funcs or chans, so copies of a value never share memory.
+func referenceFree(typ reflect.Type) bool {
+	//nolint:exhaustive // Other kinds hold references
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return referenceFree(typ.Elem())
+	case reflect.Struct:
+		for i := range typ.NumField() {
+			if !referenceFree(typ.Field(i).Type) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
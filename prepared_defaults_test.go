@@ -0,0 +1,93 @@
+package mapstructure
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type preparedLevel int
+
+func TestUnmarshaler_Unmarshal_PreparedDefaults(t *testing.T) {
+	type Server struct {
+		Host  string        `schema:"host" default:"localhost"`
+		Port  int           `schema:"port" default:"8080"`
+		Level preparedLevel `schema:"level" default:"warn"`
+		Limit *int          `schema:"limit" default:"10"`
+		Cents int           `schema:"cents,expr=value*100" default:"2"`
+	}
+
+	var calls atomic.Int32
+	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[preparedLevel](): func(value any) (reflect.Value, error) {
+			calls.Add(1)
+			levels := map[any]preparedLevel{"info": 1, "warn": 2}
+
+			return reflect.ValueOf(levels[value]), nil
+		},
+	})
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters, WithPreparedDefaults())
+
+	var first, second Server
+	require.NoError(t, u.Unmarshal(map[string]any{}, &first))
+	require.NoError(t, u.Unmarshal(map[string]any{"level": "info"}, &second))
+	require.NoError(t, u.Unmarshal(map[string]any{"port": 1}, &second))
+
+	ten := 10
+	assert.Equal(t, Server{Host: "localhost", Port: 8080, Level: 2, Limit: &ten, Cents: 200}, first)
+	assert.Equal(t, Server{Host: "localhost", Port: 1, Level: 2, Limit: &ten, Cents: 200}, second)
+	assert.Equal(t, int32(2), calls.Load(), "default converted once, input converted once")
+	assert.NotSame(t, first.Limit, second.Limit, "pointer defaults decoded on every call")
+
+	t.Run("derived unmarshalers convert again", func(t *testing.T) {
+		derived := u.With(WithNullPolicy(NullsKeep))
+		var got Server
+		require.NoError(t, derived.Unmarshal(map[string]any{}, &got))
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, preparedLevel(2), got.Level)
+	})
+}
+
+func TestUnmarshaler_PrepareDefaults(t *testing.T) {
+	type Base struct {
+		Zone string `schema:"zone" default:"eu"`
+	}
+	type Server struct {
+		Base
+		Port int `schema:"port" default:"8080"`
+	}
+	type Broken struct {
+		Port int `schema:"port" default:"http"`
+	}
+
+	u := NewDefaultUnmarshaler(WithPreparedDefaults())
+
+	t.Run("valid defaults", func(t *testing.T) {
+		require.NoError(t, u.PrepareDefaults(reflect.TypeFor[Server](), reflect.TypeFor[*Base]()))
+
+		var got Server
+		require.NoError(t, u.Unmarshal(map[string]any{}, &got))
+		assert.Equal(t, Server{Base: Base{Zone: "eu"}, Port: 8080}, got)
+	})
+
+	t.Run("invalid defaults", func(t *testing.T) {
+		err := u.PrepareDefaults(reflect.TypeFor[Broken]())
+		require.ErrorAs(t, err, new(*ConversionError))
+		assert.ErrorContains(t, err, "Broken: port: ")
+
+		var got Broken
+		assert.ErrorContains(t, u.Unmarshal(map[string]any{}, &got), "port")
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		require.ErrorAs(t, u.PrepareDefaults(reflect.TypeFor[int]()), new(*ValidationError))
+		require.ErrorAs(t, u.PrepareDefaults(nil), new(*ValidationError))
+	})
+
+	t.Run("without the option", func(t *testing.T) {
+		assert.NoError(t, NewDefaultUnmarshaler().PrepareDefaults(reflect.TypeFor[Broken]()))
+	})
+}
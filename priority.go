@@ -0,0 +1,29 @@
+package mapstructure
+
+import "reflect"
+
+// WithConvertersFirst makes registered converters take precedence over direct assignment:
+// input that already has the target type (or is assignable to it) is passed to the
+// converter registered for the type, if any, instead of being stored as is. Use it when
+// converters normalize values, e.g. a time.Time converter forcing UTC. Converters that
+// return ErrSkipConverter fall back to assignment. To give precedence to the converters
+// of some types only, mark them with ConverterRegistry.WithAuthoritative.
+func WithConvertersFirst() Option {
+	return func(u *Unmarshaler) {
+		u.convertersFirst = true
+	}
+}
+
+// applyConverter decodes data into rv with the converter registered for the type of rv.
+// It reports false when no converter is registered or the converter declined data.
+func (u *Unmarshaler) applyConverter(data any, rv reflect.Value, fieldPath string, field *FieldMetadata) (bool, error) {
+	converted, ok, err := u.convert(data, rv.Type(), fieldPath, field)
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+
+	return true, setConverted(rv, converted, fieldPath)
+}
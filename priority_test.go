@@ -0,0 +1,141 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_WithConvertersFirst(t *testing.T) {
+	type Event struct {
+		At   time.Time `schema:"at"`
+		Code string    `schema:"code"`
+	}
+
+	utc := func(value any) (reflect.Value, error) {
+		at, ok := value.(time.Time)
+		if !ok {
+			return reflect.Value{}, ErrSkipConverter
+		}
+
+		return reflect.ValueOf(at.UTC()), nil
+	}
+	upper := func(value any) (reflect.Value, error) {
+		s, ok := value.(string)
+		if !ok {
+			return reflect.Value{}, ErrSkipConverter
+		}
+
+		return reflect.ValueOf(strings.ToUpper(s)), nil
+	}
+	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		timeType:                  utc,
+		reflect.TypeFor[string](): upper,
+	})
+	local := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantAt   time.Time
+		wantCode string
+	}{
+		{
+			name:     "assignment first by default",
+			wantAt:   local,
+			wantCode: "abc",
+		},
+		{
+			name:     "converters first",
+			opts:     []Option{WithConvertersFirst()},
+			wantAt:   local.UTC(),
+			wantCode: "ABC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters, tt.opts...)
+
+			var result Event
+			require.NoError(t, u.Unmarshal(map[string]any{"at": local, "code": "abc"}, &result))
+			assert.Equal(t, tt.wantAt.Location(), result.At.Location())
+			assert.True(t, tt.wantAt.Equal(result.At))
+			assert.Equal(t, tt.wantCode, result.Code)
+		})
+	}
+
+	t.Run("declined values are assigned", func(t *testing.T) {
+		decline := func(value any) (reflect.Value, error) {
+			return reflect.Value{}, ErrSkipConverter
+		}
+		declining := NewDefaultConverterRegistry(map[reflect.Type]Converter{timeType: decline})
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), declining, WithConvertersFirst())
+
+		var result Event
+		require.NoError(t, u.Unmarshal(map[string]any{"at": local}, &result))
+		assert.Equal(t, local, result.At)
+	})
+
+	t.Run("string maps", func(t *testing.T) {
+		type Env struct {
+			Level string `schema:"LEVEL"`
+			Port  int    `schema:"PORT"`
+		}
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters, WithConvertersFirst())
+
+		var result Env
+		require.NoError(t, u.UnmarshalStrings(map[string]string{"LEVEL": "debug", "PORT": "80"}, &result))
+		assert.Equal(t, Env{Level: "DEBUG", Port: 80}, result)
+	})
+}
+
+func TestUnmarshaler_AuthoritativeConverters(t *testing.T) {
+	type Level string
+	type Job struct {
+		Level    Level `schema:"level"`
+		Priority int   `schema:"priority"`
+		Retries  int   `schema:"retries"`
+	}
+
+	levelType := reflect.TypeFor[Level]()
+	lower := func(value any) (reflect.Value, error) {
+		switch v := value.(type) {
+		case Level:
+			return reflect.ValueOf(Level(strings.ToLower(string(v)))), nil
+		case string:
+			return reflect.ValueOf(Level(strings.ToLower(v))), nil
+		default:
+			return reflect.Value{}, ErrSkipConverter
+		}
+	}
+	clamp := func(value any) (reflect.Value, error) {
+		n, err := convertInt(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(min(max(int(n.Int()), 1), 10)), nil
+	}
+	converters := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		levelType:              lower,
+		reflect.TypeFor[int](): clamp,
+	}).WithAuthoritative(levelType)
+	u := NewUnmarshaler(NewDefaultStructMetadataCache(), converters)
+
+	var result Job
+	require.NoError(t, u.Unmarshal(map[string]any{"level": Level("WARN"), "priority": 42, "retries": "42"}, &result))
+	assert.Equal(t, Level("warn"), result.Level, "authoritative converter normalizes assignable input")
+	assert.Equal(t, 42, result.Priority, "other types are still assigned directly")
+	assert.Equal(t, 10, result.Retries)
+
+	var env struct {
+		Level Level `schema:"LEVEL"`
+	}
+	require.NoError(t, u.UnmarshalStrings(map[string]string{"LEVEL": "INFO"}, &env))
+	assert.Equal(t, Level("info"), env.Level)
+}
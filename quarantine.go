@@ -0,0 +1,48 @@
+package mapstructure
+
+import "reflect"
+
+// WithQuarantine makes the call skip struct fields whose input fails to decode instead of
+// failing, storing the raw input in quarantine under the field path, e.g. "address.zip",
+// so records can be processed partially and the rejected parts inspected later. Skipped
+// fields are left at their zero value and validator errors raised while decoding them are
+// discarded. Fields with a fallback use it first, and defaults that fail to decode still
+// fail the call. quarantine must not be nil; it is written by the call and must not be
+// shared with concurrent calls.
+func WithQuarantine(quarantine map[string]any) CallOption {
+	return func(o *callOptions) {
+		o.quarantine = quarantine
+	}
+}
+
+// recoverField replaces the value of field after decoding input failed with err, from
+// its fallback or by quarantining input, and reports whether it did.
+func (u *Unmarshaler) recoverField(input any, fieldValue reflect.Value, fieldPath string, field *FieldMetadata,
+	err error, validationErrs, pendingRefs int,
+) bool {
+	if !u.unmarshalFallback(input, fieldValue, fieldPath, field, err, validationErrs) &&
+		!u.quarantineField(input, fieldValue, fieldPath, validationErrs) {
+		return false
+	}
+	u.dropPendingRefs(pendingRefs) // The field was reset
+
+	return true
+}
+
+// quarantineField stores input in the quarantine of the call under fieldPath and clears
+// fieldValue, discarding the validator errors reported after the first validationErrs.
+// It reports whether the call has a quarantine.
+func (u *Unmarshaler) quarantineField(input any, fieldValue reflect.Value, fieldPath string, validationErrs int) bool {
+	if u.call.quarantine == nil {
+		return false
+	}
+
+	if u.state != nil {
+		u.state.validationErrors = u.state.validationErrors[:validationErrs]
+	}
+
+	fieldValue.SetZero()
+	u.call.quarantine[fieldPath] = input
+
+	return true
+}
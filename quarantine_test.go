@@ -0,0 +1,100 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_Quarantine(t *testing.T) {
+	type Address struct {
+		City string `schema:"city"`
+		Zip  int    `schema:"zip"`
+	}
+	type Record struct {
+		ID      string    `schema:"id"`
+		Count   int       `schema:"count"`
+		Retries int       `schema:"retries,fallback=1"`
+		Ports   []int     `schema:"ports"`
+		Address Address   `schema:"address"`
+		Lines   []Address `schema:"lines"`
+	}
+
+	tests := []struct {
+		name           string
+		data           map[string]any
+		want           Record
+		wantQuarantine map[string]any
+	}{
+		{
+			name:           "valid input",
+			data:           map[string]any{"id": "a", "count": 1},
+			want:           Record{ID: "a", Count: 1},
+			wantQuarantine: map[string]any{},
+		},
+		{
+			name:           "failing fields skipped",
+			data:           map[string]any{"id": "a", "count": "many", "ports": []any{80, "x"}},
+			want:           Record{ID: "a"},
+			wantQuarantine: map[string]any{"count": "many", "ports": []any{80, "x"}},
+		},
+		{
+			name: "nested fields keyed by path",
+			data: map[string]any{
+				"address": map[string]any{"city": "x", "zip": "none"},
+				"lines":   []any{map[string]any{"zip": 1}, map[string]any{"city": "y", "zip": "abc"}},
+			},
+			want:           Record{Address: Address{City: "x"}, Lines: []Address{{Zip: 1}, {City: "y"}}},
+			wantQuarantine: map[string]any{"address.zip": "none", "lines[1].zip": "abc"},
+		},
+		{
+			name:           "fallbacks take precedence",
+			data:           map[string]any{"retries": "x", "address": "x"},
+			want:           Record{Retries: 1},
+			wantQuarantine: map[string]any{"address": "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Record
+			quarantine := map[string]any{}
+			require.NoError(t, Unmarshal(tt.data, &got, WithQuarantine(quarantine)))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantQuarantine, quarantine)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_QuarantineDetails(t *testing.T) {
+	type Config struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port"`
+	}
+
+	t.Run("skipped fields cleared", func(t *testing.T) {
+		got := Config{Port: 80}
+		quarantine := map[string]any{}
+		require.NoError(t, Unmarshal(map[string]any{"port": "http"}, &got, WithQuarantine(quarantine)))
+		assert.Equal(t, Config{}, got)
+	})
+
+	t.Run("invalid defaults fail", func(t *testing.T) {
+		type Defaults struct {
+			Port int `schema:"port" default:"http"`
+		}
+
+		var got Defaults
+		err := Unmarshal(map[string]any{}, &got, WithQuarantine(map[string]any{}))
+		assert.ErrorContains(t, err, "port")
+	})
+
+	t.Run("string maps", func(t *testing.T) {
+		var got Config
+		quarantine := map[string]any{}
+		require.NoError(t, UnmarshalStrings(map[string]string{"host": "a", "port": "http"}, &got, WithQuarantine(quarantine)))
+		assert.Equal(t, Config{Host: "a"}, got)
+		assert.Equal(t, map[string]any{"port": "http"}, quarantine)
+	})
+}
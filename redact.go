@@ -0,0 +1,70 @@
+package mapstructure
+
+import "reflect"
+
+// OptionSecret is the tag option marking a field whose value must not appear in logs,
+// e.g. `schema:"password,secret"`. Redacted marshalers replace its value with RedactedValue;
+// decoding and other marshalers ignore the option.
+const OptionSecret = "secret"
+
+// RedactedValue is the value of secret fields in maps produced by a redacted marshaler.
+const RedactedValue = "[REDACTED]"
+
+var redactedMarshaler = defaultMarshaler.Redacted()
+
+// MarshalRedacted transforms the struct v, or a pointer to it, into a map[string]any safe
+// to log, with the values of secret fields replaced by RedactedValue.
+// This is a convenience function that uses a shared default marshaler.
+func MarshalRedacted(v any) (map[string]any, error) {
+	return redactedMarshaler.Marshal(v)
+}
+
+// Redacted returns a new marshaler like m that replaces the value of every field tagged
+// with the "secret" option by RedactedValue, including fields of nested structs, slice
+// elements and map values. Secret fields are masked whatever their value, even when empty,
+// unless omitempty leaves them out. Structs holding secret fields are marshaled by their
+// fields even when they implement encoding.TextMarshaler. The result still has the shape of the struct but no
+// longer decodes back into an equal struct. m is left unchanged.
+func (m *Marshaler) Redacted() *Marshaler {
+	configured := *m
+	configured.redact = true
+
+	return &configured
+}
+
+// redacted reports whether the value of field is masked by m.
+func (m *Marshaler) redacted(field *FieldMetadata) bool {
+	if !m.redact {
+		return false
+	}
+
+	_, ok := field.Option(OptionSecret)
+
+	return ok
+}
+
+// holdsSecrets reports whether typ is a struct with a secret field, directly or in the
+// structs, pointers, slices, arrays and maps of its fields. seen holds the struct types
+// being inspected, to stop on recursive types.
+func (m *Marshaler) holdsSecrets(typ reflect.Type, seen map[reflect.Type]struct{}) bool {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	if _, ok := seen[typ]; ok {
+		return false
+	}
+	seen[typ] = struct{}{}
+
+	metadata := m.fieldCache.GetMetadata(typ)
+	for i := range metadata.flat {
+		field := &metadata.flat[i]
+		if m.redacted(field) || m.holdsSecrets(field.Type, seen) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package mapstructure
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type redactDB struct {
+	Host     string `schema:"host"`
+	Password string `schema:"password,secret"`
+}
+
+type redactConfig struct {
+	Name     string              `schema:"name"`
+	APIKey   *string             `schema:"api_key,secret"`
+	Token    string              `schema:"token,secret,omitempty"`
+	DB       redactDB            `schema:"db"`
+	Replicas []redactDB          `schema:"replicas"`
+	Vaults   map[string]redactDB `schema:"vaults"`
+	Keys     []string            `schema:"keys,secret"`
+	Env      map[string]any      `schema:",remain,secret"`
+}
+
+func TestMarshalRedacted(t *testing.T) {
+	key := "k-123"
+	cfg := redactConfig{
+		Name:     "api",
+		APIKey:   &key,
+		DB:       redactDB{Host: "db", Password: "hunter2"},
+		Replicas: []redactDB{{Host: "r1", Password: "p1"}},
+		Vaults:   map[string]redactDB{"main": {Host: "v", Password: "p2"}},
+		Keys:     []string{"a"},
+		Env:      map[string]any{"AWS_SECRET": "s"},
+	}
+
+	got, err := MarshalRedacted(&cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":       "api",
+		"api_key":    RedactedValue,
+		"db":         map[string]any{"host": "db", "password": RedactedValue},
+		"replicas":   []any{map[string]any{"host": "r1", "password": RedactedValue}},
+		"vaults":     map[string]any{"main": map[string]any{"host": "v", "password": RedactedValue}},
+		"keys":       RedactedValue,
+		"AWS_SECRET": RedactedValue,
+	}, got)
+	assert.Equal(t, "hunter2", cfg.DB.Password, "struct unchanged")
+
+	// Empty secrets are masked too, unless omitempty leaves them out
+	got, err = MarshalRedacted(redactConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, RedactedValue, got["api_key"])
+	assert.NotContains(t, got, "token")
+
+	// Plain marshaling ignores the option
+	got, err = Marshal(&cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", got["db"].(map[string]any)["password"])
+}
+
+func TestMarshaler_Redacted(t *testing.T) {
+	m := NewMarshaler(NewStructMetadataCache("cfg", "default"))
+	redacted := m.Redacted()
+
+	type Config struct {
+		Password string `cfg:"password,secret"`
+	}
+
+	got, err := redacted.Marshal(Config{Password: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"password": RedactedValue}, got)
+
+	got, err = m.Marshal(Config{Password: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"password": "x"}, got, "m unchanged")
+}
+
+// redactDSN is a struct whose String and MarshalText methods reveal its secret field.
+type redactDSN struct {
+	User string `schema:"user"`
+	Pass string `schema:"pass,secret"`
+	Host string `schema:"host"`
+}
+
+func (d redactDSN) String() string { return "postgres://" + d.User + ":" + d.Pass + "@" + d.Host }
+
+func (d redactDSN) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+func TestMarshalRedacted_TextMethods(t *testing.T) {
+	type Nested struct {
+		DSN redactDSN `schema:"dsn"`
+	}
+	type Config struct {
+		DB     redactDSN   `schema:"db"`
+		Nested *Nested     `schema:"nested"`
+		Day    Date        `schema:"day"`
+		Seen   time.Time   `schema:"seen"`
+		All    []redactDSN `schema:"all"`
+	}
+
+	dsn := redactDSN{User: "u", Pass: "hunter2", Host: "h"}
+	cfg := Config{DB: dsn, Nested: &Nested{DSN: dsn}, Day: Date{Year: 2024, Month: time.March, Day: 5}, All: []redactDSN{dsn}}
+	masked := map[string]any{"user": "u", "pass": RedactedValue, "host": "h"}
+
+	got, err := MarshalRedacted(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"db":     masked,
+		"nested": map[string]any{"dsn": masked},
+		"day":    "2024-03-05",
+		"seen":   "0001-01-01T00:00:00Z",
+		"all":    []any{masked},
+	}, got)
+	assert.NotContains(t, fmt.Sprint(got), "hunter2")
+
+	// Plain marshaling keeps using MarshalText
+	got, err = Marshal(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://u:hunter2@h", got["db"])
+}
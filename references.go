@@ -0,0 +1,171 @@
+package mapstructure
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// Keys of the input maps used by WithReferences.
+const (
+	// RefKey marks a reference: {"$ref": "node-17"} stands for the object with that id.
+	RefKey = "$ref"
+
+	// RefIDKey gives the object decoded from a map an id other objects can refer to.
+	RefIDKey = "$id"
+)
+
+// RefResolver returns the value a reference id stands for when decoding into the type
+// typ, and whether there is one. It resolves ids not declared in the input itself,
+// e.g. objects decoded by earlier calls.
+type RefResolver func(id string, typ reflect.Type) (any, bool)
+
+// WithReferences enables decoding object graphs with shared and cyclic references from
+// flat exports. A map decoded into a pointer may declare an id under RefIDKey, and
+// any pointer may instead be given as {"$ref": id} to share the object with that id.
+// References resolve to the objects declared in the input first, whether before or
+// after the reference, and then through resolver, which may be nil. Unresolved
+// references fail the call with a *ConversionError. References to objects declared
+// later are set once the rest of the input is decoded, so struct validators may see
+// them nil; they are dropped with fields and elements discarded after failing to decode,
+// and fail the call within values that are copied after decoding: set elements, slices
+// with the "unique" tag option and struct values held by interfaces.
+func WithReferences(resolver RefResolver) Option {
+	return func(u *Unmarshaler) {
+		u.references = true
+		u.resolver = resolver
+	}
+}
+
+// pendingRef is a reference to an object not decoded yet when it was met.
+type pendingRef struct {
+	rv        reflect.Value
+	id        string
+	fieldPath string
+}
+
+// refID returns the id of data when it is a reference: a map holding only RefKey.
+func refID(data any) (string, bool) {
+	m, ok := data.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	id, ok := m[RefKey].(string)
+
+	return id, ok
+}
+
+// setRef sets the pointer rv to the object with the given id declared in the input,
+// or defers that until the end of the call when it has not been decoded yet.
+func (u *Unmarshaler) setRef(id string, rv reflect.Value, fieldPath string) error {
+	target, ok := u.state.refs[id]
+	if !ok {
+		u.state.pendingRefs = append(u.state.pendingRefs, pendingRef{rv: rv, id: id, fieldPath: fieldPath})
+
+		return nil
+	}
+
+	return assignRef(id, target, rv, fieldPath)
+}
+
+// declareRef records the pointer rv as the object with the id declared in data, if any,
+// and returns data without the id.
+func (u *Unmarshaler) declareRef(data any, rv reflect.Value, fieldPath string) (any, error) {
+	m, ok := data.(map[string]any)
+	if !ok {
+		return data, nil
+	}
+	value, ok := m[RefIDKey]
+	if !ok {
+		return data, nil
+	}
+
+	id, ok := value.(string)
+	if !ok {
+		return nil, NewConversionError(buildFieldPath(fieldPath, RefIDKey), value, reflect.TypeFor[string](), nil)
+	}
+	if _, ok := u.state.refs[id]; ok {
+		return nil, NewConversionError(fieldPath, id, rv.Type(), fmt.Errorf("duplicate id %q", id))
+	}
+
+	if u.state.refs == nil {
+		u.state.refs = make(map[string]reflect.Value)
+	}
+	u.state.refs[id] = reflect.ValueOf(rv.Interface()) // The allocated pointer, independent of where it is stored
+
+	stripped := maps.Clone(m)
+	delete(stripped, RefIDKey)
+
+	return stripped, nil
+}
+
+// pendingRefCount returns the number of references waiting for their object, to drop
+// with dropPendingRefs those met while decoding a value that is then discarded.
+func (u *Unmarshaler) pendingRefCount() int {
+	if u.state == nil {
+		return 0
+	}
+
+	return len(u.state.pendingRefs)
+}
+
+// dropPendingRefs discards the references met after the first count, which would
+// otherwise be set in a value no longer part of the result.
+func (u *Unmarshaler) dropPendingRefs(count int) {
+	if u.state != nil {
+		u.state.pendingRefs = u.state.pendingRefs[:count]
+	}
+}
+
+// checkCopiedRefs fails when references were met after the first count while decoding
+// a value that is then copied rather than kept in place, such as set elements, slices
+// deduplicated by the "unique" tag option and struct values held by interfaces: they
+// would be set in the discarded original once their object is decoded.
+func (u *Unmarshaler) checkCopiedRefs(count int) error {
+	if u.pendingRefCount() == count {
+		return nil
+	}
+
+	ref := u.state.pendingRefs[count]
+
+	return NewConversionError(ref.fieldPath, ref.id, ref.rv.Type(),
+		fmt.Errorf("reference %q to an object declared later cannot be set in a copied value", ref.id))
+}
+
+// resolvePendingRefs sets the references met before their objects were decoded,
+// falling back to the resolver for ids not declared in the input.
+func (u *Unmarshaler) resolvePendingRefs() error {
+	for _, ref := range u.state.pendingRefs {
+		if target, ok := u.state.refs[ref.id]; ok {
+			if err := assignRef(ref.id, target, ref.rv, ref.fieldPath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var resolved any
+		var ok bool
+		if u.resolver != nil {
+			resolved, ok = u.resolver(ref.id, ref.rv.Type())
+		}
+		if !ok {
+			return NewConversionError(ref.fieldPath, ref.id, ref.rv.Type(), fmt.Errorf("unresolved reference %q", ref.id))
+		}
+		if err := assignRef(ref.id, reflect.ValueOf(resolved), ref.rv, ref.fieldPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assignRef sets rv to the referenced value target.
+func assignRef(id string, target, rv reflect.Value, fieldPath string) error {
+	if !target.IsValid() || !target.Type().AssignableTo(rv.Type()) {
+		return NewConversionError(fieldPath, id, rv.Type(), fmt.Errorf("reference %q is not a %v", id, rv.Type()))
+	}
+	rv.Set(target)
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_WithReferences(t *testing.T) {
+	type Person struct {
+		Name    string    `schema:"name"`
+		Manager *Person   `schema:"manager"`
+		Reports []*Person `schema:"reports"`
+	}
+	type Org struct {
+		People []*Person `schema:"people"`
+		CEO    *Person   `schema:"ceo"`
+	}
+
+	data := map[string]any{
+		"people": []any{
+			map[string]any{"$id": "ann", "name": "Ann", "reports": []any{
+				map[string]any{"$ref": "bob"},
+				map[string]any{"$ref": "cid"},
+			}},
+			map[string]any{"$id": "bob", "name": "Bob", "manager": map[string]any{"$ref": "ann"}},
+			map[string]any{"$id": "cid", "name": "Cid", "manager": map[string]any{"$ref": "ann"}},
+		},
+		"ceo": map[string]any{"$ref": "ann"},
+	}
+
+	u := NewDefaultUnmarshaler(WithReferences(nil))
+
+	var org Org
+	require.NoError(t, u.Unmarshal(data, &org))
+
+	ann, bob, cid := org.People[0], org.People[1], org.People[2]
+	assert.Same(t, ann, org.CEO)
+	assert.Same(t, ann, bob.Manager, "backward reference")
+	assert.Same(t, ann, cid.Manager)
+	require.Len(t, ann.Reports, 2)
+	assert.Same(t, bob, ann.Reports[0], "forward reference")
+	assert.Same(t, cid, ann.Reports[1])
+
+	t.Run("cycle to itself", func(t *testing.T) {
+		var p Person
+		require.NoError(t, u.Unmarshal(map[string]any{
+			"manager": map[string]any{"$id": "self", "name": "Self", "manager": map[string]any{"$ref": "self"}},
+		}, &p))
+		assert.Same(t, p.Manager, p.Manager.Manager)
+	})
+
+	t.Run("resolver", func(t *testing.T) {
+		known := &Person{Name: "Known"}
+		var asked []reflect.Type
+		u := NewDefaultUnmarshaler(WithReferences(func(id string, typ reflect.Type) (any, bool) {
+			asked = append(asked, typ)
+
+			return known, id == "known"
+		}))
+
+		var p Person
+		require.NoError(t, u.Unmarshal(map[string]any{"manager": map[string]any{"$ref": "known"}}, &p))
+		assert.Same(t, known, p.Manager)
+		assert.Equal(t, []reflect.Type{reflect.TypeFor[*Person]()}, asked)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			data     map[string]any
+			wantPath string
+			wantErr  string
+		}{
+			{name: "unresolved", data: map[string]any{"ceo": map[string]any{"$ref": "nobody"}}, wantPath: "ceo", wantErr: `unresolved reference "nobody"`},
+			{name: "duplicate id", data: map[string]any{"people": []any{
+				map[string]any{"$id": "a"}, map[string]any{"$id": "a"},
+			}}, wantPath: "people[1]", wantErr: `duplicate id "a"`},
+			{name: "id not a string", data: map[string]any{"ceo": map[string]any{"$id": 1}}, wantPath: "ceo.$id"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var org Org
+				err := u.Unmarshal(tt.data, &org)
+
+				var convErr *ConversionError
+				require.ErrorAs(t, err, &convErr)
+				assert.Equal(t, tt.wantPath, convErr.FieldPath)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+
+		t.Run("wrong type", func(t *testing.T) {
+			type Team struct {
+				Lead *Person `schema:"lead"`
+				Org  *Org    `schema:"org"`
+			}
+
+			var team Team
+			err := u.Unmarshal(map[string]any{
+				"lead": map[string]any{"$id": "p", "name": "P"},
+				"org":  map[string]any{"$ref": "p"},
+			}, &team)
+
+			var convErr *ConversionError
+			require.ErrorAs(t, err, &convErr)
+			assert.Equal(t, "org", convErr.FieldPath)
+		})
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var p Person
+		require.NoError(t, Unmarshal(map[string]any{"manager": map[string]any{"$ref": "x"}}, &p))
+		assert.Equal(t, &Person{}, p.Manager)
+	})
+}
+
+func TestUnmarshaler_WithReferences_DiscardedValues(t *testing.T) {
+	type Node struct {
+		Name string `schema:"name"`
+		Next *Node  `schema:"next"`
+	}
+	type Link struct {
+		Next *Node `schema:"next"`
+		N    int   `schema:"n"`
+	}
+
+	u := NewDefaultUnmarshaler(WithReferences(nil))
+	tail := map[string]any{"$id": "tail", "name": "tail"}
+
+	t.Run("quarantined field", func(t *testing.T) {
+		type Graph struct {
+			Links []Link `schema:"links"`
+			Tail  *Node  `schema:"tail"`
+		}
+
+		quarantine := map[string]any{}
+		var got Graph
+		require.NoError(t, u.Unmarshal(map[string]any{
+			"links": []any{map[string]any{"next": map[string]any{"$ref": "tail"}}, "bad"},
+			"tail":  tail,
+		}, &got, WithQuarantine(quarantine)))
+		assert.Nil(t, got.Links, "reset without the reference")
+		assert.Contains(t, quarantine, "links")
+		assert.Equal(t, "tail", got.Tail.Name)
+	})
+
+	t.Run("set elements", func(t *testing.T) {
+		type Graph struct {
+			Links map[Link]struct{} `schema:"links"`
+			Tail  *Node             `schema:"tail"`
+		}
+
+		var got Graph
+		err := u.Unmarshal(map[string]any{
+			"links": []any{map[string]any{"next": map[string]any{"$ref": "tail"}}},
+			"tail":  tail,
+		}, &got)
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "links[0].next", convErr.FieldPath)
+		assert.ErrorContains(t, err, `reference "tail" to an object declared later`)
+
+		// Objects declared earlier are set before the elements are copied
+		var earlier struct {
+			Tail  *Node             `schema:"tail"`
+			Links map[Link]struct{} `schema:"links"`
+		}
+		require.NoError(t, u.Unmarshal(map[string]any{
+			"tail":  tail,
+			"links": []any{map[string]any{"next": map[string]any{"$ref": "tail"}}},
+		}, &earlier))
+		require.Len(t, earlier.Links, 1)
+		for link := range earlier.Links {
+			assert.Same(t, earlier.Tail, link.Next)
+		}
+	})
+
+	t.Run("unique slices", func(t *testing.T) {
+		type Graph struct {
+			Nodes []*Node `schema:"nodes,unique"`
+			Tail  *Node   `schema:"tail"`
+		}
+
+		var got Graph
+		err := u.Unmarshal(map[string]any{
+			"nodes": []any{map[string]any{"$ref": "tail"}, map[string]any{"name": "a"}},
+			"tail":  tail,
+		}, &got)
+		assert.ErrorContains(t, err, `reference "tail" to an object declared later`)
+	})
+
+	t.Run("struct values held by interfaces", func(t *testing.T) {
+		type Graph struct {
+			Link any   `schema:"link"`
+			Tail *Node `schema:"tail"`
+		}
+
+		got := Graph{Link: Link{}}
+		err := u.Unmarshal(map[string]any{
+			"link": map[string]any{"next": map[string]any{"$ref": "tail"}},
+			"tail": tail,
+		}, &got)
+		assert.ErrorContains(t, err, `reference "tail" to an object declared later`)
+	})
+}
@@ -0,0 +1,128 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+)
+
+// OptionSquash is the tag option promoting the fields of a struct or pointer to struct
+// field as if it were embedded, e.g. `schema:",squash"` on a Core field decodes the keys
+// of Core from the input map of the outer struct. Keys of the outer struct's own fields
+// win over squashed fields with the same key, see StructMetadataCache.
+const OptionSquash = "squash"
+
+// OptionRemain is the tag option on a map[string]any field collecting the input keys
+// that no field of the struct accepts, including promoted and squashed fields, e.g.
+// the arbitrary extensions of a webhook payload next to its known core. The field's own
+// key is not read from the input, and strict structs with a remainder accept unknown keys.
+// When embedded structs declare remainders too, the shallowest one is used; remainders
+// tied at the shallowest depth are all ignored.
+const OptionRemain = "remain"
+
+var remainType = reflect.TypeFor[map[string]any]()
+
+var (
+	errSquashType = errors.New(`"squash" requires a struct or pointer to struct field`)
+	errRemainType = errors.New(`"remain" requires a map[string]any field`)
+)
+
+// isRemain reports whether field collects the unknown keys of its struct, see OptionRemain.
+func isRemain(field *FieldMetadata) bool {
+	_, ok := field.Option(OptionRemain)
+
+	return ok
+}
+
+// remainField returns one plus the position in flat of the field receiving unknown keys,
+// 0 if none: the shallowest field with the "remain" option, unless several are tied.
+func remainField(flat []FieldMetadata) int {
+	remain, depth, ties := 0, 0, 0
+
+	for i := range flat {
+		if !isRemain(&flat[i]) {
+			continue
+		}
+
+		switch d := len(flat[i].Index); {
+		case remain == 0 || d < depth:
+			remain, depth, ties = i+1, d, 1
+		case d == depth:
+			ties++
+		}
+	}
+
+	if ties > 1 {
+		return 0
+	}
+
+	return remain
+}
+
+// unmarshalRemain stores the keys of dataMap unknown to metadata in its remainder field,
+// when the struct has one that is decoded for the call and there are such keys.
+func (u *Unmarshaler) unmarshalRemain(dataMap map[string]any, rv reflect.Value, metadata *StructMetadata, closed []bool) {
+	if metadata.remain == 0 {
+		return
+	}
+
+	field := &metadata.flat[metadata.remain-1]
+	if !isOpen(closed, field) || !u.fieldSelected(field) {
+		return
+	}
+
+	var leftovers map[string]any
+	for key, value := range dataMap {
+		if metadata.HasKey(key) {
+			continue
+		}
+		if leftovers == nil {
+			leftovers = make(map[string]any)
+		}
+		leftovers[key] = value
+	}
+
+	if leftovers == nil {
+		return
+	}
+
+	if fieldValue := fieldByIndex(rv, field.Index); fieldValue.IsValid() {
+		fieldValue.Set(reflect.ValueOf(leftovers))
+	}
+}
+
+// marshalRemain adds the entries of the remainder field of rv, described by metadata, to
+// result, except for keys already set by fields. Entries of a secret remainder are masked.
+func (m *Marshaler) marshalRemain(rv reflect.Value, metadata *StructMetadata, result map[string]any,
+	fieldPath string, visiting map[copyKey]struct{},
+) error {
+	if metadata.remain == 0 {
+		return nil
+	}
+
+	field := &metadata.flat[metadata.remain-1]
+	fieldValue, ok := fieldByIndexNoAlloc(rv, field.Index)
+	if !ok {
+		return nil
+	}
+
+	iter := fieldValue.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if _, exists := result[key]; exists {
+			continue
+		}
+		if m.redacted(field) {
+			result[key] = RedactedValue
+
+			continue
+		}
+
+		value, err := m.marshalValue(iter.Value(), buildFieldPath(fieldPath, key), visiting)
+		if err != nil {
+			return err
+		}
+		result[key] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookCore struct {
+	ID    string `schema:"id"`
+	Event string `schema:"event"`
+}
+
+type webhookMeta struct {
+	Source string         `schema:"source"`
+	Extra  map[string]any `schema:",remain"`
+}
+
+type webhook struct {
+	Core       webhookCore    `schema:",squash"`
+	Meta       *webhookMeta   `schema:"meta,squash"`
+	Event      string         `schema:"event"` // Wins over Core.Event
+	Extensions map[string]any `schema:",remain"`
+}
+
+func TestStructMetadataCache_Squash(t *testing.T) {
+	cache := NewDefaultStructMetadataCache()
+	metadata := cache.GetMetadata(reflect.TypeFor[webhook]())
+	require.NoError(t, metadata.Err())
+
+	id, ok := metadata.FieldByKey("id")
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 0}, id.Index)
+
+	source, ok := metadata.FieldByKey("source")
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 0}, source.Index)
+
+	event, ok := metadata.FieldByKey("event")
+	require.True(t, ok)
+	assert.Equal(t, []int{2}, event.Index, "own field wins over squashed one")
+
+	for _, key := range []string{"Extensions", "Extra"} {
+		_, ok = metadata.FieldByKey(key)
+		assert.False(t, ok, "remainder %s is not decoded by key", key)
+		assert.False(t, metadata.HasKey(key))
+	}
+}
+
+func TestStructMetadataCache_SquashRemainErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     reflect.Type
+		wantErr error
+	}{
+		{
+			name: "squash non-struct",
+			typ: reflect.TypeFor[struct {
+				Name string `schema:",squash"`
+			}](),
+			wantErr: errSquashType,
+		},
+		{
+			name: "remain wrong map type",
+			typ: reflect.TypeFor[struct {
+				Rest map[string]string `schema:",remain"`
+			}](),
+			wantErr: errRemainType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewDefaultStructMetadataCache().GetMetadata(tt.typ).Err()
+			var tagErr *TagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.True(t, errors.Is(err, tt.wantErr))
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_SquashRemain(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]any
+		want webhook
+	}{
+		{
+			name: "known core and extensions",
+			data: map[string]any{"id": "evt_1", "event": "push", "x-repo": "talav", "x-sha": "abc"},
+			want: webhook{
+				Core:       webhookCore{ID: "evt_1"},
+				Event:      "push",
+				Extensions: map[string]any{"x-repo": "talav", "x-sha": "abc"},
+			},
+		},
+		{
+			name: "squashed pointer allocated for its keys",
+			data: map[string]any{"source": "github"},
+			want: webhook{Meta: &webhookMeta{Source: "github"}},
+		},
+		{
+			name: "remainder key itself is a leftover",
+			data: map[string]any{"id": "evt_1", "Extensions": "x"},
+			want: webhook{Core: webhookCore{ID: "evt_1"}, Extensions: map[string]any{"Extensions": "x"}},
+		},
+		{
+			name: "shallowest remainder wins",
+			data: map[string]any{"source": "github", "x-extra": 1},
+			want: webhook{Meta: &webhookMeta{Source: "github"}, Extensions: map[string]any{"x-extra": 1}},
+		},
+		{
+			name: "no leftovers",
+			data: map[string]any{"id": "evt_1"},
+			want: webhook{Core: webhookCore{ID: "evt_1"}},
+		},
+		{
+			name: "nested map under the Go field name",
+			data: map[string]any{"Core": map[string]any{"id": "evt_1"}},
+			want: webhook{Core: webhookCore{ID: "evt_1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got webhook
+			require.NoError(t, Unmarshal(tt.data, &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_RemainPrecedence(t *testing.T) {
+	t.Run("strict struct accepts unknown keys", func(t *testing.T) {
+		type Strict struct {
+			_    struct{}       `schema:",strict"`
+			Name string         `schema:"name"`
+			Rest map[string]any `schema:",remain"`
+		}
+
+		var got Strict
+		require.NoError(t, Unmarshal(map[string]any{"name": "a", "other": 1}, &got))
+		assert.Equal(t, map[string]any{"other": 1}, got.Rest)
+	})
+
+	t.Run("keys of fields outside the groups are not leftovers", func(t *testing.T) {
+		type Grouped struct {
+			Name  string         `schema:"name"`
+			Admin string         `schema:"admin,groups=admin"`
+			Rest  map[string]any `schema:",remain"`
+		}
+
+		var got Grouped
+		require.NoError(t, Unmarshal(map[string]any{"name": "a", "admin": "x", "other": 1}, &got, WithGroups("public")))
+		assert.Equal(t, Grouped{Name: "a", Rest: map[string]any{"other": 1}}, got)
+	})
+
+	t.Run("remainders tied at the same depth are ignored", func(t *testing.T) {
+		type Left struct {
+			Rest map[string]any `schema:",remain"`
+		}
+		type Right struct {
+			Rest map[string]any `schema:",remain"`
+		}
+		type Tied struct {
+			Left  `schema:",squash"`
+			Right `schema:",squash"`
+			Name  string `schema:"name"`
+		}
+
+		var got Tied
+		require.NoError(t, Unmarshal(map[string]any{"name": "a", "other": 1}, &got))
+		assert.Equal(t, Tied{Name: "a"}, got)
+	})
+
+	t.Run("deep leftovers stay in nested structs", func(t *testing.T) {
+		type Inner struct {
+			Name string         `schema:"name"`
+			Rest map[string]any `schema:",remain"`
+		}
+		type Outer struct {
+			Inner Inner          `schema:"inner"`
+			Rest  map[string]any `schema:",remain"`
+		}
+
+		var got Outer
+		require.NoError(t, Unmarshal(map[string]any{
+			"inner": map[string]any{"name": "a", "deep": 1},
+			"top":   2,
+		}, &got))
+		assert.Equal(t, Outer{
+			Inner: Inner{Name: "a", Rest: map[string]any{"deep": 1}},
+			Rest:  map[string]any{"top": 2},
+		}, got)
+	})
+}
+
+func TestMarshaler_Marshal_SquashRemain(t *testing.T) {
+	hook := webhook{
+		Core:       webhookCore{ID: "evt_1", Event: "ignored"},
+		Event:      "push",
+		Extensions: map[string]any{"x-repo": "talav", "id": "shadowed"},
+	}
+
+	got, err := Marshal(hook)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "evt_1", "event": "push", "x-repo": "talav"}, got,
+		"fields win over remainder entries with the same key")
+
+	var back webhook
+	require.NoError(t, Unmarshal(got, &back))
+	assert.Equal(t, webhook{
+		Core:       webhookCore{ID: "evt_1"},
+		Event:      "push",
+		Extensions: map[string]any{"x-repo": "talav"},
+	}, back)
+}
@@ -0,0 +1,158 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// RoundTrip decodes data into a new T with u, marshals the result with m and decodes the
+// marshaled map again, returning the marshaled map. It is meant for property tests in
+// consuming projects and fails when the round trip is not faithful, that is when:
+//   - decoding the marshaled map yields a different T than decoding data, or
+//   - a key of data is marshaled to a different value. Values are compared canonically:
+//     numbers by value, slices and arrays element by element and maps key by key.
+//
+// Keys of data missing from the marshaled map (unknown keys, omitempty fields) and keys
+// only in the marshaled map (defaults) are not compared. nil u or m use the shared defaults.
+func RoundTrip[T any](u *Unmarshaler, m *Marshaler, data map[string]any) (map[string]any, error) {
+	if u == nil {
+		u = defaultUnmarshaler
+	}
+	if m == nil {
+		m = defaultMarshaler
+	}
+
+	var decoded T
+	if err := u.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("round trip: decode input: %w", err)
+	}
+
+	marshaled, err := m.Marshal(&decoded)
+	if err != nil {
+		return nil, fmt.Errorf("round trip: marshal: %w", err)
+	}
+
+	var redecoded T
+	if err := u.Unmarshal(marshaled, &redecoded); err != nil {
+		return marshaled, fmt.Errorf("round trip: decode marshaled map: %w", err)
+	}
+
+	if !reflect.DeepEqual(decoded, redecoded) {
+		return marshaled, fmt.Errorf("round trip: decoding the marshaled map yields %+v, want %+v", redecoded, decoded)
+	}
+
+	if err := diffCanonical("", data, marshaled); err != nil {
+		return marshaled, fmt.Errorf("round trip: %w", err)
+	}
+
+	return marshaled, nil
+}
+
+// diffCanonical returns an error describing the first difference between want and got
+// at fieldPath, comparing as documented by RoundTrip.
+func diffCanonical(fieldPath string, want, got any) error {
+	mismatch := func() error {
+		return fmt.Errorf("%s: marshaled as %#v, input was %#v", rootPath(fieldPath), got, want)
+	}
+
+	if want == nil || got == nil {
+		if want == nil && got == nil {
+			return nil
+		}
+
+		return mismatch()
+	}
+
+	if wn, ok := canonicalNumber(want); ok {
+		if gn, ok := canonicalNumber(got); ok && wn == gn {
+			return nil
+		}
+
+		return mismatch()
+	}
+
+	wv, gv := reflect.ValueOf(want), reflect.ValueOf(got)
+
+	//nolint:exhaustive // Other kinds are compared deeply
+	switch wv.Kind() {
+	case reflect.String:
+		if gv.Kind() == reflect.String && wv.String() == gv.String() {
+			return nil
+		}
+
+		return mismatch()
+	case reflect.Bool:
+		if gv.Kind() == reflect.Bool && wv.Bool() == gv.Bool() {
+			return nil
+		}
+
+		return mismatch()
+	case reflect.Slice, reflect.Array:
+		if (gv.Kind() != reflect.Slice && gv.Kind() != reflect.Array) || wv.Len() != gv.Len() {
+			return mismatch()
+		}
+		for i := range wv.Len() {
+			if err := diffCanonical(fmt.Sprintf("%s[%d]", fieldPath, i), wv.Index(i).Interface(), gv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Map:
+		if gv.Kind() != reflect.Map || wv.Type().Key().Kind() != reflect.String || gv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		iter := wv.MapRange()
+		for iter.Next() {
+			gotValue := gv.MapIndex(iter.Key())
+			if !gotValue.IsValid() {
+				continue
+			}
+			key := iter.Key().String()
+			if err := diffCanonical(buildFieldPath(fieldPath, key), iter.Value().Interface(), gotValue.Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		return mismatch()
+	}
+
+	return nil
+}
+
+// canonicalNumber returns v as a float64 when it is a number or a json.Number.
+func canonicalNumber(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	//nolint:exhaustive // Only numeric kinds are numbers
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// rootPath returns fieldPath, or "root" for the top level.
+func rootPath(fieldPath string) string {
+	if fieldPath == "" {
+		return "root"
+	}
+
+	return fieldPath
+}
@@ -0,0 +1,102 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	type Base struct {
+		ID int64 `schema:"id"`
+	}
+	type Item struct {
+		SKU string  `schema:"sku"`
+		Qty uint    `schema:"qty"`
+		Tax float64 `schema:"tax,omitempty"`
+	}
+	type Order struct {
+		Base
+		Day     Date                `schema:"day"`
+		Items   []Item              `schema:"items"`
+		Tags    map[string]struct{} `schema:"tags"`
+		Note    string              `schema:"note,omitempty"`
+		Status  string              `schema:"status" default:"new"`
+		Numbers []int               `schema:"numbers"`
+	}
+
+	t.Run("faithful", func(t *testing.T) {
+		data := map[string]any{
+			"id":      json.Number("42"),
+			"day":     "2024-03-05",
+			"items":   []any{map[string]any{"sku": "a", "qty": 2}, map[string]any{"sku": "b", "qty": 1.0, "tax": 0.2}},
+			"tags":    []string{"x", "y"},
+			"numbers": []int{1, 2, 3},
+			"unknown": true,
+		}
+
+		got, err := RoundTrip[Order](nil, nil, data)
+
+		require.NoError(t, err)
+		assert.Equal(t, "new", got["status"], "defaults are marshaled")
+		assert.NotContains(t, got, "note")
+		assert.NotContains(t, got, "unknown")
+	})
+
+	t.Run("lossy conversion", func(t *testing.T) {
+		_, err := RoundTrip[Order](nil, nil, map[string]any{"id": "42"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "id")
+	})
+
+	t.Run("unordered set input", func(t *testing.T) {
+		_, err := RoundTrip[Order](nil, nil, map[string]any{"tags": []any{"y", "x"}})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tags[0]")
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		_, err := RoundTrip[Order](nil, nil, map[string]any{"items": "nope"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decode input")
+	})
+}
+
+func TestDiffCanonical(t *testing.T) {
+	tests := []struct {
+		name string
+		want any
+		got  any
+		path string
+	}{
+		{name: "numbers by value", want: json.Number("1.5"), got: float32(1.5)},
+		{name: "ints and uints", want: int8(3), got: uint64(3)},
+		{name: "slices and arrays", want: []int{1, 2}, got: [2]any{1, 2}},
+		{name: "nested maps", want: map[string]any{"a": map[string]any{"b": 1}}, got: map[string]any{"a": map[string]any{"b": 1, "c": 2}}},
+		{name: "missing keys ignored", want: map[string]any{"a": 1}, got: map[string]any{}},
+		{name: "number mismatch", want: 1, got: 2, path: "root"},
+		{name: "kind mismatch", want: "1", got: 1, path: "root"},
+		{name: "length mismatch", want: []any{1}, got: []any{1, 2}, path: "root"},
+		{name: "nested mismatch", want: map[string]any{"a": []any{map[string]any{"b": true}}}, got: map[string]any{"a": []any{map[string]any{"b": false}}}, path: "a[0].b"},
+		{name: "nil mismatch", want: nil, got: "", path: "root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := diffCanonical("", tt.want, tt.got)
+			if tt.path == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.path+":")
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package mapstructure
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"unicode"
+)
+
+// Names of the built-in sanitizers, referenced by the "sanitize" tag option.
+const (
+	// SanitizeControl removes control characters, including newlines and tabs.
+	SanitizeControl = "control"
+
+	// SanitizeSpace collapses runs of whitespace into single spaces and trims the ends.
+	SanitizeSpace = "space"
+
+	// SanitizeSlug lower-cases letters and replaces runs of other characters with "-",
+	// e.g. "Hello, World!" becomes "hello-world". Input without letters or digits fails.
+	SanitizeSlug = "slug"
+)
+
+// Sanitizer cleans a decoded string value, referenced by name from the "sanitize" tag
+// option (sanitize=slug, or sanitize=control|space for several). fieldPath locates
+// the value for error messages; errors are reported as *ConversionError for the field.
+type Sanitizer func(fieldPath, value string) (string, error)
+
+// builtinSanitizers are the sanitizers available to every unmarshaler.
+var builtinSanitizers = map[string]Sanitizer{
+	SanitizeControl: sanitizeControl,
+	SanitizeSpace:   sanitizeSpace,
+	SanitizeSlug:    sanitizeSlug,
+}
+
+// WithSanitizers returns a new unmarshaler extending u with the given sanitizers, keyed
+// by the name the "sanitize" tag option refers to them with, e.g. to add Unicode NFC
+// normalization based on golang.org/x/text/unicode/norm. Sanitizers replace any previously
+// registered, or built in, under the same name. u is left unchanged.
+func (u *Unmarshaler) WithSanitizers(sanitizers map[string]Sanitizer) *Unmarshaler {
+	merged := maps.Clone(u.sanitizers)
+	if merged == nil {
+		merged = make(map[string]Sanitizer, len(sanitizers))
+	}
+	maps.Copy(merged, sanitizers)
+
+	configured := *u
+	configured.sanitizers = merged
+
+	return &configured
+}
+
+// sanitizer returns the sanitizer registered under name, falling back to the built-in ones.
+func (u *Unmarshaler) sanitizer(name string) (Sanitizer, bool) {
+	if sanitizer, ok := u.sanitizers[name]; ok {
+		return sanitizer, true
+	}
+	sanitizer, ok := builtinSanitizers[name]
+
+	return sanitizer, ok
+}
+
+// sanitizeControl implements SanitizeControl.
+func sanitizeControl(_, value string) (string, error) {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, value), nil
+}
+
+// sanitizeSpace implements SanitizeSpace.
+func sanitizeSpace(_, value string) (string, error) {
+	return strings.Join(strings.Fields(value), " "), nil
+}
+
+// sanitizeSlug implements SanitizeSlug.
+func sanitizeSlug(_, value string) (string, error) {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	if b.Len() == 0 && value != "" {
+		return "", errors.New("no letters or digits to build a slug from")
+	}
+
+	return b.String(), nil
+}
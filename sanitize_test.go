@@ -0,0 +1,114 @@
+package mapstructure
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizers_Builtin(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: SanitizeControl, input: "a\x00b\tc\nd​e", want: "abcd​e"},
+		{name: SanitizeSpace, input: "  hello \t\n  world  ", want: "hello world"},
+		{name: SanitizeSlug, input: "  Hello, World! 2024 ", want: "hello-world-2024"},
+		{name: SanitizeSlug, input: "Crème Brûlée", want: "crème-brûlée"},
+		{name: SanitizeSlug, input: "", want: ""},
+		{name: SanitizeSlug, input: "!!!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.input, func(t *testing.T) {
+			got, err := builtinSanitizers[tt.name]("field", tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshaler_WithSanitizers(t *testing.T) {
+	type Article struct {
+		Title string   `schema:"title,sanitize=control|space"`
+		Slug  string   `schema:"slug,trim,sanitize=slug"`
+		Tags  []string `schema:"tags,sanitize=reverse|slug"`
+	}
+
+	var seen []string
+	base := NewDefaultUnmarshaler()
+	u := base.WithSanitizers(map[string]Sanitizer{
+		"reverse": func(fieldPath, value string) (string, error) {
+			seen = append(seen, fieldPath)
+			r := []rune(value)
+			for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+				r[i], r[j] = r[j], r[i]
+			}
+
+			return string(r), nil
+		},
+	})
+
+	data := map[string]any{
+		"title": " Breaking\x07   news ",
+		"slug":  " My First Post ",
+		"tags":  []any{"Go Lang", "ab"},
+	}
+
+	var result Article
+	err := u.Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Breaking news", result.Title)
+	assert.Equal(t, "my-first-post", result.Slug)
+	assert.Equal(t, []string{"gnal-og", "ba"}, result.Tags)
+	assert.Equal(t, []string{"tags[0]", "tags[1]"}, seen, "sanitizers see element paths")
+
+	t.Run("errors carry the field path", func(t *testing.T) {
+		var result Article
+		err := u.Unmarshal(map[string]any{"tags": []any{"ok", "?!"}}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "tags[1]", convErr.FieldPath)
+	})
+
+	t.Run("custom sanitizer error", func(t *testing.T) {
+		strict := base.WithSanitizers(map[string]Sanitizer{
+			SanitizeSpace: func(fieldPath, value string) (string, error) {
+				if strings.Contains(value, "  ") {
+					return "", errors.New("repeated spaces are not allowed")
+				}
+
+				return value, nil
+			},
+		})
+
+		var result Article
+		err := strict.Unmarshal(map[string]any{"title": "a  b"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "title", convErr.FieldPath)
+		assert.Contains(t, err.Error(), "repeated spaces")
+	})
+
+	t.Run("unknown sanitizer", func(t *testing.T) {
+		var result Article
+		err := base.Unmarshal(map[string]any{"tags": []any{"a"}}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Contains(t, err.Error(), `unknown sanitizer "reverse"`)
+	})
+}
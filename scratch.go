@@ -0,0 +1,23 @@
+package mapstructure
+
+// scratch allocates intermediate values of a decode call: values that the decoded result
+// does not reference once the call returns, such as the rows zipped by UnmarshalColumns.
+// Calls without one allocate them on the heap; see WithArena for the experimental
+// arena-backed implementation.
+type scratch interface {
+	// anySlice returns a slice of n nil values.
+	anySlice(n int) []any
+
+	// release frees every value allocated by the scratch. It is called once, when the
+	// call ends.
+	release()
+}
+
+// scratchAnySlice returns a slice of n nil values for intermediate use during the call.
+func (u *Unmarshaler) scratchAnySlice(n int) []any {
+	if s := u.call.scratch; s != nil {
+		return s.anySlice(n)
+	}
+
+	return make([]any, n)
+}
@@ -0,0 +1,41 @@
+package mapstructure
+
+// OptionSkipInvalid is the tag option dropping the elements of a slice field that fail to
+// decode instead of failing the field, for tolerant ingestion of third-party feeds, e.g.
+// `schema:"items,skipinvalid"`. The remaining elements keep their input order, and the
+// dropped ones are listed in DecodeStats.SkippedElements when the call requests WithStats,
+// and in Metadata.Skipped for UnmarshalWithMetadata.
+// Elements assigned without conversion, such as those of []any, never fail.
+const OptionSkipInvalid = "skipinvalid"
+
+// SkippedElement describes a slice element dropped because it failed to decode, see OptionSkipInvalid.
+type SkippedElement struct {
+	FieldPath string // Path of the element, e.g. "items[3]"
+	Index     int    // Position of the element in the input
+	Err       error  // Error that decoding the element returned
+}
+
+// recordSkipped records a slice element dropped after failing with err, discarding the
+// validator errors reported while decoding it, which followed the first validationErrs.
+func (u *Unmarshaler) recordSkipped(fieldPath string, index int, err error, validationErrs int) {
+	if u.state != nil {
+		u.state.validationErrors = u.state.validationErrors[:validationErrs]
+	}
+
+	skipped := SkippedElement{FieldPath: fieldPath, Index: index, Err: err}
+	if stats := u.call.stats; stats != nil {
+		stats.SkippedElements = append(stats.SkippedElements, skipped)
+	}
+	if md := u.call.metadata; md != nil {
+		md.Skipped = append(md.Skipped, skipped)
+	}
+}
+
+// validationErrorCount returns the number of validator errors reported so far by the call.
+func (u *Unmarshaler) validationErrorCount() int {
+	if u.state == nil {
+		return 0
+	}
+
+	return len(u.state.validationErrors)
+}
@@ -0,0 +1,171 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_SkipInvalid(t *testing.T) {
+	type Item struct {
+		SKU string `schema:"sku"`
+		Qty int    `schema:"qty"`
+	}
+	type Feed struct {
+		Items  []Item `schema:"items,skipinvalid"`
+		Scores []int  `schema:"scores,skipinvalid"`
+		Strict []int  `schema:"strict"`
+	}
+
+	tests := []struct {
+		name        string
+		data        map[string]any
+		want        Feed
+		wantSkipped []string // Paths of skipped elements
+		wantErr     string
+	}{
+		{
+			name: "invalid elements dropped",
+			data: map[string]any{"items": []any{
+				map[string]any{"sku": "a", "qty": 1},
+				map[string]any{"sku": "b", "qty": "many"},
+				"not an item",
+				map[string]any{"sku": "c", "qty": "3"},
+			}},
+			want:        Feed{Items: []Item{{SKU: "a", Qty: 1}, {SKU: "c", Qty: 3}}},
+			wantSkipped: []string{"items[1]", "items[2]"},
+		},
+		{
+			name:        "scalars",
+			data:        map[string]any{"scores": []any{1, "x", 3.0, map[string]any{}}},
+			want:        Feed{Scores: []int{1, 3}},
+			wantSkipped: []string{"scores[1]", "scores[3]"},
+		},
+		{
+			name:        "all invalid",
+			data:        map[string]any{"scores": []any{"x", "y"}},
+			want:        Feed{Scores: []int{}},
+			wantSkipped: []string{"scores[0]", "scores[1]"},
+		},
+		{
+			name: "no invalid elements",
+			data: map[string]any{"scores": []any{1, "2"}},
+			want: Feed{Scores: []int{1, 2}},
+		},
+		{
+			name:    "fields without the option still fail",
+			data:    map[string]any{"strict": []any{1, "x"}},
+			wantErr: "strict[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Feed
+			var stats DecodeStats
+			err := Unmarshal(tt.data, &got, WithStats(&stats))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			var paths []string
+			for _, skipped := range stats.SkippedElements {
+				paths = append(paths, skipped.FieldPath)
+				assert.Error(t, skipped.Err)
+			}
+			assert.Equal(t, tt.wantSkipped, paths)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_SkipInvalidDetails(t *testing.T) {
+	type Feed struct {
+		Scores []int `schema:"scores,skipinvalid"`
+	}
+
+	var got Feed
+	var stats DecodeStats
+	require.NoError(t, Unmarshal(map[string]any{"scores": []any{1, "x"}}, &got, WithStats(&stats)))
+	require.Len(t, stats.SkippedElements, 1)
+
+	skipped := stats.SkippedElements[0]
+	assert.Equal(t, 1, skipped.Index)
+	var convErr *ConversionError
+	require.ErrorAs(t, skipped.Err, &convErr)
+	assert.Equal(t, "scores[1]", convErr.FieldPath)
+
+	// Without WithStats elements are still skipped
+	require.NoError(t, Unmarshal(map[string]any{"scores": []any{"x", 2}}, &got))
+	assert.Equal(t, []int{2}, got.Scores)
+}
+
+func TestUnmarshaler_Unmarshal_SkipInvalidValidators(t *testing.T) {
+	type Line struct {
+		Qty   int `schema:"qty"`
+		Price int `schema:"price"`
+	}
+	type Order struct {
+		Lines []struct {
+			Line  Line   `schema:"line"`
+			Label string `schema:"label"`
+			Bad   int    `schema:"bad"`
+		} `schema:"lines,skipinvalid"`
+	}
+
+	u := NewDefaultUnmarshaler().WithValidators(map[reflect.Type]Validator{
+		reflect.TypeFor[Line](): ValidatorFor(func(l *Line) error {
+			if l.Qty <= 0 {
+				return errors.New("qty must be positive")
+			}
+
+			return nil
+		}),
+	})
+
+	var got Order
+	err := u.Unmarshal(map[string]any{"lines": []any{
+		map[string]any{"line": map[string]any{"qty": 0}, "bad": "x"}, // Dropped with its validator error
+		map[string]any{"line": map[string]any{"qty": 1}},
+	}}, &got)
+	require.NoError(t, err)
+	require.Len(t, got.Lines, 1)
+	assert.Equal(t, 1, got.Lines[0].Line.Qty)
+
+	// Validator errors of kept elements are still reported
+	err = u.Unmarshal(map[string]any{"lines": []any{map[string]any{"line": map[string]any{"qty": 0}}}}, &got)
+	assert.ErrorContains(t, err, "qty must be positive")
+}
+
+func TestUnmarshaler_Unmarshal_SkipInvalidReferences(t *testing.T) {
+	type Node struct {
+		Name string `schema:"name"`
+		Next *Node  `schema:"next"` // Met before the failing field
+		N    int    `schema:"n"`
+	}
+	type Graph struct {
+		Items []Node `schema:"items,skipinvalid"`
+		Tail  *Node  `schema:"tail"`
+	}
+
+	var got Graph
+	err := NewDefaultUnmarshaler(WithReferences(nil)).Unmarshal(map[string]any{
+		"items": []any{
+			map[string]any{"name": "a", "next": map[string]any{"$ref": "tail"}, "n": "bad"},
+			map[string]any{"name": "b"},
+		},
+		"tail": map[string]any{"$id": "tail", "name": "tail"},
+	}, &got)
+
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, "b", got.Items[0].Name)
+	assert.Nil(t, got.Items[0].Next, "references of the skipped element are dropped")
+}
@@ -0,0 +1,54 @@
+package mapstructure
+
+import (
+	"io"
+	"reflect"
+	"time"
+)
+
+// DecodeStats holds the counters of one decode call, filled in when requested with WithStats.
+type DecodeStats struct {
+	FieldsSet         int           // Struct fields set from the input or from defaults
+	DefaultsApplied   int           // Struct fields set from default tags, including templates and embedded struct defaults
+	ConvertersInvoked int           // Calls of registered converters, including those that declined the value
+	BytesRead         int64         // Bytes read from io.Reader values converted to []byte
+	Duration          time.Duration // Time spent in the call
+
+	SkippedElements []SkippedElement // Slice elements dropped by the "skipinvalid" tag option, in decoding order
+	Fallbacks       []FieldFallback  // Fields set from the "fallback" tag option, in decoding order
+}
+
+// WithStats makes the call fill in stats, which is reset first, with the counters
+// of the decode, e.g. for performance dashboards. Stats are complete once the call
+// returns, whether it succeeded or not.
+func WithStats(stats *DecodeStats) CallOption {
+	return func(o *callOptions) {
+		o.stats = stats
+	}
+}
+
+// countField records a struct field set by the call, from a default or not.
+func (u *Unmarshaler) countField(fromDefault bool) {
+	stats := u.call.stats
+	if stats == nil {
+		return
+	}
+
+	stats.FieldsSet++
+	if fromDefault {
+		stats.DefaultsApplied++
+	}
+}
+
+// countConversion records a converter invoked on data, which returned converted.
+func (u *Unmarshaler) countConversion(data any, converted reflect.Value) {
+	stats := u.call.stats
+	if stats == nil {
+		return
+	}
+
+	stats.ConvertersInvoked++
+	if _, ok := data.(io.Reader); ok && converted.IsValid() && converted.Kind() == reflect.Slice && converted.Type().Elem().Kind() == reflect.Uint8 {
+		stats.BytesRead += int64(converted.Len())
+	}
+}
@@ -0,0 +1,87 @@
+package mapstructure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_Stats(t *testing.T) {
+	type Upload struct {
+		Name    string `schema:"name"`
+		Port    int    `schema:"port" default:"8080"`
+		Host    string `schema:"host" default:"localhost"`
+		Body    []byte `schema:"body"`
+		Skipped string `schema:"skipped"`
+	}
+
+	tests := []struct {
+		name  string
+		data  map[string]any
+		check func(t *testing.T, stats DecodeStats)
+	}{
+		{
+			name: "fields and defaults",
+			data: map[string]any{"name": "report", "host": "example.com"},
+			check: func(t *testing.T, stats DecodeStats) {
+				t.Helper()
+				assert.Equal(t, 3, stats.FieldsSet)
+				assert.Equal(t, 1, stats.DefaultsApplied)
+				assert.Zero(t, stats.BytesRead)
+			},
+		},
+		{
+			name: "bytes read from readers",
+			data: map[string]any{"body": strings.NewReader("hello"), "port": "9000"},
+			check: func(t *testing.T, stats DecodeStats) {
+				t.Helper()
+				assert.Equal(t, 3, stats.FieldsSet)
+				assert.Equal(t, 1, stats.DefaultsApplied)
+				assert.Equal(t, int64(5), stats.BytesRead)
+				assert.GreaterOrEqual(t, stats.ConvertersInvoked, 2)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := DecodeStats{FieldsSet: 99}
+
+			var result Upload
+			require.NoError(t, Unmarshal(tt.data, &result, WithStats(&stats)))
+			assert.Positive(t, stats.Duration)
+			tt.check(t, stats)
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_StatsOnError(t *testing.T) {
+	type Config struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port"`
+	}
+
+	var stats DecodeStats
+	var result Config
+	err := Unmarshal(map[string]any{"host": "example.com", "port": "nope"}, &result, WithStats(&stats))
+	require.Error(t, err)
+	assert.Equal(t, 1, stats.FieldsSet)
+	assert.Positive(t, stats.ConvertersInvoked)
+	assert.Positive(t, stats.Duration)
+}
+
+func TestUnmarshaler_UnmarshalStrings_Stats(t *testing.T) {
+	type Env struct {
+		Port  int    `schema:"PORT"`
+		Level string `schema:"LEVEL" default:"info"`
+	}
+
+	var stats DecodeStats
+	var result Env
+	require.NoError(t, UnmarshalStrings(map[string]string{"PORT": "8080"}, &result, WithStats(&stats)))
+	assert.Equal(t, 2, stats.FieldsSet)
+	assert.Equal(t, 1, stats.DefaultsApplied)
+	assert.Positive(t, stats.ConvertersInvoked)
+}
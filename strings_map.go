@@ -0,0 +1,138 @@
+package mapstructure
+
+import (
+	"reflect"
+)
+
+// UnmarshalStrings transforms an all-string map, such as HTTP headers, environment
+// variables or labels, into a Go struct pointed to by result.
+// This is a convenience function that uses a shared default unmarshaler.
+func UnmarshalStrings(data map[string]string, result any, opts ...CallOption) error {
+	return defaultUnmarshaler.UnmarshalStrings(data, result, opts...)
+}
+
+// UnmarshalStrings transforms an all-string map into a Go struct pointed to by result.
+// It decodes exactly like Unmarshal with the values stored as strings, but structs whose
+// fields are all strings, bools and numbers are decoded in a single loop over the cached
+// fields, without building an intermediate map[string]any.
+func (u *Unmarshaler) UnmarshalStrings(data map[string]string, result any, opts ...CallOption) error {
+	rv, err := validateResultPointer(result)
+	if err != nil {
+		return err
+	}
+	if err := u.validateTarget(rv.Type()); err != nil {
+		return err
+	}
+	if err := u.checkNilInput(data == nil); err != nil {
+		return err
+	}
+
+	call := u.beginCall(rv.Type(), opts)
+
+	if rv.Kind() == reflect.Struct {
+		metadata := u.fieldCache.GetMetadata(rv.Type())
+		_, transformed := u.transformers[rv.Type()]
+		_, verified := u.verifiers[rv.Type()] // Verifiers need the input as map[string]any
+		if metadata.flatScalars && !transformed && !verified && len(u.hooks) == 0 {
+			return call.endCall(call.unmarshalStringFields(data, rv, metadata))
+		}
+	}
+
+	anyData := make(map[string]any, len(data))
+	for key, value := range data {
+		anyData[key] = value
+	}
+
+	return call.endCall(call.unmarshalValue(anyData, rv, "", nil))
+}
+
+// unmarshalStringFields decodes a struct whose metadata is flatScalars from data.
+// It runs the steps of decodeStruct that apply to such structs, and decodes each field
+// with decodeField like unmarshalFields.
+func (u *Unmarshaler) unmarshalStringFields(data map[string]string, rv reflect.Value, metadata *StructMetadata) error {
+	if err := u.enter(""); err != nil {
+		return err
+	}
+	defer u.leave()
+
+	if metadata.Options.Strict {
+		for key := range data {
+			if !metadata.HasKey(key) {
+				return checkUnknownKeys(stringKeys(data), metadata, "")
+			}
+		}
+	}
+	if len(metadata.funcFields) > 0 && u.funcPolicy != FuncFieldsSkip {
+		if err := u.checkFuncFields(stringKeys(data), metadata, ""); err != nil {
+			return err
+		}
+	}
+	if u.errorUnset {
+		if err := u.checkUnsetFields(stringKeys(data), metadata, nil, ""); err != nil {
+			return err
+		}
+	}
+
+	for i := range metadata.Fields {
+		field := &metadata.Fields[i]
+		if !u.fieldSelected(field) {
+			continue
+		}
+
+		value, exists := data[field.MapKey]
+		if !exists && (!u.hasDefault(field) || field.Default == nil) {
+			continue
+		}
+
+		if err := u.decodeField(value, rv, field, "", !exists, !exists); err != nil {
+			return err
+		}
+	}
+
+	if err := u.applyDefaultFunc(rv, ""); err != nil {
+		return err
+	}
+
+	u.validate(rv, "")
+
+	return nil
+}
+
+// stringKeys returns data as a map[string]any sharing its keys, for key-only checks.
+func stringKeys(data map[string]string) map[string]any {
+	keys := make(map[string]any, len(data))
+	for key := range data {
+		keys[key] = nil
+	}
+
+	return keys
+}
+
+// isFlatScalars reports whether a struct with the given fields can be decoded by
+// unmarshalStringFields: every field is a string, bool or number, and none needs
+// the struct-level steps of unmarshalFields, i.e. embedded structs, template defaults
+// and oneof groups. Tag options of single fields are applied by decodeField.
+func isFlatScalars(fields []FieldMetadata, oneOf []oneOfGroup) bool {
+	if len(oneOf) > 0 {
+		return false
+	}
+
+	for i := range fields {
+		field := &fields[i]
+		if field.Embedded || field.defaultTemplate != nil {
+			return false
+		}
+
+		//nolint:exhaustive // Only scalar kinds qualify
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,213 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFlatScalars(t *testing.T) {
+	type Inner struct {
+		A string
+	}
+	type Embeds struct {
+		Inner
+	}
+
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want bool
+	}{
+		{name: "scalars", typ: reflect.TypeFor[struct {
+			A string
+			B int
+			C bool
+			D float64
+			E uint8
+		}](), want: true},
+		{name: "groups and versions", typ: reflect.TypeFor[struct {
+			A string `schema:"a,groups=admin,since=2,until=3"`
+		}](), want: true},
+		{name: "named scalar", typ: reflect.TypeFor[struct {
+			D time.Duration
+		}](), want: true},
+		{name: "plain default", typ: reflect.TypeFor[struct {
+			A string `default:"x"`
+		}](), want: true},
+		{name: "template default", typ: reflect.TypeFor[struct {
+			A string `default:"{{.B}}"`
+			B string
+		}]()},
+		{name: "field options", typ: reflect.TypeFor[struct {
+			A int    `schema:"a,format=bytes"`
+			B string `schema:"b,trim,maxlen=3,fallback=x"`
+		}](), want: true},
+		{name: "oneof", typ: reflect.TypeFor[struct {
+			A string `schema:"a,oneof=g"`
+		}]()},
+		{name: "slice", typ: reflect.TypeFor[struct {
+			A []string
+		}]()},
+		{name: "pointer", typ: reflect.TypeFor[struct {
+			A *string
+		}]()},
+		{name: "struct", typ: reflect.TypeFor[struct {
+			A time.Time
+		}]()},
+		{name: "embedded", typ: reflect.TypeFor[Embeds]()},
+	}
+
+	cache := NewDefaultStructMetadataCache()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cache.GetMetadata(tt.typ).flatScalars)
+		})
+	}
+}
+
+func TestUnmarshaler_UnmarshalStrings(t *testing.T) {
+	type Headers struct {
+		ContentType string  `schema:"Content-Type"`
+		Length      int     `schema:"Content-Length"`
+		KeepAlive   bool    `schema:"Keep-Alive" default:"true"`
+		Ratio       float64 `schema:"X-Ratio"`
+		Admin       string  `schema:"X-Admin,groups=admin"`
+	}
+
+	type Nested struct {
+		Name  string            `schema:"name"`
+		Extra map[string]string `schema:"extra"`
+	}
+
+	t.Run("fast path", func(t *testing.T) {
+		data := map[string]string{
+			"Content-Type":   "text/plain",
+			"Content-Length": "42",
+			"X-Ratio":        "0.5",
+			"X-Timeout":      "1500000000",
+			"X-Admin":        "root",
+		}
+
+		var result Headers
+		require.NoError(t, UnmarshalStrings(data, &result))
+		assert.Equal(t, Headers{
+			ContentType: "text/plain",
+			Length:      42,
+			KeepAlive:   true,
+			Ratio:       0.5,
+		}, result, "grouped fields need WithGroups")
+
+		// The generic path decodes identically
+		anyData := make(map[string]any, len(data))
+		for k, v := range data {
+			anyData[k] = v
+		}
+		var generic Headers
+		require.NoError(t, Unmarshal(anyData, &generic))
+		assert.Equal(t, generic, result)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		var result Headers
+		require.NoError(t, UnmarshalStrings(map[string]string{"X-Admin": "root"}, &result, WithGroups("admin")))
+		assert.Equal(t, "root", result.Admin)
+	})
+
+	t.Run("conversion error", func(t *testing.T) {
+		var result Headers
+		err := UnmarshalStrings(map[string]string{"Content-Length": "abc"}, &result)
+		require.Error(t, err)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "Content-Length", convErr.FieldPath)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		type Strict struct {
+			_    struct{} `schema:",strict"`
+			Name string   `schema:"name"`
+		}
+
+		var result Strict
+		err := UnmarshalStrings(map[string]string{"name": "a", "role": "b"}, &result)
+
+		var conErr *ConstraintError
+		require.ErrorAs(t, err, &conErr)
+		assert.Equal(t, "role", conErr.FieldPath)
+	})
+
+	t.Run("validators", func(t *testing.T) {
+		u := NewDefaultUnmarshaler().WithValidators(map[reflect.Type]Validator{
+			reflect.TypeFor[Headers](): ValidatorFor(func(h *Headers) error {
+				if h.Length < 0 {
+					return errors.New("negative length")
+				}
+
+				return nil
+			}),
+		})
+
+		var result Headers
+		err := u.UnmarshalStrings(map[string]string{"Content-Length": "-1"}, &result)
+
+		var valErr *StructValidationError
+		require.ErrorAs(t, err, &valErr)
+	})
+
+	t.Run("field options", func(t *testing.T) {
+		type Options struct {
+			Size int    `schema:"size,format=bytes"`
+			Name string `schema:"name,trim,upper"`
+			Mode int    `schema:"mode,fallback=1"`
+		}
+
+		data := map[string]string{"size": "2KiB", "name": " a ", "mode": "fast"}
+
+		var result Options
+		require.NoError(t, UnmarshalStrings(data, &result))
+		assert.Equal(t, Options{Size: 2048, Name: "A", Mode: 1}, result)
+
+		// The generic path decodes identically
+		anyData := make(map[string]any, len(data))
+		for k, v := range data {
+			anyData[k] = v
+		}
+		var generic Options
+		require.NoError(t, Unmarshal(anyData, &generic))
+		assert.Equal(t, generic, result)
+	})
+
+	t.Run("transformers use the generic path", func(t *testing.T) {
+		u := NewDefaultUnmarshaler().WithTransformers(map[reflect.Type]Transformer{
+			reflect.TypeFor[Headers](): func(m map[string]any) map[string]any {
+				m["Content-Type"] = "application/json"
+
+				return m
+			},
+		})
+
+		var result Headers
+		require.NoError(t, u.UnmarshalStrings(map[string]string{}, &result))
+		assert.Equal(t, "application/json", result.ContentType)
+	})
+
+	t.Run("generic path", func(t *testing.T) {
+		var result Nested
+		require.NoError(t, UnmarshalStrings(map[string]string{"name": "a"}, &result))
+		assert.Equal(t, Nested{Name: "a"}, result)
+	})
+
+	t.Run("invalid result", func(t *testing.T) {
+		var result Headers
+		err := UnmarshalStrings(map[string]string{}, result)
+
+		var valErr *ValidationError
+		require.ErrorAs(t, err, &valErr)
+	})
+}
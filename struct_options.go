@@ -0,0 +1,76 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/talav/tagparser"
+)
+
+// StructOptions holds decoding options declared by a struct type itself, so the
+// behavior of a type does not depend on which Unmarshaler decodes it.
+//
+// A struct declares its options either by implementing StructOptionsProvider or with a
+// blank marker field carrying the options in its tag:
+//
+//	type Request struct {
+//		_    struct{} `schema:",strict"`
+//		Name string   `schema:"name"`
+//	}
+//
+// When both are present, DecodeOptions takes precedence.
+type StructOptions struct {
+	// Strict rejects input keys that do not map to any field of the struct,
+	// including fields promoted from embedded structs.
+	// Set with the "strict" or "allowunknown=false" marker options.
+	Strict bool
+}
+
+// StructOptionsProvider is implemented by struct types that declare their own decoding options.
+// DecodeOptions is called once per type on its zero value, and the result is cached.
+type StructOptionsProvider interface {
+	DecodeOptions() StructOptions
+}
+
+var structOptionsProviderType = reflect.TypeOf((*StructOptionsProvider)(nil)).Elem()
+
+// resolveStructOptions determines the options of typ from its StructOptionsProvider
+// implementation or, failing that, from the tag of its blank marker field.
+func resolveStructOptions(typ reflect.Type, markerTag string) StructOptions {
+	switch {
+	case typ.Implements(structOptionsProviderType):
+		//nolint:forcetypeassert // Checked by Implements
+		return reflect.Zero(typ).Interface().(StructOptionsProvider).DecodeOptions()
+	case reflect.PointerTo(typ).Implements(structOptionsProviderType):
+		//nolint:forcetypeassert // Checked by Implements
+		return reflect.New(typ).Interface().(StructOptionsProvider).DecodeOptions()
+	}
+
+	return parseStructOptions(markerTag)
+}
+
+// parseStructOptions parses struct options from a marker field tag such as ",strict".
+// Unknown options and malformed tags are ignored.
+func parseStructOptions(tagValue string) StructOptions {
+	var opts StructOptions
+	if tagValue == "" {
+		return opts
+	}
+
+	tag, err := tagparser.ParseWithName(tagValue)
+	if err != nil {
+		return opts
+	}
+
+	if _, ok := tag.Options["strict"]; ok {
+		opts.Strict = true
+	}
+
+	if v, ok := tag.Options["allowunknown"]; ok {
+		if allow, err := strconv.ParseBool(v); err == nil {
+			opts.Strict = !allow
+		}
+	}
+
+	return opts
+}
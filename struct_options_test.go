@@ -0,0 +1,148 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictByMethod struct {
+	Name string `schema:"name"`
+}
+
+func (strictByMethod) DecodeOptions() StructOptions {
+	return StructOptions{Strict: true}
+}
+
+type strictByPointerMethod struct {
+	_    struct{} `schema:",allowunknown=true"`
+	Name string   `schema:"name"`
+}
+
+func (*strictByPointerMethod) DecodeOptions() StructOptions {
+	return StructOptions{Strict: true}
+}
+
+func TestParseStructOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		tagValue string
+		want     StructOptions
+	}{
+		{name: "empty", tagValue: "", want: StructOptions{}},
+		{name: "strict", tagValue: ",strict", want: StructOptions{Strict: true}},
+		{name: "allowunknown false", tagValue: ",allowunknown=false", want: StructOptions{Strict: true}},
+		{name: "allowunknown true overrides strict", tagValue: ",strict,allowunknown=true", want: StructOptions{}},
+		{name: "invalid bool ignored", tagValue: ",allowunknown=maybe", want: StructOptions{}},
+		{name: "unknown option ignored", tagValue: ",frobnicate", want: StructOptions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseStructOptions(tt.tagValue))
+		})
+	}
+}
+
+func TestStructMetadataCache_StructOptions(t *testing.T) {
+	type Marker struct {
+		_    struct{} `schema:",strict"`
+		Name string   `schema:"name"`
+	}
+
+	type Plain struct {
+		Name string `schema:"name"`
+	}
+
+	cache := NewDefaultStructMetadataCache()
+
+	assert.True(t, cache.GetMetadata(reflect.TypeOf(Marker{})).Options.Strict)
+	assert.Len(t, cache.GetMetadata(reflect.TypeOf(Marker{})).Fields, 1, "marker field is not a data field")
+	assert.False(t, cache.GetMetadata(reflect.TypeOf(Plain{})).Options.Strict)
+	assert.True(t, cache.GetMetadata(reflect.TypeOf(strictByMethod{})).Options.Strict)
+	assert.True(t, cache.GetMetadata(reflect.TypeOf(strictByPointerMethod{})).Options.Strict, "DecodeOptions wins over marker")
+
+	t.Run("marker ignored without tags", func(t *testing.T) {
+		cache := NewStructMetadataCache("-", "")
+		assert.False(t, cache.GetMetadata(reflect.TypeOf(Marker{})).Options.Strict)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_StrictStructs(t *testing.T) {
+	type Base struct {
+		ID string `schema:"id"`
+	}
+
+	type Address struct {
+		_    struct{} `schema:",strict"`
+		City string   `schema:"city"`
+	}
+
+	type User struct {
+		_ struct{} `schema:",strict"`
+		Base
+		Name    string  `schema:"name"`
+		Address Address `schema:"address"`
+	}
+
+	t.Run("known keys including promoted", func(t *testing.T) {
+		data := map[string]any{
+			"id":      "u1",
+			"name":    "Alice",
+			"address": map[string]any{"city": "Paris"},
+		}
+
+		var result User
+		err := NewDefaultUnmarshaler().Unmarshal(data, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, "u1", result.ID)
+		assert.Equal(t, "Paris", result.Address.City)
+	})
+
+	t.Run("named embedded key", func(t *testing.T) {
+		var result User
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"Base": map[string]any{"id": "u2"}}, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, "u2", result.ID)
+	})
+
+	t.Run("unknown top-level key", func(t *testing.T) {
+		var result User
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"name": "Alice", "role": "admin", "age": 3}, &result)
+
+		var constraintErr *ConstraintError
+		require.ErrorAs(t, err, &constraintErr)
+		assert.Equal(t, "age", constraintErr.FieldPath)
+		assert.Equal(t, "strict", constraintErr.Constraint)
+		assert.EqualError(t, err, `age: unknown key "age"`)
+	})
+
+	t.Run("unknown nested key", func(t *testing.T) {
+		var result User
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"address": map[string]any{"zip": "75001"}}, &result)
+
+		var constraintErr *ConstraintError
+		require.ErrorAs(t, err, &constraintErr)
+		assert.Equal(t, "address.zip", constraintErr.FieldPath)
+	})
+
+	t.Run("strict via DecodeOptions", func(t *testing.T) {
+		var result strictByMethod
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"extra": 1}, &result)
+		require.Error(t, err)
+	})
+
+	t.Run("non-strict structs ignore unknown keys", func(t *testing.T) {
+		type Loose struct {
+			Name string `schema:"name"`
+		}
+
+		var result Loose
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"name": "x", "extra": 1}, &result)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package mapstructure
+
+import (
+	"maps"
+	"reflect"
+)
+
+// Transformer rewrites the input map of a struct before its fields are mapped,
+// e.g. to rename keys, split or merge values, or up-convert old payload shapes.
+// It receives a shallow copy of the input, so it may add and delete keys freely,
+// but nested maps and slices are shared with the caller and must not be modified.
+type Transformer func(data map[string]any) map[string]any
+
+// WithTransformers returns a new unmarshaler extending u with the given transformers,
+// keyed by the struct type whose input they rewrite. A transformer runs whenever that
+// type is decoded from its own map (at the top level, as a nested field, or as a slice
+// element), but not for promoted fields of anonymous embedded structs.
+// Transformers replace any previously registered for the same type. u is left unchanged.
+func (u *Unmarshaler) WithTransformers(transformers map[reflect.Type]Transformer) *Unmarshaler {
+	merged := maps.Clone(u.transformers)
+	if merged == nil {
+		merged = make(map[reflect.Type]Transformer, len(transformers))
+	}
+	maps.Copy(merged, transformers)
+
+	configured := *u
+	configured.transformers = merged
+
+	return &configured
+}
+
+// transform applies the transformer registered for typ, if any, to a copy of dataMap.
+func (u *Unmarshaler) transform(typ reflect.Type, dataMap map[string]any) map[string]any {
+	transformer, ok := u.transformers[typ]
+	if !ok {
+		return dataMap
+	}
+
+	transformed := transformer(maps.Clone(dataMap))
+	if transformed == nil {
+		return map[string]any{}
+	}
+
+	return transformed
+}
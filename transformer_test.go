@@ -0,0 +1,89 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_WithTransformers(t *testing.T) {
+	type Contact struct {
+		FirstName string `schema:"first_name"`
+		LastName  string `schema:"last_name"`
+	}
+
+	type Account struct {
+		_        struct{}  `schema:",strict"`
+		Email    string    `schema:"email"`
+		Owner    Contact   `schema:"owner"`
+		Contacts []Contact `schema:"contacts"`
+	}
+
+	// v1 payloads carried "mail" and a single "name" field
+	accountV1 := func(data map[string]any) map[string]any {
+		if mail, ok := data["mail"]; ok {
+			data["email"] = mail
+			delete(data, "mail")
+		}
+
+		return data
+	}
+	splitName := func(data map[string]any) map[string]any {
+		if name, ok := data["name"].(string); ok {
+			first, last, _ := strings.Cut(name, " ")
+			data["first_name"], data["last_name"] = first, last
+			delete(data, "name")
+		}
+
+		return data
+	}
+
+	base := NewDefaultUnmarshaler()
+	u := base.WithTransformers(map[reflect.Type]Transformer{
+		reflect.TypeOf(Account{}): accountV1,
+		reflect.TypeOf(Contact{}): splitName,
+	})
+
+	owner := map[string]any{"name": "Ada Lovelace"}
+	data := map[string]any{
+		"mail":     "ada@example.com",
+		"owner":    owner,
+		"contacts": []any{map[string]any{"name": "Charles Babbage"}, map[string]any{"first_name": "Mary"}},
+	}
+
+	var result Account
+	err := u.Unmarshal(data, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", result.Email)
+	assert.Equal(t, Contact{FirstName: "Ada", LastName: "Lovelace"}, result.Owner)
+	assert.Equal(t, []Contact{{FirstName: "Charles", LastName: "Babbage"}, {FirstName: "Mary"}}, result.Contacts)
+
+	t.Run("input maps are not modified", func(t *testing.T) {
+		assert.Contains(t, data, "mail")
+		assert.NotContains(t, data, "email")
+		assert.Equal(t, map[string]any{"name": "Ada Lovelace"}, owner)
+	})
+
+	t.Run("receiver is unchanged", func(t *testing.T) {
+		var result Account
+		err := base.Unmarshal(map[string]any{"mail": "x"}, &result)
+		require.Error(t, err, "strict struct rejects untransformed key")
+	})
+
+	t.Run("later registrations override", func(t *testing.T) {
+		override := u.WithTransformers(map[reflect.Type]Transformer{
+			reflect.TypeOf(Contact{}): func(map[string]any) map[string]any { return nil },
+		})
+
+		var result Account
+		err := override.Unmarshal(map[string]any{"mail": "m", "owner": map[string]any{"first_name": "x"}}, &result)
+
+		require.NoError(t, err)
+		assert.Equal(t, "m", result.Email)
+		assert.Equal(t, Contact{}, result.Owner)
+	})
+}
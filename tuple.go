@@ -0,0 +1,43 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OptionTuple is the tag option decoding structs from positional arrays, e.g.
+// `schema:"owner,tuple"` decodes ["alice", 30, true] into the first three fields of
+// the struct. It applies to slice elements too, so []Person with the option decodes
+// rows of a CSV-like array of arrays.
+const OptionTuple = "tuple"
+
+// tupleInput converts data, a positional slice or array decoded into the struct type typ
+// with the "tuple" tag option, into the equivalent map keyed by field: element i sets the
+// i-th field in declaration order, fields promoted from an embedded struct taking its
+// place. Missing trailing elements leave their fields to
+// defaults; extra elements are an error. Other data is returned unchanged.
+func (u *Unmarshaler) tupleInput(data any, typ reflect.Type, fieldPath string) (any, error) {
+	dataVal := reflect.ValueOf(data)
+	if dataVal.Kind() != reflect.Slice && dataVal.Kind() != reflect.Array {
+		return data, nil
+	}
+
+	metadata := u.fieldCache.GetMetadata(typ)
+	keys := make([]string, 0, len(metadata.flat))
+	for i := range metadata.flat {
+		if !metadata.flat[i].Embedded {
+			keys = append(keys, metadata.flat[i].MapKey)
+		}
+	}
+
+	if dataVal.Len() > len(keys) {
+		return nil, NewConversionError(fieldPath, data, typ, fmt.Errorf("tuple has %d elements, struct has %d fields", dataVal.Len(), len(keys)))
+	}
+
+	dataMap := make(map[string]any, dataVal.Len())
+	for i := range dataVal.Len() {
+		dataMap[keys[i]] = dataVal.Index(i).Interface()
+	}
+
+	return dataMap, nil
+}
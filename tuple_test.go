@@ -0,0 +1,114 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tuplePerson struct {
+	Name   string `schema:"name"`
+	Age    int    `schema:"age"`
+	Active bool   `schema:"active"`
+	Role   string `schema:"role" default:"member"`
+}
+
+type tupleAudited struct {
+	TupleAuditInfo
+
+	ID   int    `schema:"id"`
+	Note string `schema:"-"`
+}
+
+type TupleAuditInfo struct {
+	CreatedBy string `schema:"created_by"`
+}
+
+func TestUnmarshaler_Unmarshal_Tuple(t *testing.T) {
+	type Payload struct {
+		Owner   tuplePerson   `schema:"owner,tuple"`
+		Members []tuplePerson `schema:"members,tuple"`
+		Backup  *tuplePerson  `schema:"backup,tuple"`
+		Record  tupleAudited  `schema:"record,tuple"`
+	}
+
+	tests := []struct {
+		name     string
+		data     map[string]any
+		expected Payload
+	}{
+		{
+			name:     "positional elements with conversion",
+			data:     map[string]any{"owner": []any{"alice", "30", 1}},
+			expected: Payload{Owner: tuplePerson{Name: "alice", Age: 30, Active: true, Role: "member"}},
+		},
+		{
+			name:     "all elements",
+			data:     map[string]any{"owner": []any{"bob", 41, false, "admin"}},
+			expected: Payload{Owner: tuplePerson{Name: "bob", Age: 41, Role: "admin"}},
+		},
+		{
+			name:     "typed slice",
+			data:     map[string]any{"owner": []string{"carol", "25"}},
+			expected: Payload{Owner: tuplePerson{Name: "carol", Age: 25, Role: "member"}},
+		},
+		{
+			name: "rows of a slice",
+			data: map[string]any{"members": []any{[]any{"a", 1}, []any{"b", 2, true}}},
+			expected: Payload{Members: []tuplePerson{
+				{Name: "a", Age: 1, Role: "member"},
+				{Name: "b", Age: 2, Active: true, Role: "member"},
+			}},
+		},
+		{
+			name:     "pointer",
+			data:     map[string]any{"backup": []any{"dave"}},
+			expected: Payload{Backup: &tuplePerson{Name: "dave", Role: "member"}},
+		},
+		{
+			name:     "maps still decode by key",
+			data:     map[string]any{"owner": map[string]any{"age": 5}},
+			expected: Payload{Owner: tuplePerson{Age: 5, Role: "member"}},
+		},
+		{
+			name:     "promoted fields in place of their embedded struct",
+			data:     map[string]any{"record": []any{"system", 7}},
+			expected: Payload{Record: tupleAudited{ID: 7, TupleAuditInfo: TupleAuditInfo{CreatedBy: "system"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Payload
+			require.NoError(t, Unmarshal(tt.data, &result))
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("too many elements", func(t *testing.T) {
+		var result Payload
+		err := Unmarshal(map[string]any{"members": []any{[]any{"a", 1, true, "x", "extra"}}}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "members[0]", convErr.FieldPath)
+		assert.Contains(t, convErr.Error(), "tuple has 5 elements, struct has 4 fields")
+	})
+
+	t.Run("element errors name the field", func(t *testing.T) {
+		var result Payload
+		err := Unmarshal(map[string]any{"owner": []any{"alice", "old"}}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "owner.age", convErr.FieldPath)
+	})
+
+	t.Run("without the option arrays are rejected", func(t *testing.T) {
+		var result struct {
+			Owner tuplePerson `schema:"owner"`
+		}
+		require.Error(t, Unmarshal(map[string]any{"owner": []any{"alice"}}, &result))
+	})
+}
@@ -4,15 +4,88 @@ import "reflect"
 
 // FieldMetadata holds cached struct field information.
 type FieldMetadata struct {
-	StructFieldName string       // Go field name
-	MapKey          string       // Key to lookup in map
-	Index           int          // Field index for reflection
-	Type            reflect.Type // Field type
-	Embedded        bool         // Anonymous/embedded struct
-	Default         *string      // Raw default value from `default` tag, nil if no tag
+	StructFieldName string            // Go field name
+	MapKey          string            // Key to lookup in map
+	Index           []int             // Field index path for reflection, see reflect.StructField.Index
+	Type            reflect.Type      // Field type
+	Embedded        bool              // Anonymous/embedded struct or pointer to struct, or squashed field
+	Default         *string           // Raw default value from `default` tag, nil if no tag
+	Options         map[string]string // Tag options after the map key (e.g. "unit=ms"), nil if none
+	Groups          []string          // Groups from the "groups" tag option, nil if none
+	Since           int               // First version with the field ("since" tag option), 0 if unbounded
+	Until           int               // Last version with the field ("until" tag option), 0 if unbounded
+	Doc             string            // Doc comment of the field, empty if unknown, see WithFieldDocs
+
+	defaultTemplate *defaultTemplate // Parsed Default referencing sibling fields, nil for plain defaults
+	defaultValues   map[string]any   // Parsed Default of an embedded struct, by promoted key
+	embeddedDefault *any             // Value for the field from the Default of an embedded struct declaring it
+	promoted        *StructMetadata  // Metadata of the embedded struct whose fields are promoted, nil if none
+	pos             int              // Position in StructMetadata.flat
+	owner           int              // One plus the position in StructMetadata.flat of the embedded field declaring the field, 0 for own fields
+	named           bool             // MapKey is given by the tag rather than the Go field name
+}
+
+// Option returns the value of the named tag option and whether it was present.
+func (f *FieldMetadata) Option(name string) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+
+	v, ok := f.Options[name]
+
+	return v, ok
 }
 
 // StructMetadata holds cached metadata for a struct type.
 type StructMetadata struct {
-	Fields []FieldMetadata
+	Fields  []FieldMetadata
+	Options StructOptions // Options declared by the struct type
+
+	knownKeys  map[string]struct{}       // Map keys accepted by the struct, including promoted ones
+	oneOf      []oneOfGroup              // Mutually exclusive field groups from "oneof" tag options
+	flat       []FieldMetadata           // Fields followed by the fields promoted from embedded structs
+	byKey      map[string]*FieldMetadata // Non-embedded fields of flat by map key
+	eager      []int                     // Positions in flat of embedded fields and fields with defaults
+	funcFields []FieldMetadata           // Func and chan fields, including promoted ones, excluded from flat
+	tagErr     error                     // First malformed field tag, including in embedded structs, see TagError
+	remain     int                       // One plus the position in flat of the field receiving unknown keys, 0 if none
+
+	flatScalars bool // All fields are plain scalars, see UnmarshalStrings
+}
+
+// FieldByKey returns the field decoded from key, which may be promoted from an
+// embedded struct, and whether there is one.
+func (m *StructMetadata) FieldByKey(key string) (*FieldMetadata, bool) {
+	field, ok := m.byKey[key]
+
+	return field, ok
+}
+
+// ResolvedFields returns the fields decoded by map key, including those promoted from
+// embedded structs, which follow their embedded struct in declaration order. Fields
+// shadowed by or tied with another field of the same key are left out, as decoding
+// ignores them. The returned metadata is shared and must not be modified.
+func (m *StructMetadata) ResolvedFields() []*FieldMetadata {
+	fields := make([]*FieldMetadata, 0, len(m.byKey))
+	for i := range m.flat {
+		if !m.flat[i].Embedded {
+			fields = append(fields, &m.flat[i])
+		}
+	}
+
+	return fields
+}
+
+// Err returns the TagError of the first malformed field tag of the struct, including
+// tags of embedded structs, or nil. Decoding the struct fails with this error.
+func (m *StructMetadata) Err() error {
+	return m.tagErr
+}
+
+// HasKey reports whether key maps to a field of the struct, directly, as a named
+// embedded struct, or as a field promoted from an embedded struct.
+func (m *StructMetadata) HasKey(key string) bool {
+	_, ok := m.knownKeys[key]
+
+	return ok
 }
@@ -0,0 +1,55 @@
+package mapstructure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithErrorUnset makes the unmarshaler fail when struct fields receive no value: their
+// key is missing from the input and they have no default tag, the mirror of strict
+// structs rejecting unknown keys, for API payloads that must supply every field. Keys
+// present with a nil value count as set, and embedded structs decoded from a nested map
+// are checked against that map. Fields outside the groups and version selected for the
+// call and remainder fields are not required; default funcs run after the check, so
+// fields they derive must be given a default tag or supplied. Each unset field is
+// reported as a *ConstraintError with Constraint "unset", joined into one error.
+func WithErrorUnset() Option {
+	return func(u *Unmarshaler) {
+		u.errorUnset = true
+	}
+}
+
+// checkUnsetFields returns the ConstraintErrors of the fields of metadata, in field
+// order, that dataMap gives no value and that have no default, when the unmarshaler
+// reports unset fields.
+func (u *Unmarshaler) checkUnsetFields(dataMap map[string]any, metadata *StructMetadata, closed []bool, fieldPath string) error {
+	if !u.errorUnset {
+		return nil
+	}
+
+	var errs []error
+	for _, field := range u.unsetFields(dataMap, metadata, closed) {
+		errs = append(errs, NewConstraintError(buildFieldPath(fieldPath, field.MapKey), "unset",
+			fmt.Sprintf("missing key %q", field.MapKey)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// unsetFields returns the fields of metadata, in field order, that dataMap gives no
+// value and that have no default. closed is as returned by closedEmbeds.
+func (u *Unmarshaler) unsetFields(dataMap map[string]any, metadata *StructMetadata, closed []bool) []*FieldMetadata {
+	var unset []*FieldMetadata
+	for i := range metadata.flat {
+		field := &metadata.flat[i]
+		if field.Embedded || u.hasDefault(field) || !isOpen(closed, field) || !u.fieldSelected(field) || isRemain(field) {
+			continue
+		}
+
+		if _, ok := dataMap[field.MapKey]; !ok {
+			unset = append(unset, field)
+		}
+	}
+
+	return unset
+}
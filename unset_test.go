@@ -0,0 +1,123 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_Unmarshal_ErrorUnset(t *testing.T) {
+	type Address struct {
+		City string `schema:"city"`
+		Zip  string `schema:"zip" default:"00000"`
+	}
+	type Meta struct {
+		Source string `schema:"source"`
+	}
+	type User struct {
+		Meta
+		Name    string         `schema:"name"`
+		Age     int            `schema:"age"`
+		Role    string         `schema:"role" default:"user"`
+		Cost    int            `schema:"cost,groups=admin"`
+		Address *Address       `schema:"address"`
+		Extra   map[string]any `schema:",remain"`
+	}
+
+	tests := []struct {
+		name      string
+		data      map[string]any
+		wantPaths []string // Paths of the unset fields reported
+	}{
+		{
+			name: "all fields supplied",
+			data: map[string]any{"source": "api", "name": "a", "age": 1, "address": map[string]any{"city": "x"}},
+		},
+		{
+			name:      "missing fields reported in field order",
+			data:      map[string]any{"name": "a"},
+			wantPaths: []string{"source", "age", "address"},
+		},
+		{
+			name: "nil values count as set",
+			data: map[string]any{"source": "api", "name": "a", "age": 1, "address": nil},
+		},
+		{
+			name:      "nested structs",
+			data:      map[string]any{"source": "api", "name": "a", "age": 1, "address": map[string]any{}},
+			wantPaths: []string{"address.city"},
+		},
+		{
+			name:      "embedded structs decoded from a nested map",
+			data:      map[string]any{"Meta": map[string]any{}, "name": "a", "age": 1, "address": nil},
+			wantPaths: []string{"source"},
+		},
+	}
+
+	u := NewDefaultUnmarshaler(WithErrorUnset())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got User
+			err := u.Unmarshal(tt.data, &got)
+			if len(tt.wantPaths) == 0 {
+				require.NoError(t, err)
+				assert.Equal(t, "user", got.Role)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantPaths, unsetPaths(err))
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_ErrorUnsetDetails(t *testing.T) {
+	type Config struct {
+		Host string `schema:"host"`
+		Port int    `schema:"port"`
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var got Config
+		require.NoError(t, Unmarshal(map[string]any{}, &got))
+	})
+
+	t.Run("error message", func(t *testing.T) {
+		var got Config
+		err := NewDefaultUnmarshaler(WithErrorUnset()).Unmarshal(map[string]any{"host": "a"}, &got)
+		assert.EqualError(t, err, `port: missing key "port"`)
+	})
+
+	t.Run("string maps", func(t *testing.T) {
+		var got Config
+		err := NewDefaultUnmarshaler(WithErrorUnset()).UnmarshalStrings(map[string]string{"port": "80"}, &got)
+		assert.EqualError(t, err, `host: missing key "host"`)
+	})
+
+	t.Run("normalize", func(t *testing.T) {
+		_, err := NewDefaultUnmarshaler(WithErrorUnset()).Normalize(map[string]any{"host": "a"}, reflect.TypeFor[Config]())
+		assert.EqualError(t, err, `port: missing key "port"`)
+	})
+}
+
+// unsetPaths returns the field paths of the unset field errors in the tree of err.
+func unsetPaths(err error) []string {
+	var paths []string
+	switch wrapped := err.(type) {
+	case *ConstraintError:
+		if wrapped.Constraint == "unset" {
+			paths = append(paths, wrapped.FieldPath)
+		}
+	case interface{ Unwrap() []error }:
+		for _, e := range wrapped.Unwrap() {
+			paths = append(paths, unsetPaths(e)...)
+		}
+	case interface{ Unwrap() error }:
+		paths = unsetPaths(wrapped.Unwrap())
+	}
+
+	return paths
+}
@@ -0,0 +1,58 @@
+package mapstructure
+
+import "reflect"
+
+// UnsupportedFields returns an UnsupportedTypeError for every field reachable from typ
+// (usually a struct type) that Unmarshal can only fill by direct assignment of an input
+// value of the exact field type, because no converter is registered for its type and it
+// is not a pointer, slice, set or struct. Maps and interfaces are assumed to be assigned
+// directly and are not reported. Elements of slices and sets get a "[]" path suffix.
+func (u *Unmarshaler) UnsupportedFields(typ reflect.Type) []*UnsupportedTypeError {
+	var unsupported []*UnsupportedTypeError
+	u.collectUnsupported(typ, "", make(map[reflect.Type]struct{}), &unsupported)
+
+	return unsupported
+}
+
+// collectUnsupported appends the unsupported types reachable from typ, decoded at
+// fieldPath, to unsupported. visiting holds the struct types being walked, so
+// recursive types are walked once.
+func (u *Unmarshaler) collectUnsupported(typ reflect.Type, fieldPath string, visiting map[reflect.Type]struct{},
+	unsupported *[]*UnsupportedTypeError,
+) {
+	if u.hasConverter(typ) {
+		return
+	}
+
+	if isSetType(typ) {
+		u.collectUnsupported(typ.Key(), fieldPath+"[]", visiting, unsupported)
+
+		return
+	}
+
+	//nolint:exhaustive // Remaining kinds are unsupported
+	switch typ.Kind() {
+	case reflect.Ptr:
+		u.collectUnsupported(typ.Elem(), fieldPath, visiting, unsupported)
+	case reflect.Slice:
+		u.collectUnsupported(typ.Elem(), fieldPath+"[]", visiting, unsupported)
+	case reflect.Struct:
+		if _, ok := visiting[typ]; ok {
+			return
+		}
+		visiting[typ] = struct{}{}
+		defer delete(visiting, typ)
+
+		metadata := u.fieldCache.GetMetadata(typ)
+		for i := range metadata.flat {
+			field := &metadata.flat[i]
+			if field.Embedded {
+				continue // Promoted fields follow in metadata.flat
+			}
+			u.collectUnsupported(field.Type, buildFieldPath(fieldPath, field.MapKey), visiting, unsupported)
+		}
+	case reflect.Map, reflect.Interface:
+	default:
+		*unsupported = append(*unsupported, NewUnsupportedTypeError(fieldPath, typ))
+	}
+}
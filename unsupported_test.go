@@ -0,0 +1,70 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_UnsupportedFields(t *testing.T) {
+	type Level string
+	type Base struct {
+		Level Level `schema:"level"`
+	}
+	type Node struct {
+		Name     string  `schema:"name"`
+		Children []*Node `schema:"children"`
+	}
+	type Config struct {
+		Base
+		Name     string                 `schema:"name"`
+		Created  time.Time              `schema:"created"`
+		Timeout  time.Duration          `schema:"timeout"`
+		Ratios   []complex64            `schema:"ratios"`
+		Levels   map[Level]struct{}     `schema:"levels"`
+		Extra    map[string]string      `schema:"extra"`
+		Any      any                    `schema:"any"`
+		Tree     *Node                  `schema:"tree"`
+		Nested   struct{ C complex128 } `schema:"nested"`
+		Settings map[string]any         `schema:"settings"`
+	}
+
+	type entry struct {
+		Path string
+		Type reflect.Type
+	}
+
+	var got []entry
+	for _, err := range NewDefaultUnmarshaler().UnsupportedFields(reflect.TypeFor[Config]()) {
+		got = append(got, entry{Path: err.FieldPath, Type: err.Type})
+	}
+
+	assert.Equal(t, []entry{
+		{Path: "level", Type: reflect.TypeFor[Level]()},
+		{Path: "timeout", Type: reflect.TypeFor[time.Duration]()},
+		{Path: "ratios[]", Type: reflect.TypeFor[complex64]()},
+		{Path: "levels[]", Type: reflect.TypeFor[Level]()},
+		{Path: "nested.C", Type: reflect.TypeFor[complex128]()},
+	}, got)
+
+	t.Run("converters make types supported", func(t *testing.T) {
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), NewDefaultConverterRegistry(map[reflect.Type]Converter{
+			reflect.TypeFor[time.Duration](): func(v any) (reflect.Value, error) {
+				return reflect.ValueOf(time.Duration(0)), nil
+			},
+		}))
+
+		for _, err := range u.UnsupportedFields(reflect.TypeFor[Config]()) {
+			assert.NotEqual(t, "timeout", err.FieldPath)
+		}
+	})
+
+	t.Run("top level", func(t *testing.T) {
+		unsupported := NewDefaultUnmarshaler().UnsupportedFields(reflect.TypeFor[Level]())
+		if assert.Len(t, unsupported, 1) {
+			assert.Equal(t, "root", unsupported[0].FieldPath)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package mapstructure
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// Validator checks invariants spanning several fields of a decoded struct,
+// such as start < end or exactly one of A and B being set.
+// It receives a pointer to the struct after its fields and defaults were decoded.
+type Validator func(structPtr any) error
+
+// ValidatorFor adapts a typed validation function to a Validator keyed by its type:
+//
+//	u = u.WithValidators(map[reflect.Type]Validator{
+//		reflect.TypeOf(Range{}): ValidatorFor(func(r *Range) error { ... }),
+//	})
+func ValidatorFor[T any](fn func(*T) error) Validator {
+	return func(structPtr any) error {
+		ptr, ok := structPtr.(*T)
+		if !ok {
+			return fmt.Errorf("validator for %v called with %T", reflect.TypeFor[T](), structPtr)
+		}
+
+		return fn(ptr)
+	}
+}
+
+// WithValidators returns a new unmarshaler extending u with the given validators,
+// keyed by the struct type they check. Validators run for every struct of that type
+// decoded from a map, including nested structs and slice elements. Their errors do not
+// stop decoding; all of them are returned together, as *StructValidationError values
+// joined with errors.Join, once decoding otherwise succeeded.
+// Validators replace any previously registered for the same type. u is left unchanged.
+func (u *Unmarshaler) WithValidators(validators map[reflect.Type]Validator) *Unmarshaler {
+	merged := maps.Clone(u.validators)
+	if merged == nil {
+		merged = make(map[reflect.Type]Validator, len(validators))
+	}
+	maps.Copy(merged, validators)
+
+	configured := *u
+	configured.validators = merged
+
+	return &configured
+}
+
+// validate runs the validator registered for the struct type of rv, if any,
+// and records its error in the call state.
+func (u *Unmarshaler) validate(rv reflect.Value, fieldPath string) {
+	validator, ok := u.validators[rv.Type()]
+	if !ok || u.state == nil || !rv.CanAddr() {
+		return
+	}
+
+	if err := validator(rv.Addr().Interface()); err != nil {
+		u.state.validationErrors = append(u.state.validationErrors, NewStructValidationError(fieldPath, rv.Type(), err))
+	}
+}
@@ -0,0 +1,102 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshaler_WithValidators(t *testing.T) {
+	type Window struct {
+		Start int `schema:"start"`
+		End   int `schema:"end" default:"{{.Start}}"`
+	}
+
+	type Schedule struct {
+		Name    string   `schema:"name"`
+		Email   string   `schema:"email"`
+		Phone   string   `schema:"phone"`
+		Windows []Window `schema:"windows"`
+	}
+
+	errEmptyWindow := errors.New("start must be before end")
+
+	base := NewDefaultUnmarshaler()
+	u := base.WithValidators(map[reflect.Type]Validator{
+		reflect.TypeOf(Window{}): ValidatorFor(func(w *Window) error {
+			if w.Start >= w.End {
+				return errEmptyWindow
+			}
+
+			return nil
+		}),
+		reflect.TypeOf(Schedule{}): ValidatorFor(func(s *Schedule) error {
+			if (s.Email == "") == (s.Phone == "") {
+				return errors.New("exactly one of email or phone must be set")
+			}
+
+			return nil
+		}),
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		data := map[string]any{
+			"email":   "a@example.com",
+			"windows": []any{map[string]any{"start": 1, "end": 2}},
+		}
+
+		var result Schedule
+		require.NoError(t, u.Unmarshal(data, &result))
+		assert.Equal(t, 2, result.Windows[0].End)
+	})
+
+	t.Run("errors are aggregated", func(t *testing.T) {
+		data := map[string]any{
+			"windows": []any{
+				map[string]any{"start": 1, "end": 2},
+				map[string]any{"start": 5, "end": 3},
+				map[string]any{"start": 7},
+			},
+		}
+
+		var result Schedule
+		err := u.Unmarshal(data, &result)
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, errEmptyWindow)
+		assert.Len(t, result.Windows, 3, "decoding completes despite validation errors")
+
+		var joined interface{ Unwrap() []error }
+		require.ErrorAs(t, err, &joined)
+
+		paths := make([]string, 0, len(joined.Unwrap()))
+		for _, e := range joined.Unwrap() {
+			var valErr *StructValidationError
+			require.ErrorAs(t, e, &valErr)
+			paths = append(paths, valErr.FieldPath)
+		}
+		assert.Equal(t, []string{"windows[1]", "windows[2]", "root"}, paths)
+	})
+
+	t.Run("decode errors take precedence", func(t *testing.T) {
+		var result Schedule
+		err := u.Unmarshal(map[string]any{"windows": "nope"}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+	})
+
+	t.Run("receiver is unchanged", func(t *testing.T) {
+		var result Schedule
+		require.NoError(t, base.Unmarshal(map[string]any{}, &result))
+	})
+
+	t.Run("validator type mismatch", func(t *testing.T) {
+		validator := ValidatorFor(func(*time.Time) error { return nil })
+		require.Error(t, validator(&Window{}))
+	})
+}
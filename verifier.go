@@ -0,0 +1,78 @@
+package mapstructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// Verifier checks a decoded struct against the raw input map it was decoded from, such
+// as an HMAC field over the canonicalized content of a signed payload, see CanonicalJSON.
+// It receives the input before transformers rewrote it and a pointer to the struct after
+// its fields and defaults were decoded.
+type Verifier func(input map[string]any, structPtr any) error
+
+// VerifierFor adapts a typed verification function to a Verifier keyed by its type:
+//
+//	u = u.WithVerifiers(map[reflect.Type]Verifier{
+//		reflect.TypeOf(Webhook{}): VerifierFor(func(input map[string]any, w *Webhook) error { ... }),
+//	})
+func VerifierFor[T any](fn func(input map[string]any, v *T) error) Verifier {
+	return func(input map[string]any, structPtr any) error {
+		ptr, ok := structPtr.(*T)
+		if !ok {
+			return fmt.Errorf("verifier for %v called with %T", reflect.TypeFor[T](), structPtr)
+		}
+
+		return fn(input, ptr)
+	}
+}
+
+// WithVerifiers returns a new unmarshaler extending u with the given verifiers, keyed by
+// the struct type they check. Verifiers run for every struct of that type decoded from a
+// map, including nested structs and slice elements, before validators. Unlike validator
+// errors, a verifier error stops decoding at once and is returned as a *VerificationError,
+// since the rest of an unverified payload cannot be trusted.
+// Verifiers replace any previously registered for the same type. u is left unchanged.
+func (u *Unmarshaler) WithVerifiers(verifiers map[reflect.Type]Verifier) *Unmarshaler {
+	merged := maps.Clone(u.verifiers)
+	if merged == nil {
+		merged = make(map[reflect.Type]Verifier, len(verifiers))
+	}
+	maps.Copy(merged, verifiers)
+
+	configured := *u
+	configured.verifiers = merged
+
+	return &configured
+}
+
+// verify runs the verifier registered for the struct type of rv, if any, with input.
+func (u *Unmarshaler) verify(input map[string]any, rv reflect.Value, fieldPath string) error {
+	verifier, ok := u.verifiers[rv.Type()]
+	if !ok || !rv.CanAddr() {
+		return nil
+	}
+
+	if err := verifier(input, rv.Addr().Interface()); err != nil {
+		return NewVerificationError(fieldPath, rv.Type(), err)
+	}
+
+	return nil
+}
+
+// CanonicalJSON returns the canonical encoding of input without the keys in omit, such as
+// the signature field itself, for computing or checking signatures in a Verifier: JSON
+// with map keys sorted at every level and no insignificant whitespace, as produced by
+// encoding/json. input is not modified.
+func CanonicalJSON(input map[string]any, omit ...string) ([]byte, error) {
+	if len(omit) > 0 {
+		input = maps.Clone(input)
+		for _, key := range omit {
+			delete(input, key)
+		}
+	}
+
+	return json.Marshal(input)
+}
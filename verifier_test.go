@@ -0,0 +1,162 @@
+package mapstructure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSigningKey = []byte("secret")
+
+type signedEvent struct {
+	ID        string `schema:"id"`
+	Amount    int    `schema:"amount"`
+	Currency  string `schema:"currency" default:"EUR"`
+	Signature string `schema:"signature"`
+}
+
+func sign(t *testing.T, input map[string]any) string {
+	t.Helper()
+
+	content, err := CanonicalJSON(input, "signature")
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, testSigningKey)
+	mac.Write(content)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var verifySignedEvent = VerifierFor(func(input map[string]any, e *signedEvent) error {
+	content, err := CanonicalJSON(input, "signature")
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, testSigningKey)
+	mac.Write(content)
+
+	got, err := hex.DecodeString(e.Signature)
+	if err != nil || !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+})
+
+func TestUnmarshaler_WithVerifiers(t *testing.T) {
+	type Batch struct {
+		Events []signedEvent `schema:"events"`
+	}
+
+	u := NewDefaultUnmarshaler().WithVerifiers(map[reflect.Type]Verifier{
+		reflect.TypeFor[signedEvent](): verifySignedEvent,
+	})
+
+	event := map[string]any{"id": "evt_1", "amount": 100}
+	event["signature"] = sign(t, event)
+
+	t.Run("valid signature", func(t *testing.T) {
+		var got signedEvent
+		require.NoError(t, u.Unmarshal(event, &got))
+		assert.Equal(t, "evt_1", got.ID)
+		assert.Equal(t, "EUR", got.Currency, "defaults are not part of the signed input")
+	})
+
+	t.Run("tampered input", func(t *testing.T) {
+		tampered := map[string]any{"id": "evt_1", "amount": 1000, "signature": event["signature"]}
+
+		var got signedEvent
+		err := u.Unmarshal(tampered, &got)
+		var verificationErr *VerificationError
+		require.ErrorAs(t, err, &verificationErr)
+		assert.Equal(t, "root", verificationErr.FieldPath)
+		assert.Equal(t, reflect.TypeFor[signedEvent](), verificationErr.Type)
+		assert.EqualError(t, err, "root: verification failed: signature mismatch")
+	})
+
+	t.Run("nested elements", func(t *testing.T) {
+		forged := map[string]any{"id": "evt_2", "amount": 5, "signature": "00"}
+
+		var got Batch
+		err := u.Unmarshal(map[string]any{"events": []any{event, forged}}, &got)
+		var verificationErr *VerificationError
+		require.ErrorAs(t, err, &verificationErr)
+		assert.Equal(t, "events[1]", verificationErr.FieldPath)
+	})
+
+	t.Run("input before transformers", func(t *testing.T) {
+		var seen map[string]any
+		transformed := NewDefaultUnmarshaler().WithTransformers(map[reflect.Type]Transformer{
+			reflect.TypeFor[signedEvent](): func(data map[string]any) map[string]any {
+				rewritten := make(map[string]any, len(data))
+				for k, v := range data {
+					rewritten[strings.ToLower(k)] = v
+				}
+
+				return rewritten
+			},
+		}).WithVerifiers(map[reflect.Type]Verifier{
+			reflect.TypeFor[signedEvent](): func(input map[string]any, _ any) error {
+				seen = input
+
+				return nil
+			},
+		})
+
+		var got signedEvent
+		require.NoError(t, transformed.Unmarshal(map[string]any{"ID": "evt_3"}, &got))
+		assert.Equal(t, "evt_3", got.ID)
+		assert.Equal(t, map[string]any{"ID": "evt_3"}, seen)
+	})
+
+	t.Run("string maps", func(t *testing.T) {
+		var got signedEvent
+		err := u.UnmarshalStrings(map[string]string{"id": "evt_1", "amount": "100", "signature": "00"}, &got)
+		require.ErrorAs(t, err, new(*VerificationError))
+	})
+
+	t.Run("verifiers run before validators", func(t *testing.T) {
+		var calls []string
+		ordered := NewDefaultUnmarshaler().WithVerifiers(map[reflect.Type]Verifier{
+			reflect.TypeFor[signedEvent](): func(map[string]any, any) error {
+				calls = append(calls, "verify")
+
+				return nil
+			},
+		}).WithValidators(map[reflect.Type]Validator{
+			reflect.TypeFor[signedEvent](): func(any) error {
+				calls = append(calls, "validate")
+
+				return nil
+			},
+		})
+
+		var got signedEvent
+		require.NoError(t, ordered.Unmarshal(map[string]any{"id": "x"}, &got))
+		assert.Equal(t, []string{"verify", "validate"}, calls)
+		assert.Equal(t, 1, ordered.Config().Verifiers)
+	})
+}
+
+func TestVerifierFor_WrongType(t *testing.T) {
+	err := verifySignedEvent(nil, &struct{}{})
+	assert.EqualError(t, err, "verifier for mapstructure.signedEvent called with *struct {}")
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	input := map[string]any{"b": 1, "a": map[string]any{"z": true, "y": []any{"x"}}, "signature": "abc"}
+
+	got, err := CanonicalJSON(input, "signature")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"y":["x"],"z":true},"b":1}`, string(got))
+	assert.Contains(t, input, "signature", "input unchanged")
+
+	_, err = CanonicalJSON(map[string]any{"ch": make(chan int)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package mapstructure
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// ErrInputMutated is returned by Unmarshal with input verification enabled when the
+// input data changed during decoding, typically because a converter, transformer or
+// default function modified shared input in place.
+var ErrInputMutated = errors.New("input data was mutated during decoding")
+
+// WithInputVerification enables a debug mode in which Unmarshal records a hash of the
+// input before decoding and verifies it afterwards, failing with ErrInputMutated when
+// the input was modified. Hashing walks the whole input, so keep it out of hot paths.
+func WithInputVerification() Option {
+	return func(u *Unmarshaler) {
+		u.verifyInput = true
+	}
+}
+
+// verifyInputUnchanged runs decode, failing with ErrInputMutated when data changed during it.
+func verifyInputUnchanged(data map[string]any, decode func() error) error {
+	before := HashInput(data)
+	err := decode()
+	if HashInput(data) != before {
+		return errors.Join(err, ErrInputMutated)
+	}
+
+	return err
+}
+
+// HashInput returns a hash of the contents of data. Equal contents hash equally
+// regardless of map iteration order; cyclic references are hashed up to the cycle.
+func HashInput(data map[string]any) uint64 {
+	h := &inputHasher{seen: make(map[copyKey]struct{})}
+	h.value(reflect.ValueOf(data))
+
+	return h.sum
+}
+
+// inputHasher accumulates a 64-bit FNV-1a hash over a value graph.
+type inputHasher struct {
+	sum  uint64
+	seen map[copyKey]struct{}
+}
+
+// mix folds n into the running hash.
+func (h *inputHasher) mix(n uint64) {
+	f := fnv.New64a()
+	var b [16]byte
+	for i := range 8 {
+		b[i] = byte(h.sum >> (8 * i))
+		b[8+i] = byte(n >> (8 * i))
+	}
+	_, _ = f.Write(b[:])
+	h.sum = f.Sum64()
+}
+
+// mixString folds s into the running hash.
+func (h *inputHasher) mixString(s string) {
+	f := fnv.New64a()
+	_, _ = f.Write([]byte(s))
+	h.mix(uint64(len(s)))
+	h.mix(f.Sum64())
+}
+
+// enter marks the reference v as being hashed and returns the function that unmarks
+// it, or nil when v is already being hashed further up (a cycle).
+func (h *inputHasher) enter(v reflect.Value) func() {
+	key := copyKey{ptr: v.Pointer(), typ: v.Type()}
+	if _, ok := h.seen[key]; ok {
+		return nil
+	}
+	h.seen[key] = struct{}{}
+
+	return func() { delete(h.seen, key) }
+}
+
+// value folds v into the running hash.
+func (h *inputHasher) value(v reflect.Value) {
+	if !v.IsValid() {
+		h.mix(0)
+
+		return
+	}
+	h.mix(uint64(v.Kind()))
+	h.mixString(v.Type().String())
+
+	//nolint:exhaustive // Remaining kinds are hashed by identity below
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.mix(1)
+		} else {
+			h.mix(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.mix(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.mix(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.mix(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		h.mix(math.Float64bits(real(c)))
+		h.mix(math.Float64bits(imag(c)))
+	case reflect.String:
+		h.mixString(v.String())
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			h.mix(0)
+
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			leave := h.enter(v)
+			if leave == nil {
+				return
+			}
+			defer leave()
+		}
+		h.value(v.Elem())
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		leave := h.enter(v)
+		if leave == nil {
+			return
+		}
+		defer leave()
+		// Entries are hashed separately and summed, so iteration order does not matter.
+		var entries uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := &inputHasher{seen: h.seen}
+			entry.value(iter.Key())
+			entry.value(iter.Value())
+			entries += entry.sum
+		}
+		h.mix(uint64(v.Len()))
+		h.mix(entries)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		h.mix(uint64(v.Len()))
+		if v.Len() == 0 {
+			return
+		}
+		leave := h.enter(v)
+		if leave == nil {
+			return
+		}
+		defer leave()
+		for i := range v.Len() {
+			h.value(v.Index(i))
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			h.value(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			h.value(v.Field(i))
+		}
+	default:
+		// Funcs, channels and unsafe pointers are compared by identity.
+		h.mix(uint64(v.Pointer()))
+	}
+}
@@ -0,0 +1,126 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashInput(t *testing.T) {
+	cyclic := map[string]any{"name": "a"}
+	cyclic["self"] = cyclic
+
+	shared := []any{1, 2}
+
+	tests := []struct {
+		name  string
+		a, b  map[string]any
+		equal bool
+	}{
+		{
+			name:  "equal scalars",
+			a:     map[string]any{"a": 1, "b": "x", "c": true, "d": 1.5},
+			b:     map[string]any{"d": 1.5, "c": true, "b": "x", "a": 1},
+			equal: true,
+		},
+		{
+			name:  "nested containers",
+			a:     map[string]any{"a": []any{map[string]any{"k": []int{1, 2}}}},
+			b:     map[string]any{"a": []any{map[string]any{"k": []int{1, 2}}}},
+			equal: true,
+		},
+		{
+			name:  "shared references",
+			a:     map[string]any{"x": shared, "y": shared},
+			b:     map[string]any{"x": []any{1, 2}, "y": []any{1, 2}},
+			equal: true,
+		},
+		{
+			name:  "cycles",
+			a:     cyclic,
+			b:     cyclic,
+			equal: true,
+		},
+		{
+			name: "different value",
+			a:    map[string]any{"a": 1},
+			b:    map[string]any{"a": 2},
+		},
+		{
+			name: "different type",
+			a:    map[string]any{"a": 1},
+			b:    map[string]any{"a": int64(1)},
+		},
+		{
+			name: "swapped values",
+			a:    map[string]any{"a": "x", "b": "y"},
+			b:    map[string]any{"a": "y", "b": "x"},
+		},
+		{
+			name: "nested change",
+			a:    map[string]any{"a": []any{map[string]any{"k": "v"}}},
+			b:    map[string]any{"a": []any{map[string]any{"k": "w"}}},
+		},
+		{
+			name: "nil versus empty",
+			a:    map[string]any{"a": nil},
+			b:    map[string]any{"a": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.equal {
+				assert.Equal(t, HashInput(tt.a), HashInput(tt.b))
+			} else {
+				assert.NotEqual(t, HashInput(tt.a), HashInput(tt.b))
+			}
+		})
+	}
+}
+
+func TestUnmarshaler_Unmarshal_InputVerification(t *testing.T) {
+	type Config struct {
+		Name string   `schema:"name"`
+		Tags []string `schema:"tags"`
+	}
+
+	// Converter that destructively normalizes the shared input slice.
+	mutating := NewDefaultConverterRegistry(map[reflect.Type]Converter{
+		reflect.TypeFor[[]string](): func(value any) (reflect.Value, error) {
+			//nolint:forcetypeassert // Test code
+			tags := value.([]any)
+			for i := range tags {
+				tags[i] = "mutated"
+			}
+
+			return reflect.ValueOf([]string{"ok"}), nil
+		},
+	})
+
+	t.Run("unchanged input", func(t *testing.T) {
+		u := NewDefaultUnmarshaler(WithInputVerification())
+
+		var result Config
+		err := u.Unmarshal(map[string]any{"name": "app", "tags": []any{"a"}}, &result)
+		require.NoError(t, err)
+		assert.Equal(t, Config{Name: "app", Tags: []string{"a"}}, result)
+	})
+
+	t.Run("mutated input", func(t *testing.T) {
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), mutating, WithInputVerification())
+
+		var result Config
+		err := u.Unmarshal(map[string]any{"name": "app", "tags": []any{"a"}}, &result)
+		require.ErrorIs(t, err, ErrInputMutated)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		u := NewUnmarshaler(NewDefaultStructMetadataCache(), mutating)
+
+		var result Config
+		require.NoError(t, u.Unmarshal(map[string]any{"name": "app", "tags": []any{"a"}}, &result))
+	})
+}
@@ -0,0 +1,34 @@
+package mapstructure
+
+import "reflect"
+
+// WithBytesAsStrings makes the unmarshaler decode []byte input like the string holding
+// the same bytes, for generic maps from codecs such as msgpack or gob that produce byte
+// slices for strings. Numbers of any size (int8, uint32...) are accepted regardless.
+//
+// Only targets that hold a single value are affected: scalars and structs, such as
+// time.Time, decoded by their converters, and slices with the "split" tag option.
+// Byte slices still fill other slices, arrays, maps and interfaces as they are, so
+// []byte and net.IP fields receive the raw bytes, and fields of type any keep the []byte.
+func WithBytesAsStrings() Option {
+	return func(u *Unmarshaler) {
+		u.bytesAsStrings = true
+	}
+}
+
+// bytesAsString returns the string for data when it is a []byte decoded into a value
+// of kind, see WithBytesAsStrings, and data otherwise.
+func bytesAsString(data any, kind reflect.Kind) any {
+	b, ok := data.([]byte)
+	if !ok {
+		return data
+	}
+
+	//nolint:exhaustive // Only kinds holding several values or any value keep bytes
+	switch kind {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface, reflect.Ptr:
+		return data
+	default:
+		return string(b)
+	}
+}
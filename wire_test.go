@@ -0,0 +1,122 @@
+package mapstructure
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wireTarget struct {
+	Count   int        `schema:"count"`
+	Size    int64      `schema:"size,format=bytes"`
+	Ratio   float32    `schema:"ratio"`
+	Enabled bool       `schema:"enabled"`
+	Name    string     `schema:"name"`
+	Level   *uint8     `schema:"level"`
+	At      time.Time  `schema:"at"`
+	Day     Date       `schema:"day"`
+	Addr    netip.Addr `schema:"addr"`
+	Tags    []string   `schema:"tags,split"`
+	Names   []string   `schema:"names"`
+	Raw     []byte     `schema:"raw"`
+	IP      net.IP     `schema:"ip"`
+	Extra   any        `schema:"extra"`
+}
+
+// msgpackInput mimics the generic output of msgpack decoders: the narrowest integer
+// types and byte slices for strings.
+func msgpackInput() map[string]any {
+	return map[string]any{
+		"count":   []byte("42"),
+		"size":    []byte("2KiB"),
+		"ratio":   []byte("0.5"),
+		"enabled": []byte("true"),
+		"name":    []byte("app"),
+		"level":   []byte("3"),
+		"at":      []byte("2024-05-01T10:00:00Z"),
+		"day":     []byte("2024-05-02"),
+		"addr":    []byte("10.0.0.1"),
+		"tags":    []byte("a,b"),
+		"names":   []any{[]byte("x"), []byte("y")},
+		"raw":     []byte{0, 1},
+		"ip":      []byte{10, 0, 0, 2},
+		"extra":   []byte("kept"),
+	}
+}
+
+func TestUnmarshaler_Unmarshal_BytesAsStrings(t *testing.T) {
+	t.Run("decoded like strings", func(t *testing.T) {
+		var result wireTarget
+		require.NoError(t, NewDefaultUnmarshaler(WithBytesAsStrings()).Unmarshal(msgpackInput(), &result))
+
+		level := uint8(3)
+		assert.Equal(t, wireTarget{
+			Count:   42,
+			Size:    2048,
+			Ratio:   0.5,
+			Enabled: true,
+			Name:    "app",
+			Level:   &level,
+			At:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			Day:     Date{Year: 2024, Month: time.May, Day: 2},
+			Addr:    netip.MustParseAddr("10.0.0.1"),
+			Tags:    []string{"a", "b"},
+			Names:   []string{"x", "y"},
+			Raw:     []byte{0, 1},
+			IP:      net.IP{10, 0, 0, 2},
+			Extra:   []byte("kept"),
+		}, result)
+	})
+
+	t.Run("rejected by default", func(t *testing.T) {
+		var result wireTarget
+		err := NewDefaultUnmarshaler().Unmarshal(map[string]any{"count": []byte("42")}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "count", convErr.FieldPath)
+	})
+
+	t.Run("invalid text", func(t *testing.T) {
+		var result wireTarget
+		err := NewDefaultUnmarshaler(WithBytesAsStrings()).Unmarshal(map[string]any{"count": []byte("many")}, &result)
+
+		var convErr *ConversionError
+		require.ErrorAs(t, err, &convErr)
+		assert.Equal(t, "many", convErr.Value, "errors report the string")
+	})
+}
+
+func TestUnmarshaler_Unmarshal_NarrowNumbers(t *testing.T) {
+	var result struct {
+		Count int       `schema:"count"`
+		Total uint64    `schema:"total"`
+		Ratio float64   `schema:"ratio"`
+		On    bool      `schema:"on"`
+		Name  string    `schema:"name"`
+		At    time.Time `schema:"at,unit=s"`
+		Size  int64     `schema:"size,format=bytes"`
+	}
+	data := map[string]any{
+		"count": int8(-5),
+		"total": uint32(70000),
+		"ratio": float32(0.25),
+		"on":    uint8(1),
+		"name":  int16(12),
+		"at":    uint32(1700000000),
+		"size":  uint16(512),
+	}
+
+	require.NoError(t, NewDefaultUnmarshaler().Unmarshal(data, &result))
+	assert.Equal(t, -5, result.Count)
+	assert.Equal(t, uint64(70000), result.Total)
+	assert.InDelta(t, 0.25, result.Ratio, 0)
+	assert.True(t, result.On)
+	assert.Equal(t, "12", result.Name)
+	assert.Equal(t, int64(1700000000), result.At.Unix())
+	assert.Equal(t, int64(512), result.Size)
+}
@@ -0,0 +1,99 @@
+package mapstructure
+
+import "reflect"
+
+// MarshalOption configures a Marshaler.
+type MarshalOption func(*Marshaler)
+
+// CollectionPolicy selects how a Marshaler emits nil and empty slices and maps, sets included.
+type CollectionPolicy int
+
+const (
+	// CollectionsAsIs emits nil slices and maps as nil and empty ones as empty []any or
+	// map[string]any. This is the default.
+	CollectionsAsIs CollectionPolicy = iota
+
+	// CollectionsNilAsEmpty emits nil slices and maps as empty ones, for contracts that
+	// expect [] or {} rather than null. It applies at any depth, e.g. to slice elements.
+	CollectionsNilAsEmpty
+
+	// CollectionsEmptyAsNil emits empty slices and maps as nil, like nil ones.
+	// It applies at any depth, e.g. to slice elements.
+	CollectionsEmptyAsNil
+
+	// CollectionsOmitEmpty leaves struct fields holding a nil or empty slice or map out.
+	CollectionsOmitEmpty
+)
+
+// ZeroPolicy selects how a Marshaler emits struct fields holding zero values, by kind.
+// It applies to every field, in addition to the omitempty tag option of single fields.
+// The zero ZeroPolicy emits all fields as they are.
+type ZeroPolicy struct {
+	OmitZeroScalars bool             // Leave out bools, numbers and strings holding their zero value
+	OmitNilPointers bool             // Leave out nil pointers
+	Collections     CollectionPolicy // Emission of nil and empty slices and maps
+}
+
+// WithZeroPolicy sets how the marshaler emits fields holding zero values, e.g. to leave
+// out the fields a downstream API defaults when absent.
+func WithZeroPolicy(policy ZeroPolicy) MarshalOption {
+	return func(m *Marshaler) {
+		m.zeroPolicy = policy
+	}
+}
+
+// With returns a new marshaler derived from m with opts applied on top of its settings.
+// The derived marshaler shares the metadata cache of m. m is left unchanged.
+func (m *Marshaler) With(opts ...MarshalOption) *Marshaler {
+	derived := *m
+	for _, opt := range opts {
+		opt(&derived)
+	}
+
+	return &derived
+}
+
+// omitZero reports whether the zero policy of m leaves out a field holding rv.
+func (m *Marshaler) omitZero(rv reflect.Value) bool {
+	policy := m.zeroPolicy
+
+	//nolint:exhaustive // Other kinds are always emitted
+	switch rv.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		return policy.OmitZeroScalars && rv.IsZero()
+	case reflect.Ptr:
+		return policy.OmitNilPointers && rv.IsNil()
+	case reflect.Slice, reflect.Map:
+		return policy.Collections == CollectionsOmitEmpty && rv.Len() == 0
+	default:
+		return false
+	}
+}
+
+// emptyCollection returns the value the collection policy of m emits for the nil or
+// empty slice or map rv, and false when rv is emitted as usual.
+func (m *Marshaler) emptyCollection(rv reflect.Value) (any, bool) {
+	switch m.zeroPolicy.Collections {
+	case CollectionsNilAsEmpty:
+		if !rv.IsNil() {
+			return nil, false
+		}
+	case CollectionsEmptyAsNil:
+		return nil, true
+	default:
+		return nil, false
+	}
+
+	switch {
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return reflect.MakeSlice(rv.Type(), 0, 0).Interface(), true
+	case rv.Kind() == reflect.Slice || isSetType(rv.Type()):
+		return []any{}, true
+	case rv.Type().Key().Kind() == reflect.String:
+		return map[string]any{}, true
+	default:
+		return reflect.MakeMap(rv.Type()).Interface(), true
+	}
+}
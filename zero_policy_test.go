@@ -0,0 +1,105 @@
+package mapstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshaler_WithZeroPolicy(t *testing.T) {
+	type Item struct {
+		Tags []string `schema:"tags"`
+	}
+	type Payload struct {
+		Name   string              `schema:"name"`
+		Count  int                 `schema:"count"`
+		Active bool                `schema:"active"`
+		Parent *Item               `schema:"parent"`
+		Tags   []string            `schema:"tags"`
+		Labels map[string]string   `schema:"labels"`
+		IDs    map[int]struct{}    `schema:"ids"`
+		Data   []byte              `schema:"data"`
+		Items  []Item              `schema:"items"`
+		Note   string              `schema:"note,omitempty"`
+		Meta   map[string]struct{} `schema:"meta"`
+	}
+
+	payload := Payload{Labels: map[string]string{}, Items: []Item{{}, {Tags: []string{}}}}
+
+	tests := []struct {
+		name   string
+		policy ZeroPolicy
+		want   map[string]any
+	}{
+		{
+			name:   "default emits everything",
+			policy: ZeroPolicy{},
+			want: map[string]any{
+				"name": "", "count": 0, "active": false, "parent": nil, "tags": nil,
+				"labels": map[string]any{}, "ids": nil, "data": nil, "meta": nil,
+				"items": []any{map[string]any{"tags": nil}, map[string]any{"tags": []any{}}},
+			},
+		},
+		{
+			name:   "omit zero scalars and nil pointers",
+			policy: ZeroPolicy{OmitZeroScalars: true, OmitNilPointers: true},
+			want: map[string]any{
+				"tags": nil, "labels": map[string]any{}, "ids": nil, "data": nil, "meta": nil,
+				"items": []any{map[string]any{"tags": nil}, map[string]any{"tags": []any{}}},
+			},
+		},
+		{
+			name:   "nil collections as empty",
+			policy: ZeroPolicy{Collections: CollectionsNilAsEmpty},
+			want: map[string]any{
+				"name": "", "count": 0, "active": false, "parent": nil, "tags": []any{},
+				"labels": map[string]any{}, "ids": []any{}, "data": []byte{}, "meta": []any{},
+				"items": []any{map[string]any{"tags": []any{}}, map[string]any{"tags": []any{}}},
+			},
+		},
+		{
+			name:   "empty collections as nil",
+			policy: ZeroPolicy{Collections: CollectionsEmptyAsNil},
+			want: map[string]any{
+				"name": "", "count": 0, "active": false, "parent": nil, "tags": nil,
+				"labels": nil, "ids": nil, "data": nil, "meta": nil,
+				"items": []any{map[string]any{"tags": nil}, map[string]any{"tags": nil}},
+			},
+		},
+		{
+			name:   "omit empty collections",
+			policy: ZeroPolicy{Collections: CollectionsOmitEmpty},
+			want: map[string]any{
+				"name": "", "count": 0, "active": false, "parent": nil,
+				"items": []any{map[string]any{}, map[string]any{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDefaultMarshaler(WithZeroPolicy(tt.policy)).Marshal(payload)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMarshaler_With(t *testing.T) {
+	type Payload struct {
+		Name     string `schema:"name"`
+		Password string `schema:"password,secret"`
+	}
+
+	base := NewDefaultMarshaler().Redacted()
+	derived := base.With(WithZeroPolicy(ZeroPolicy{OmitZeroScalars: true}))
+
+	got, err := derived.Marshal(Payload{Password: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"password": RedactedValue}, got, "derived keeps redaction")
+
+	got, err = base.Marshal(Payload{Password: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "", "password": RedactedValue}, got, "base unchanged")
+}